@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -27,6 +28,7 @@ import (
 	"github.com/killakam3084/rss-curator/internal/settings"
 	"github.com/killakam3084/rss-curator/internal/storage"
 	"github.com/killakam3084/rss-curator/internal/suggester"
+	"github.com/killakam3084/rss-curator/internal/watchdir"
 	"github.com/killakam3084/rss-curator/pkg/models"
 )
 
@@ -42,13 +44,26 @@ func main() {
 
 	command := os.Args[1]
 
+	// explain owns stdout for its report (often piped to jq with --json), so
+	// its configuration banner goes to stderr.
+	var banner io.Writer = os.Stdout
+	if command == "explain" {
+		banner = os.Stderr
+	}
+
 	// Load config
-	cfg, err := loadConfig()
+	cfg, err := loadConfig(banner)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Only the commands that match feeds migrate; others must not write a
+	// watchlist into whatever directory they happen to run from.
+	if command == "serve" || command == "check" {
+		migrateLegacyRulesOnStartup(&cfg)
+	}
+
 	// Initialize storage
 	store, err := storage.New(cfg.StoragePath)
 	if err != nil {
@@ -87,13 +102,19 @@ func main() {
 	case "serve":
 		cmdServe(cfg, store, buf, metaLookup)
 	case "test":
-		cmdTest(cfg)
+		cmdTest(cfg, store)
 	case "resume":
 		cmdResume(cfg, store, os.Args[2:])
 	case "pause":
 		cmdPause(cfg, store, os.Args[2:])
 	case "cleanup":
 		cmdCleanup(store, os.Args[2:])
+	case "replay":
+		cmdReplay(cfg, store, os.Args[2:])
+	case "explain":
+		cmdExplain(cfg, os.Args[2:], os.Stdout)
+	case "migrate-rules":
+		cmdMigrateRules(cfg, os.Args[2:])
 	case "version":
 		fmt.Printf("rss-curator v%s\n", version)
 	default:
@@ -115,27 +136,33 @@ func cmdCheck(cfg models.Config, store *storage.Storage, metaLookup *metadata.Lo
 		fmt.Println("AI provider available — enrichment and scoring enabled")
 	}
 
-	var m *matcher.Matcher
-	if cfg.ShowsConfig != nil {
-		m = matcher.NewMatcher(cfg.ShowsConfig, nil)
-		fmt.Printf("Using shows.json config (%d shows configured)\n", len(cfg.ShowsConfig.Shows))
-	} else {
-		m = matcher.NewMatcher(nil, &cfg.MatchRules)
-		fmt.Println("Using environment variable config")
-	}
+	m := cliMatcher(cfg)
 
+	seedFeedRegistry(store, cfg.Feeds)
 	summary, err := ops.RunFeedCheck(context.Background(), ops.FeedCheckConfig{
-		Feeds:   cfg.Feeds,
-		Matcher: m,
+		Feeds:     cfg.Feeds,
+		LoadFeeds: ops.RegistryFeeds(store),
+		Sources:   watchSources(),
+		Matcher:   m,
 	}, ops.FeedCheckDeps{
-		Store:      store,
-		Enricher:   enricher,
-		Scorer:     scorer,
-		ScorerProv: scorerProvider,
+		Store:           store,
+		Enricher:        enricher,
+		Scorer:          scorer,
+		ScorerProv:      scorerProvider,
+		Parser:          captureParser(),
+		InspectTorrents: func() bool { return getEnv("CURATOR_INSPECT_TORRENTS", "false") == "true" },
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 	}
+	for _, fr := range summary.Feeds {
+		switch fr.Status {
+		case "failed":
+			fmt.Fprintf(os.Stderr, "Warning: feed %s failed: %s\n", fr.URL, fr.Error)
+		case "backoff":
+			fmt.Printf("Skipped %s (backing off after repeated failures)\n", fr.URL)
+		}
+	}
 
 	if summary.ItemsFound > 0 {
 		fmt.Printf("\n✓ Discovered %d items from RSS feeds\n", summary.ItemsFound)
@@ -151,12 +178,373 @@ func cmdCheck(cfg models.Config, store *storage.Storage, metaLookup *metadata.Lo
 	}
 }
 
+// cliMatcher builds the matcher used by one-shot commands from the watchlist
+// file, falling back to the environment-variable rules.
+func cliMatcher(cfg models.Config) *matcher.Matcher {
+	if cfg.ShowsConfig != nil {
+		fmt.Printf("Using shows.json config (%d shows configured)\n", len(cfg.ShowsConfig.Shows))
+	} else {
+		fmt.Println("Using environment variable config")
+	}
+	return newMatcher(cfg)
+}
+
+// newMatcher builds a matcher from the watchlist, or from the legacy
+// environment-variable rules when no watchlist is loaded.
+func newMatcher(cfg models.Config) *matcher.Matcher {
+	if cfg.ShowsConfig != nil {
+		return matcher.NewMatcher(cfg.ShowsConfig, nil)
+	}
+	return matcher.NewMatcher(nil, &cfg.MatchRules)
+}
+
+// captureParser returns a feed parser that records raw responses into
+// CURATOR_CAPTURE_DIR for `curator replay`, or nil (the default parser) when
+// the variable is unset.
+func captureParser() *feed.Parser {
+	dir := os.Getenv("CURATOR_CAPTURE_DIR")
+	if dir == "" {
+		return nil
+	}
+	fmt.Printf("[Config] CURATOR_CAPTURE_DIR: %s (raw feed responses are recorded)\n", dir)
+	return feed.NewParser().WithCapture(dir)
+}
+
+// watchSources returns the CURATOR_WATCH_DIR source, if configured.
+// CURATOR_WATCH_DIR_TYPE ("show" or "movie") pins the content type; by
+// default it is inferred per file.
+func watchSources() []feed.FeedSource {
+	dir := os.Getenv("CURATOR_WATCH_DIR")
+	if dir == "" {
+		return nil
+	}
+	ct := models.ContentType(os.Getenv("CURATOR_WATCH_DIR_TYPE"))
+	if ct != "" && ct != models.ContentTypeShow && ct != models.ContentTypeMovie {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring CURATOR_WATCH_DIR_TYPE=%q (want show or movie)\n", ct)
+		ct = ""
+	}
+	fmt.Printf("[Config] CURATOR_WATCH_DIR: %s\n", dir)
+	return []feed.FeedSource{watchdir.New(dir, ct)}
+}
+
+// replayArgs are the parsed arguments of `curator replay`.
+type replayArgs struct {
+	dir     string
+	scratch bool   // stage into a throwaway database
+	dbPath  string // stage into this database instead of STORAGE_PATH
+}
+
+func parseReplayArgs(args []string) (replayArgs, error) {
+	var ra replayArgs
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--scratch":
+			ra.scratch = true
+		case a == "--db":
+			if i+1 >= len(args) {
+				return ra, fmt.Errorf("--db requires a path")
+			}
+			i++
+			ra.dbPath = args[i]
+		case strings.HasPrefix(a, "--db="):
+			ra.dbPath = strings.TrimPrefix(a, "--db=")
+		case strings.HasPrefix(a, "-"):
+			return ra, fmt.Errorf("unknown flag %s", a)
+		case ra.dir == "":
+			ra.dir = a
+		default:
+			return ra, fmt.Errorf("unexpected argument %s", a)
+		}
+	}
+	if ra.dir == "" {
+		return ra, fmt.Errorf("capture directory required")
+	}
+	if ra.scratch && ra.dbPath != "" {
+		return ra, fmt.Errorf("--scratch and --db are mutually exclusive")
+	}
+	return ra, nil
+}
+
+// cmdReplay runs the feed-check pipeline (parse → match → dedup → stage)
+// against responses recorded with CURATOR_CAPTURE_DIR instead of the network.
+// AI enrichment/scoring, .torrent inspection and auto-queue are left off so a
+// replay is deterministic and never contacts a tracker, and the live feed
+// state (backoff, poll interval, validators, health) is bypassed.
+func cmdReplay(cfg models.Config, store *storage.Storage, args []string) {
+	ra, err := parseReplayArgs(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Usage: curator replay <dir> [--scratch | --db <path>]")
+		os.Exit(1)
+	}
+
+	captures, err := feed.LoadCaptures(ra.dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading captures: %v\n", err)
+		os.Exit(1)
+	}
+	if len(captures) == 0 {
+		fmt.Printf("No captures found in %s\n", ra.dir)
+		return
+	}
+
+	if ra.scratch {
+		tmpDir, err := os.MkdirTemp("", "curator-replay-*")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating scratch dir: %v\n", err)
+			os.Exit(1)
+		}
+		defer os.RemoveAll(tmpDir)
+		ra.dbPath = filepath.Join(tmpDir, "replay.db")
+	}
+	if ra.dbPath != "" {
+		replayStore, err := storage.New(ra.dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
+			os.Exit(1)
+		}
+		defer replayStore.Close()
+		store = replayStore
+		fmt.Printf("Replaying into %s\n", ra.dbPath)
+	}
+
+	m := cliMatcher(cfg)
+	rounds := feed.ReplayRounds(captures)
+	fmt.Printf("Replaying %d captures in %d round(s) from %s\n", len(captures), len(rounds), ra.dir)
+
+	var found, staged int
+	for i, round := range rounds {
+		feeds := make([]models.FeedConfig, 0, len(round))
+		for _, c := range round {
+			feeds = append(feeds, models.FeedConfig{URL: c.URL, ContentType: c.ContentType})
+		}
+		summary, err := ops.RunFeedCheck(context.Background(), ops.FeedCheckConfig{
+			Feeds:   feeds,
+			Matcher: m,
+			Replay:  true,
+		}, ops.FeedCheckDeps{
+			Store:  store,
+			Parser: feed.NewReplayParser(round),
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: round %d: %v\n", i+1, err)
+		}
+		for _, fr := range summary.Feeds {
+			if fr.Status == "failed" {
+				fmt.Fprintf(os.Stderr, "Warning: capture of %s failed to parse: %s\n", fr.URL, fr.Error)
+			}
+		}
+		found += summary.ItemsFound
+		staged += summary.ItemsMatched
+	}
+
+	fmt.Printf("\n✓ Replayed %d items, staged %d new torrents\n", found, staged)
+	if ra.scratch {
+		// The scratch DB is removed on exit, so show what would have been staged.
+		cmdListStatus(store, "pending")
+	}
+}
+
+// explainArgs are the parsed arguments of `curator explain`.
+type explainArgs struct {
+	title  string
+	movie  bool // parse and match as a movie instead of a show
+	asJSON bool // print the raw trace
+}
+
+func parseExplainArgs(args []string) (explainArgs, error) {
+	var ea explainArgs
+	var words []string
+	for _, a := range args {
+		switch {
+		case a == "--movie":
+			ea.movie = true
+		case a == "--json":
+			ea.asJSON = true
+		case strings.HasPrefix(a, "-"):
+			return ea, fmt.Errorf("unknown flag %s", a)
+		default:
+			words = append(words, a)
+		}
+	}
+	ea.title = strings.TrimSpace(strings.Join(words, " "))
+	if ea.title == "" {
+		return ea, fmt.Errorf("title required")
+	}
+	return ea, nil
+}
+
+// cmdMigrateRules converts the legacy env-var match rules (SHOW_NAMES,
+// MIN_QUALITY, PREFERRED_CODEC, EXCLUDE_GROUPS, PREFERRED_GROUPS) into
+// watchlist rules and merges them into the resolved watchlist path. With
+// --dry-run it only prints the diff.
+func cmdMigrateRules(cfg models.Config, args []string) {
+	dryRun := false
+	for _, a := range args {
+		switch a {
+		case "--dry-run", "-n":
+			dryRun = true
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown argument %s\n", a)
+			fmt.Fprintln(os.Stderr, "Usage: curator migrate-rules [--dry-run]")
+			os.Exit(1)
+		}
+	}
+	if len(cfg.MatchRules.ShowNames) == 0 {
+		fmt.Println("SHOW_NAMES is empty — no legacy rules to migrate")
+		return
+	}
+
+	path := resolveShowsPath()
+	res, err := ops.MigrateRules(ops.MigrateRulesConfig{
+		Legacy:    cfg.MatchRules,
+		ShowsPath: path,
+		DryRun:    dryRun,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if res.Diff == "" {
+		fmt.Printf("%s already covers the legacy rules — nothing to migrate\n", path)
+		return
+	}
+	from := path
+	if res.Created {
+		from = "/dev/null"
+	}
+	fmt.Printf("--- %s\n+++ %s (migrated)\n%s", from, path, res.Diff)
+	if dryRun {
+		fmt.Printf("\nDry run: %d show rule(s) would be added; re-run without --dry-run to write %s\n", res.ShowsAdded, path)
+		return
+	}
+	fmt.Printf("\n✓ Wrote %s (%d show rule(s) added)\n", path, res.ShowsAdded)
+	fmt.Println("The environment-variable match rules are no longer used and can be removed.")
+	fmt.Println("Restart a running 'curator serve' to match with the new watchlist.")
+}
+
+// migrateLegacyRulesOnStartup writes a watchlist converted from the legacy
+// env-var match rules when SHOW_NAMES is set and no watchlist file exists, so
+// existing installs move to watchlist rules once and for good. A watchlist
+// that exists but failed to load is left alone. Set
+// CURATOR_MIGRATE_LEGACY_RULES=false to keep running on the legacy rules.
+func migrateLegacyRulesOnStartup(cfg *models.Config) {
+	if cfg.ShowsConfig != nil || len(cfg.MatchRules.ShowNames) == 0 {
+		return
+	}
+	if getEnv("CURATOR_MIGRATE_LEGACY_RULES", "true") != "true" {
+		return
+	}
+	path := resolveShowsPath()
+	if _, err := os.Stat(path); err == nil {
+		return
+	}
+	res, err := ops.MigrateRules(ops.MigrateRulesConfig{Legacy: cfg.MatchRules, ShowsPath: path})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[Config] Warning: legacy rule migration failed: %v\n", err)
+		return
+	}
+	cfg.ShowsConfig = res.Config
+	fmt.Printf("[Config] Migrated %d legacy SHOW_NAMES rule(s) to %s; the environment-variable match rules are no longer used\n",
+		res.ShowsAdded, path)
+}
+
+// cmdExplain parses a release title and prints how every watchlist rule
+// treats it: the parsed fields, whether the name matched and each check with
+// its effective value and where that value came from.
+func cmdExplain(cfg models.Config, args []string, out io.Writer) {
+	ea, err := parseExplainArgs(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Usage: curator explain \"<title>\" [--movie] [--json]")
+		os.Exit(1)
+	}
+
+	contentType := models.ContentTypeShow
+	if ea.movie {
+		contentType = models.ContentTypeMovie
+	}
+	exp := ops.ExplainTitle(newMatcher(cfg), ea.title, contentType)
+
+	if ea.asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		enc.Encode(exp)
+		return
+	}
+
+	it := exp.Item
+	fmt.Fprintf(out, "\nTitle:    %s\n", it.Title)
+	fmt.Fprintf(out, "Parsed:   name=%q season=%d episode=%d quality=%s codec=%s source=%s group=%s hdr=%s\n",
+		it.ShowName, it.Season, it.Episode, orDash(it.Quality), orDash(it.Codec), orDash(it.Source),
+		orDash(it.ReleaseGroup), orDash(strings.Join(it.HDR, ",")))
+	if exp.Result.Matched {
+		fmt.Fprintf(out, "Result:   ✓ match (score %d) — %s\n", exp.Result.Score, exp.Result.Reason)
+	} else {
+		fmt.Fprintf(out, "Result:   ✗ no match — %s\n", exp.Result.Reason)
+	}
+
+	for _, rt := range exp.Rules {
+		if !rt.NameMatched {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s rule %q", rt.Kind, rt.Rule)
+		if rt.Profile != "" {
+			fmt.Fprintf(out, " (profile %s)", rt.Profile)
+		}
+		if rt.Via != "" {
+			fmt.Fprintf(out, " via %s", rt.Via)
+		}
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "  CHECK\tITEM\tEFFECTIVE\tFROM\tRESULT\n")
+		for _, c := range rt.Checks {
+			result := "pass"
+			switch {
+			case c.Effect == "prefer" && c.Passed:
+				result = "preferred"
+			case c.Effect == "prefer":
+				result = "-"
+			case !c.Passed:
+				result = "FAIL"
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", c.Check, orDash(c.Value), orDash(c.Effective), orDash(c.Source), result)
+		}
+		w.Flush()
+		if rt.Result != nil {
+			fmt.Fprintf(out, "  → %s\n", rt.Result.Reason)
+		}
+	}
+
+	unmatched := 0
+	for _, rt := range exp.Rules {
+		if !rt.NameMatched {
+			unmatched++
+		}
+	}
+	if unmatched > 0 {
+		fmt.Fprintf(out, "\n%d other rule(s) did not match the name (use --json for their traces)\n", unmatched)
+	}
+}
+
+// orDash renders an empty string as "-" in tabular output.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func cmdList(store *storage.Storage) {
 	status := "pending"
 	if len(os.Args) > 2 {
 		status = os.Args[2]
 	}
+	cmdListStatus(store, status)
+}
 
+// cmdListStatus prints the staged torrents with the given status as a table.
+func cmdListStatus(store *storage.Storage, status string) {
 	torrents, err := store.List(status, "", "")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error listing torrents: %v\n", err)
@@ -221,7 +609,7 @@ func cmdApprove(cfg models.Config, store *storage.Storage, args []string) {
 
 		// Add to qBittorrent
 		fmt.Printf("Adding: %s\n", torrent.FeedItem.Title)
-		err = qb.AddTorrent(torrent.FeedItem.Link, nil)
+		err = qb.AddTorrentWithAuth(torrent.FeedItem.Link, nil, ops.FeedAuthFor(store, torrent.FeedItem.FeedURL))
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error adding torrent: %v\n", err)
 			continue
@@ -290,7 +678,7 @@ func cmdReview(cfg models.Config, store *storage.Storage) {
 		response = strings.ToLower(strings.TrimSpace(response))
 		switch response {
 		case "a", "approve":
-			if err := qb.AddTorrent(t.FeedItem.Link, nil); err != nil {
+			if err := qb.AddTorrentWithAuth(t.FeedItem.Link, nil, ops.FeedAuthFor(store, t.FeedItem.FeedURL)); err != nil {
 				fmt.Fprintf(os.Stderr, "Error adding torrent: %v\n", err)
 			} else {
 				store.UpdateStatus(t.ID, "approved")
@@ -309,7 +697,7 @@ func cmdReview(cfg models.Config, store *storage.Storage) {
 	fmt.Println("\nReview complete!")
 }
 
-func cmdTest(cfg models.Config) {
+func cmdTest(cfg models.Config, store *storage.Storage) {
 	fmt.Println("Testing connections...")
 
 	// Test qBittorrent
@@ -325,15 +713,19 @@ func cmdTest(cfg models.Config) {
 		fmt.Printf("  Active torrents: %d\n", len(torrents))
 	}
 
-	// Test RSS feeds
+	// Test RSS feeds — registry feeds when any are registered, else env feeds.
+	feeds := cfg.Feeds
+	if registered, err := ops.RegistryFeeds(store)(); err == nil && len(registered) > 0 {
+		feeds = registered
+	}
 	parser := feed.NewParser()
-	for i, fc := range cfg.Feeds {
+	for i, fc := range feeds {
 		fmt.Printf("RSS feed %d (%s)... ", i+1, fc.ContentType)
-		items, err := parser.Parse(fc.URL, fc.ContentType)
+		res, err := parser.ParseConditional(fc.URL, fc.ContentType, feed.FetchOptions{Auth: fc.Auth()})
 		if err != nil {
 			fmt.Printf("✗ Failed: %v\n", err)
 		} else {
-			fmt.Printf("✓ OK (%d items)\n", len(items))
+			fmt.Printf("✓ OK (%d items)\n", len(res.Items))
 		}
 	}
 
@@ -350,7 +742,22 @@ func cmdTest(cfg models.Config) {
 	}
 }
 
-func loadConfig() (models.Config, error) {
+// seedFeedRegistry copies env-configured feeds into an empty feed registry so
+// upgrades keep polling the same feeds. Errors are reported but not fatal.
+func seedFeedRegistry(store *storage.Storage, feeds []models.FeedConfig) {
+	n, err := ops.SeedFeedRegistry(store, feeds)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not seed feed registry: %v\n", err)
+		return
+	}
+	if n > 0 {
+		fmt.Printf("[Config] Seeded feed registry with %d feed(s) from environment\n", n)
+	}
+}
+
+// loadConfig reads the configuration from the environment and the watchlist
+// file, logging what it loaded to out.
+func loadConfig(out io.Writer) (models.Config, error) {
 	homeDir, _ := os.UserHomeDir()
 
 	cfg := models.Config{
@@ -380,30 +787,31 @@ func loadConfig() (models.Config, error) {
 	}
 
 	// Log all configured options at startup
-	fmt.Println("\n========== Configuration Loaded ==========")
-	fmt.Printf("[Config] RSS_FEED_URL: %s\n", cfg.FeedURLs[0])
-	fmt.Printf("[Config] RSS_MOVIE_FEED_URL: %s\n", cfg.MovieFeedURLs[0])
-	fmt.Printf("[Config] POLL_INTERVAL: %d seconds\n", cfg.PollInterval)
-	fmt.Println("\n--- QBittorrent Settings ---")
-	fmt.Printf("[Config] QBITTORRENT_HOST: %s\n", cfg.QBittorrent.Host)
-	fmt.Printf("[Config] QBITTORRENT_USER: %s\n", cfg.QBittorrent.Username)
-	fmt.Printf("[Config] QBITTORRENT_CATEGORY: %s\n", cfg.QBittorrent.Category)
-	fmt.Printf("[Config] QBITTORRENT_SAVEPATH: %s\n", cfg.QBittorrent.SavePath)
-	fmt.Printf("[Config] QBITTORRENT_ADD_PAUSED (env): %s\n", os.Getenv("QBITTORRENT_ADD_PAUSED"))
-	fmt.Printf("[Config] QBITTORRENT_ADD_PAUSED (parsed): %v\n", cfg.QBittorrent.AddPaused)
-	fmt.Println("\n--- Match Rules ---")
-	fmt.Printf("[Config] SHOW_NAMES: %v\n", cfg.MatchRules.ShowNames)
-	fmt.Printf("[Config] MIN_QUALITY: %s\n", cfg.MatchRules.MinQuality)
-	fmt.Printf("[Config] PREFERRED_CODEC: %s\n", cfg.MatchRules.PreferredCodec)
-	fmt.Printf("[Config] EXCLUDE_GROUPS: %v\n", cfg.MatchRules.ExcludeGroups)
-	fmt.Printf("[Config] PREFERRED_GROUPS: %v\n", cfg.MatchRules.PreferredGroups)
-	fmt.Println("\n--- Storage ---")
-	fmt.Printf("[Config] STORAGE_PATH: %s\n", cfg.StoragePath)
-	fmt.Println("==========================================")
-
-	// Validate required fields
+	fmt.Fprintln(out, "\n========== Configuration Loaded ==========")
+	fmt.Fprintf(out, "[Config] RSS_FEED_URL: %s\n", cfg.FeedURLs[0])
+	fmt.Fprintf(out, "[Config] RSS_MOVIE_FEED_URL: %s\n", cfg.MovieFeedURLs[0])
+	fmt.Fprintf(out, "[Config] POLL_INTERVAL: %d seconds\n", cfg.PollInterval)
+	fmt.Fprintln(out, "\n--- QBittorrent Settings ---")
+	fmt.Fprintf(out, "[Config] QBITTORRENT_HOST: %s\n", cfg.QBittorrent.Host)
+	fmt.Fprintf(out, "[Config] QBITTORRENT_USER: %s\n", cfg.QBittorrent.Username)
+	fmt.Fprintf(out, "[Config] QBITTORRENT_CATEGORY: %s\n", cfg.QBittorrent.Category)
+	fmt.Fprintf(out, "[Config] QBITTORRENT_SAVEPATH: %s\n", cfg.QBittorrent.SavePath)
+	fmt.Fprintf(out, "[Config] QBITTORRENT_ADD_PAUSED (env): %s\n", os.Getenv("QBITTORRENT_ADD_PAUSED"))
+	fmt.Fprintf(out, "[Config] QBITTORRENT_ADD_PAUSED (parsed): %v\n", cfg.QBittorrent.AddPaused)
+	fmt.Fprintln(out, "\n--- Match Rules ---")
+	fmt.Fprintf(out, "[Config] SHOW_NAMES: %v\n", cfg.MatchRules.ShowNames)
+	fmt.Fprintf(out, "[Config] MIN_QUALITY: %s\n", cfg.MatchRules.MinQuality)
+	fmt.Fprintf(out, "[Config] PREFERRED_CODEC: %s\n", cfg.MatchRules.PreferredCodec)
+	fmt.Fprintf(out, "[Config] EXCLUDE_GROUPS: %v\n", cfg.MatchRules.ExcludeGroups)
+	fmt.Fprintf(out, "[Config] PREFERRED_GROUPS: %v\n", cfg.MatchRules.PreferredGroups)
+	fmt.Fprintln(out, "\n--- Storage ---")
+	fmt.Fprintf(out, "[Config] STORAGE_PATH: %s\n", cfg.StoragePath)
+	fmt.Fprintln(out, "==========================================")
+
+	// RSS_FEED_URL only seeds the feed registry on first run; once feeds are
+	// managed via /api/feeds it may be left unset.
 	if cfg.FeedURLs[0] == "" {
-		return cfg, fmt.Errorf("RSS_FEED_URL environment variable is required")
+		fmt.Fprintln(out, "[Config] RSS_FEED_URL not set — feeds will be read from the feed registry (/api/feeds)")
 	}
 
 	// Build combined Feeds slice from FeedURLs + MovieFeedURLs
@@ -419,17 +827,17 @@ func loadConfig() (models.Config, error) {
 	}
 
 	// Try to load watchlist.json (falls back to shows.json for existing installs)
-	showsConfig, err := loadShowsConfig()
+	showsConfig, err := loadShowsConfig(out)
 	if err == nil {
 		cfg.ShowsConfig = showsConfig
 	} else {
-		fmt.Printf("[Config] watchlist.json not loaded (%v); using environment variable rules fallback\n", err)
+		fmt.Fprintf(out, "[Config] watchlist.json not loaded (%v); using environment variable rules fallback\n", err)
 	}
 
 	return cfg, nil
 }
 
-func loadShowsConfig() (*models.ShowsConfig, error) {
+func loadShowsConfig(out io.Writer) (*models.ShowsConfig, error) {
 	// Primary path is watchlist.json; shows.json is a silent fallback for
 	// existing installs that have not yet renamed their config file.
 	paths := []string{
@@ -456,7 +864,7 @@ func loadShowsConfig() (*models.ShowsConfig, error) {
 	}
 
 	if loadedPath == "shows.json" || loadedPath == filepath.Join(os.Getenv("HOME"), ".curator-shows.json") {
-		fmt.Printf("[Config] loaded legacy %s — consider renaming to watchlist.json\n", loadedPath)
+		fmt.Fprintf(out, "[Config] loaded legacy %s — consider renaming to watchlist.json\n", loadedPath)
 	}
 
 	var config models.ShowsConfig
@@ -528,10 +936,17 @@ Commands:
   pause <id>...        Pause torrent(s) in qBittorrent
   cleanup [pattern]    Remove stale database entries (default: info page links)
   serve                Start API server and scheduler
+  replay <dir> [--scratch | --db <path>]
+                       Re-run the feed check against captured feed responses
+  explain "<title>" [--movie] [--json]
+                       Trace how every watchlist rule treats a release title
+  migrate-rules [--dry-run]
+                       Convert the legacy env-var match rules into watchlist.json
 
 Configuration:
   1. shows.json (recommended) - Per-show rules
-  2. Environment variables (fallback) - See shows.json.sample
+  2. Environment variables (legacy fallback) - migrated to watchlist.json by
+     the first 'serve' or 'check', or on demand with 'curator migrate-rules'
 
 Examples:
   curator check                    # Check feeds and stage new matches
@@ -541,6 +956,13 @@ Examples:
   curator cleanup                  # Remove stale info page links
   curator cleanup "%/old/%"        # Remove entries matching pattern
   curator test                     # Test configuration
+  curator replay ./captures --scratch  # Replay captured feeds into a throwaway DB
+  curator explain "Andor.S02E01.1080p.WEB-DL.x265-NTb"  # Why did this (not) match?
+  curator migrate-rules --dry-run  # Preview the legacy rule migration as a diff
+
+Capture:
+  Set CURATOR_CAPTURE_DIR to record every raw feed response (with its URL and
+  timestamp) for later replay. Captures may contain passkey-bearing links.
 `)
 }
 
@@ -718,12 +1140,7 @@ func cmdServe(cfg models.Config, store *storage.Storage, buf *logbuffer.Buffer,
 	}
 
 	// Create matcher config once for API rematch operations.
-	var m *matcher.Matcher
-	if cfg.ShowsConfig != nil {
-		m = matcher.NewMatcher(cfg.ShowsConfig, nil)
-	} else {
-		m = matcher.NewMatcher(nil, &cfg.MatchRules)
-	}
+	m := newMatcher(cfg)
 
 	// Try to initialize qBittorrent client with retry
 	var qb *client.Client
@@ -790,6 +1207,14 @@ func cmdServe(cfg models.Config, store *storage.Storage, buf *logbuffer.Buffer,
 		fmt.Printf("[Serve] Recovered %d stale job(s) from previous crash\n", n)
 	}
 
+	// Pending items staged before release scores were stored carry a zero
+	// score; rescore them so dedup and auto-queue compare like with like.
+	if n, err := ops.BackfillReleaseScores(store, m); err != nil {
+		fmt.Fprintf(os.Stderr, "[Serve] Warning: %v\n", err)
+	} else if n > 0 {
+		fmt.Printf("[Serve] Backfilled release scores for %d pending torrent(s)\n", n)
+	}
+
 	// Job queue — single worker, used for on-demand async operations.
 	q := jobs.New(nil)
 	q.Start()
@@ -809,9 +1234,14 @@ func cmdServe(cfg models.Config, store *storage.Storage, buf *logbuffer.Buffer,
 			backfillInterval = time.Duration(n) * time.Second
 		}
 	}
+	// Feeds are read from the registry on every run so /api/feeds edits take
+	// effect without a restart; env-configured feeds seed an empty registry.
+	seedFeedRegistry(store, cfg.Feeds)
 	feedCheckCfg := ops.FeedCheckConfig{
-		Feeds:   cfg.Feeds,
-		Matcher: m,
+		Feeds:     cfg.Feeds,
+		LoadFeeds: ops.RegistryFeeds(store),
+		Sources:   watchSources(),
+		Matcher:   m,
 	}
 	feedCheckDeps := ops.FeedCheckDeps{
 		Store:      store,
@@ -819,10 +1249,14 @@ func cmdServe(cfg models.Config, store *storage.Storage, buf *logbuffer.Buffer,
 		Scorer:     scorer,
 		ScorerProv: scorerProvider,
 		LogBuffer:  buf,
+		Parser:     captureParser(),
 	}
 	// Pre-declare settingsMgr so scheduler task closures can capture it as a
 	// mutable reference; the actual assignment happens after sched.Start().
 	var settingsMgr *settings.Manager
+	feedCheckDeps.InspectTorrents = func() bool {
+		return settingsMgr != nil && settingsMgr.Get().Scheduler.InspectTorrents
+	}
 	sched := scheduler.New()
 	sched.Register(&scheduler.Task{
 		Type:     "feed_check",
@@ -897,12 +1331,14 @@ func cmdServe(cfg models.Config, store *storage.Storage, buf *logbuffer.Buffer,
 				MinConfidence: st.MinConfidence,
 				HoldMins:      st.HoldMins,
 				MaxHoldMins:   st.MaxHoldMins,
+				Upgrades:      st.Upgrades,
 				DryRun:        st.DryRun,
 			}, autoQueueDeps)
 		},
 	})
 
-	// watchlist_enrich — backfill empty rule fields from approval history.
+	// watchlist_enrich — backfill empty rule fields from approval history and
+	// merge provider alternate titles into rule aliases.
 	watchlistEnrichInterval := 6 * time.Hour
 	if v := os.Getenv("CURATOR_WATCHLIST_ENRICH_INTERVAL_HOURS"); v != "" {
 		if n, err := strconv.Atoi(v); err == nil && n > 0 {
@@ -915,9 +1351,32 @@ func cmdServe(cfg models.Config, store *storage.Storage, buf *logbuffer.Buffer,
 		Enabled:  true,
 		Fn: func(ctx context.Context) {
 			ops.RunWatchlistEnrich(ctx, ops.WatchlistEnrichDeps{
-				Store:     store,
-				Matcher:   m,
-				ShowsPath: resolveShowsPath(),
+				Store:      store,
+				Matcher:    m,
+				ShowsPath:  resolveShowsPath(),
+				MetaLookup: metaLookup,
+			})
+		},
+	})
+
+	// watchlist_retire — archive movie rules whose retire_after is met and
+	// ended shows whose finale has been queued.
+	watchlistRetireInterval := time.Hour
+	if v := os.Getenv("CURATOR_WATCHLIST_RETIRE_INTERVAL_HOURS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			watchlistRetireInterval = time.Duration(n) * time.Hour
+		}
+	}
+	sched.Register(&scheduler.Task{
+		Type:     "watchlist_retire",
+		Interval: watchlistRetireInterval,
+		Enabled:  true,
+		Fn: func(ctx context.Context) {
+			ops.RunWatchlistRetire(ctx, ops.WatchlistRetireDeps{
+				Store:      store,
+				Matcher:    m,
+				ShowsPath:  resolveShowsPath(),
+				MetaLookup: metaLookup,
 			})
 		},
 	})
@@ -950,6 +1409,7 @@ func cmdServe(cfg models.Config, store *storage.Storage, buf *logbuffer.Buffer,
 		PreferredGroups:       cfg.MatchRules.PreferredGroups,
 		AuthUsername:          authUsername,
 		AuthPassword:          authPassword,
+		InspectTorrents:       getEnv("CURATOR_INSPECT_TORRENTS", "false") == "true",
 	}
 	if err := settingsMgr.Load(envDefaults); err != nil {
 		fmt.Fprintf(os.Stderr, "[Serve] Warning: could not load settings from DB: %v\n", err)
@@ -27,3 +27,73 @@ func TestParseCSV(t *testing.T) {
 		})
 	}
 }
+
+func TestParseReplayArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    replayArgs
+		wantErr bool
+	}{
+		{name: "dir only", args: []string{"caps"}, want: replayArgs{dir: "caps"}},
+		{name: "scratch", args: []string{"--scratch", "caps"}, want: replayArgs{dir: "caps", scratch: true}},
+		{name: "db", args: []string{"caps", "--db", "/tmp/r.db"}, want: replayArgs{dir: "caps", dbPath: "/tmp/r.db"}},
+		{name: "db equals", args: []string{"caps", "--db=/tmp/r.db"}, want: replayArgs{dir: "caps", dbPath: "/tmp/r.db"}},
+		{name: "missing dir", args: []string{"--scratch"}, wantErr: true},
+		{name: "db without path", args: []string{"caps", "--db"}, wantErr: true},
+		{name: "scratch and db", args: []string{"caps", "--scratch", "--db", "x"}, wantErr: true},
+		{name: "unknown flag", args: []string{"caps", "--live"}, wantErr: true},
+		{name: "extra arg", args: []string{"caps", "more"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseReplayArgs(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseReplayArgs(%q): expected error, got %+v", tt.args, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseReplayArgs(%q): %v", tt.args, err)
+			}
+			if got != tt.want {
+				t.Fatalf("parseReplayArgs(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExplainArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    explainArgs
+		wantErr bool
+	}{
+		{name: "title", args: []string{"Andor.S02E01.1080p"}, want: explainArgs{title: "Andor.S02E01.1080p"}},
+		{name: "movie", args: []string{"--movie", "Heat 1995 1080p"}, want: explainArgs{title: "Heat 1995 1080p", movie: true}},
+		{name: "unquoted words", args: []string{"Heat", "1995", "--json"}, want: explainArgs{title: "Heat 1995", asJSON: true}},
+		{name: "missing title", args: []string{"--movie"}, wantErr: true},
+		{name: "unknown flag", args: []string{"x", "--show"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExplainArgs(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseExplainArgs(%q): expected error, got %+v", tt.args, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseExplainArgs(%q): %v", tt.args, err)
+			}
+			if got != tt.want {
+				t.Fatalf("parseExplainArgs(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
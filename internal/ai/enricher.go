@@ -77,7 +77,7 @@ func (e *Enricher) EnrichForce(item *models.FeedItem) {
 func (e *Enricher) enrich(item *models.FeedItem, force bool) {
 	// Only enrich if at least one key field is missing.
 	if !force && item.ShowName != "" &&
-		item.Season > 0 &&
+		(item.Season > 0 || item.AirDate != "") &&
 		item.Quality != "" &&
 		item.Codec != "" &&
 		item.Source != "" &&
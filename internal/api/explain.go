@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/killakam3084/rss-curator/internal/ops"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// ExplainRequest is the body accepted by POST /api/match/explain.
+// ContentType defaults to "show".
+type ExplainRequest struct {
+	Title       string             `json:"title"`
+	ContentType models.ContentType `json:"content_type"`
+}
+
+// handleMatchExplain parses a release title and returns a per-rule trace of
+// how the current watchlist treats it.
+// POST /api/match/explain — 200 with a matcher.Explanation.
+func (s *Server) handleMatchExplain(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "method not allowed"})
+		return
+	}
+	if s.matcher == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "matcher unavailable"})
+		return
+	}
+
+	var req ExplainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("invalid JSON: %v", err)})
+		return
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "title is required"})
+		return
+	}
+	switch req.ContentType {
+	case "", models.ContentTypeShow, models.ContentTypeMovie:
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("content_type must be %q or %q", models.ContentTypeShow, models.ContentTypeMovie)})
+		return
+	}
+
+	json.NewEncoder(w).Encode(ops.ExplainTitle(s.matcher, req.Title, req.ContentType))
+}
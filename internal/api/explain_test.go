@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+func TestHandleMatchExplain(t *testing.T) {
+	server, _ := setupTestServer(t)
+	server.matcher = matcher.NewMatcher(&models.ShowsConfig{
+		Shows: []models.ShowRule{
+			{Name: "Andor", MinQuality: "2160p"},
+			{Name: "Shrinking"},
+		},
+		Defaults: models.DefaultRules{MinQuality: "1080p", PreferredCodec: "x265"},
+	}, nil)
+
+	body := `{"title":"Andor.S02E01.1080p.DSNP.WEB-DL.DDP5.1.H.265-NTb"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/match/explain", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleMatchExplain(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var exp matcher.Explanation
+	if err := json.NewDecoder(w.Body).Decode(&exp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if exp.Item.ShowName != "Andor" || exp.Item.Season != 2 || exp.Item.Episode != 1 {
+		t.Errorf("parsed fields not returned: %+v", exp.Item)
+	}
+	if exp.Result.Matched || exp.Result.Reason != "quality 1080P below minimum 2160p" {
+		t.Errorf("overall result = %+v", exp.Result)
+	}
+	if len(exp.Rules) != 2 || !exp.Rules[0].NameMatched || exp.Rules[1].NameMatched {
+		t.Fatalf("rule traces = %+v", exp.Rules)
+	}
+	quality := exp.Rules[0].Checks[0]
+	if quality.Check != "min_quality" || quality.Passed || quality.Effective != "2160p" || quality.Source != "rule" {
+		t.Errorf("min_quality trace = %+v", quality)
+	}
+	if q := exp.Rules[1].Checks[0]; q.Source != "defaults" || !q.Passed {
+		t.Errorf("unmatched rule should still trace defaults: %+v", q)
+	}
+
+	for _, tc := range []struct {
+		method, body string
+		want         int
+	}{
+		{http.MethodGet, "", http.StatusMethodNotAllowed},
+		{http.MethodPost, `{"title":"  "}`, http.StatusBadRequest},
+		{http.MethodPost, `{"title":"x","content_type":"music"}`, http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest(tc.method, "/api/match/explain", strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+		server.handleMatchExplain(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s %q: got %d, want %d", tc.method, tc.body, w.Code, tc.want)
+		}
+	}
+}
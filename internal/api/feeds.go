@@ -0,0 +1,338 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+	"go.uber.org/zap"
+)
+
+// FeedStatus is one entry in GET /api/feeds/status: a feed plus its persisted
+// health record. Feeds that have never been fetched carry only their identity.
+type FeedStatus struct {
+	models.FeedState
+	ID          int                `json:"id,omitempty"`
+	Name        string             `json:"name,omitempty"`
+	ContentType models.ContentType `json:"content_type"`
+	Enabled     bool               `json:"enabled"`
+	// BackingOff is true while NextAttemptAt is in the future.
+	BackingOff bool `json:"backing_off"`
+}
+
+type FeedStatusResponse struct {
+	Feeds []FeedStatus `json:"feeds"`
+}
+
+// FeedsResponse is the shape returned by GET /api/feeds.
+type FeedsResponse struct {
+	Feeds []models.ManagedFeed `json:"feeds"`
+	Count int                  `json:"count"`
+}
+
+// FeedRequest is the body accepted by POST /api/feeds and PUT /api/feeds/{id}.
+// Enabled defaults to true when omitted. On PUT a passkey or cookie value of
+// "***" (as returned by GET) means "do not change".
+type FeedRequest struct {
+	Name             string             `json:"name"`
+	URL              string             `json:"url"`
+	ContentType      models.ContentType `json:"content_type"`
+	Enabled          *bool              `json:"enabled"`
+	PollIntervalMins int                `json:"poll_interval_mins"`
+	Headers          map[string]string  `json:"headers"`
+	Cookies          map[string]string  `json:"cookies"`
+	Passkey          string             `json:"passkey"`
+}
+
+// toManagedFeed validates the request and converts it to a registry entry.
+// stored is the feed being replaced (nil on create); masked secrets in the
+// request keep its values.
+func (req FeedRequest) toManagedFeed(stored *models.ManagedFeed) (models.ManagedFeed, error) {
+	f := models.ManagedFeed{
+		Name:             strings.TrimSpace(req.Name),
+		URL:              strings.TrimSpace(req.URL),
+		ContentType:      req.ContentType,
+		Enabled:          req.Enabled == nil || *req.Enabled,
+		PollIntervalMins: req.PollIntervalMins,
+		Headers:          req.Headers,
+		Cookies:          req.Cookies,
+		Passkey:          strings.TrimSpace(req.Passkey),
+	}
+	if stored != nil {
+		if f.Passkey == "***" {
+			f.Passkey = stored.Passkey
+		}
+		for name, v := range f.Cookies {
+			if v == "***" {
+				f.Cookies[name] = stored.Cookies[name]
+			}
+		}
+	}
+	u, err := url.Parse(f.FeedConfig().Auth().Expand(f.URL))
+	if f.URL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return f, fmt.Errorf("url must be an absolute http(s) URL")
+	}
+	switch f.ContentType {
+	case "":
+		f.ContentType = models.ContentTypeShow
+	case models.ContentTypeShow, models.ContentTypeMovie:
+	default:
+		return f, fmt.Errorf("content_type must be %q or %q", models.ContentTypeShow, models.ContentTypeMovie)
+	}
+	if f.PollIntervalMins < 0 {
+		return f, fmt.Errorf("poll_interval_mins must be >= 0")
+	}
+	if f.Headers == nil {
+		f.Headers = map[string]string{}
+	}
+	if f.Cookies == nil {
+		f.Cookies = map[string]string{}
+	}
+	return f, nil
+}
+
+// maskedFeed returns f with its passkey and cookie values replaced by "***"
+// so tracker credentials are never sent to API clients.
+func maskedFeed(f models.ManagedFeed) models.ManagedFeed {
+	if f.Passkey != "" {
+		f.Passkey = "***"
+	}
+	if len(f.Cookies) > 0 {
+		masked := make(map[string]string, len(f.Cookies))
+		for name := range f.Cookies {
+			masked[name] = "***"
+		}
+		f.Cookies = masked
+	}
+	return f
+}
+
+// isUniqueViolation reports whether err is a SQLite UNIQUE constraint error.
+func isUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// handleFeeds lists or creates registry feeds.
+// GET /api/feeds — all feeds ordered by id.
+// POST /api/feeds — create; 201 with the stored feed, 409 on a duplicate URL.
+func (s *Server) handleFeeds(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+		feeds, err := s.store.ListFeeds()
+		if err != nil {
+			s.logger.Error("handleFeeds: ListFeeds failed", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		for i := range feeds {
+			feeds[i] = maskedFeed(feeds[i])
+		}
+		json.NewEncoder(w).Encode(FeedsResponse{Feeds: feeds, Count: len(feeds)})
+
+	case http.MethodPost:
+		var req FeedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid JSON: " + err.Error()})
+			return
+		}
+		f, err := req.toManagedFeed(nil)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		id, err := s.store.CreateFeed(f)
+		if isUniqueViolation(err) {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "a feed with this url already exists"})
+			return
+		}
+		if err != nil {
+			s.logger.Error("handleFeeds: CreateFeed failed", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		created, err := s.store.GetFeed(id)
+		if err != nil || created == nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "feed created but could not be read back"})
+			return
+		}
+		s.logger.Info("feed registered", zap.Int("feed_id", id), zap.String("url", created.URL))
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(maskedFeed(*created))
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "method not allowed"})
+	}
+}
+
+// handleFeed reads, replaces or deletes a single registry feed.
+// GET /api/feeds/{id}, PUT /api/feeds/{id}, DELETE /api/feeds/{id} (204).
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/feeds/"), "/"))
+	if err != nil || id <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid feed id"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		f, err := s.store.GetFeed(id)
+		if err != nil {
+			s.logger.Error("handleFeed: GetFeed failed", zap.Int("feed_id", id), zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		if f == nil {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "feed not found"})
+			return
+		}
+		json.NewEncoder(w).Encode(maskedFeed(*f))
+
+	case http.MethodPut:
+		var req FeedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "invalid JSON: " + err.Error()})
+			return
+		}
+		stored, err := s.store.GetFeed(id)
+		if err != nil {
+			s.logger.Error("handleFeed: GetFeed failed", zap.Int("feed_id", id), zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		if stored == nil {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "feed not found"})
+			return
+		}
+		f, err := req.toManagedFeed(stored)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		f.ID = id
+		err = s.store.UpdateFeed(f)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "feed not found"})
+			return
+		case isUniqueViolation(err):
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "a feed with this url already exists"})
+			return
+		case err != nil:
+			s.logger.Error("handleFeed: UpdateFeed failed", zap.Int("feed_id", id), zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		s.logger.Info("feed updated", zap.Int("feed_id", id), zap.String("url", f.URL), zap.Bool("enabled", f.Enabled))
+		if updated, _ := s.store.GetFeed(id); updated != nil {
+			json.NewEncoder(w).Encode(maskedFeed(*updated))
+		}
+
+	case http.MethodDelete:
+		err := s.store.DeleteFeed(id)
+		if errors.Is(err, sql.ErrNoRows) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "feed not found"})
+			return
+		}
+		if err != nil {
+			s.logger.Error("handleFeed: DeleteFeed failed", zap.Int("feed_id", id), zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		s.logger.Info("feed deleted", zap.Int("feed_id", id))
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "method not allowed"})
+	}
+}
+
+// handleFeedsStatus returns per-feed health. When the feed registry holds any
+// feeds they are listed (including disabled ones); otherwise the statically
+// configured feeds are used.
+// GET /api/feeds/status
+func (s *Server) handleFeedsStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	states, err := s.store.ListFeedStates()
+	if err != nil {
+		s.logger.Error("handleFeedsStatus: ListFeedStates failed", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+	byURL := make(map[string]models.FeedState, len(states))
+	for _, st := range states {
+		byURL[st.URL] = st
+	}
+
+	registry, err := s.store.ListFeeds()
+	if err != nil {
+		s.logger.Error("handleFeedsStatus: ListFeeds failed", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(registry) == 0 {
+		for _, fc := range s.feedCheckCfg.Feeds {
+			registry = append(registry, models.ManagedFeed{
+				Name:        fc.Name,
+				URL:         fc.URL,
+				ContentType: fc.ContentType,
+				Enabled:     true,
+			})
+		}
+	}
+
+	now := time.Now()
+	resp := FeedStatusResponse{Feeds: make([]FeedStatus, 0, len(registry))}
+	for _, f := range registry {
+		st, ok := byURL[f.URL]
+		if !ok {
+			st = models.FeedState{URL: f.URL}
+		}
+		resp.Feeds = append(resp.Feeds, FeedStatus{
+			FeedState:   st,
+			ID:          f.ID,
+			Name:        f.Name,
+			ContentType: f.ContentType,
+			Enabled:     f.Enabled,
+			BackingOff:  st.NextAttemptAt != nil && now.Before(*st.NextAttemptAt),
+		})
+	}
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+func TestHandleFeeds_CreateAndList(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	body := `{"name":"Tracker TV","url":"https://tracker.example/rss?cat=tv","content_type":"show","poll_interval_mins":30,"headers":{"X-Api-Key":"k"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/feeds", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleFeeds(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created models.ManagedFeed
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if created.ID == 0 || !created.Enabled || created.PollIntervalMins != 30 || created.Headers["X-Api-Key"] != "k" {
+		t.Errorf("unexpected created feed: %+v", created)
+	}
+
+	// Duplicate URL → 409.
+	req = httptest.NewRequest(http.MethodPost, "/api/feeds", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	server.handleFeeds(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for duplicate url, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/feeds", nil)
+	w = httptest.NewRecorder()
+	server.handleFeeds(w, req)
+	var resp FeedsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Count != 1 || resp.Feeds[0].Name != "Tracker TV" {
+		t.Errorf("unexpected list: %+v", resp)
+	}
+}
+
+func TestHandleFeeds_Validation(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	cases := []string{
+		`{"url":""}`,
+		`{"url":"ftp://tracker.example/rss"}`,
+		`{"url":"https://tracker.example/rss","content_type":"music"}`,
+		`{"url":"https://tracker.example/rss","poll_interval_mins":-1}`,
+		`not json`,
+	}
+	for _, body := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/feeds", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		server.handleFeeds(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("body %s: expected 400, got %d", body, w.Code)
+		}
+	}
+}
+
+func TestHandleFeed_MasksSecrets(t *testing.T) {
+	server, mockStore := setupTestServer(t)
+	id, _ := mockStore.CreateFeed(models.ManagedFeed{
+		Name: "TV", URL: "https://tracker.example/rss?pk={passkey}", ContentType: models.ContentTypeShow, Enabled: true,
+		Cookies: map[string]string{"uid": "42", "pass": "secret"}, Passkey: "abc123",
+	})
+
+	for _, path := range []string{"/api/feeds", "/api/feeds/1"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		if path == "/api/feeds" {
+			server.handleFeeds(w, req)
+		} else {
+			server.handleFeed(w, req)
+		}
+		if body := w.Body.String(); strings.Contains(body, "abc123") || strings.Contains(body, "secret") || !strings.Contains(body, `"***"`) {
+			t.Errorf("GET %s leaks or does not mask secrets: %s", path, body)
+		}
+	}
+
+	// Masked values sent back on PUT keep the stored secrets; new values replace them.
+	body := `{"name":"TV","url":"https://tracker.example/rss?pk={passkey}","passkey":"***","cookies":{"uid":"43","pass":"***"}}`
+	req := httptest.NewRequest(http.MethodPut, "/api/feeds/1", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleFeed(w, req)
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "secret") {
+		t.Fatalf("expected masked 200, got %d: %s", w.Code, w.Body.String())
+	}
+	f, _ := mockStore.GetFeed(id)
+	if f.Passkey != "abc123" || f.Cookies["pass"] != "secret" || f.Cookies["uid"] != "43" {
+		t.Errorf("stored secrets = %q %v", f.Passkey, f.Cookies)
+	}
+}
+
+func TestHandleFeed_UpdateAndDelete(t *testing.T) {
+	server, mockStore := setupTestServer(t)
+	id, _ := mockStore.CreateFeed(models.ManagedFeed{
+		Name: "Movies", URL: "https://tracker.example/movies", ContentType: models.ContentTypeMovie, Enabled: true,
+	})
+
+	body := `{"name":"Movies (paused)","url":"https://tracker.example/movies","content_type":"movie","enabled":false}`
+	req := httptest.NewRequest(http.MethodPut, "/api/feeds/1", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleFeed(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if f, _ := mockStore.GetFeed(id); f == nil || f.Enabled || f.Name != "Movies (paused)" {
+		t.Errorf("feed not updated: %+v", f)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/feeds/1", nil)
+	w = httptest.NewRecorder()
+	server.handleFeed(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/feeds/1", nil)
+	w = httptest.NewRecorder()
+	server.handleFeed(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", w.Code)
+	}
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/pkg/models"
+	"go.uber.org/zap"
+)
+
+// ProfilesResponse is the shape returned by GET /api/watchlist/profiles.
+type ProfilesResponse struct {
+	Profiles []models.QualityProfile `json:"profiles"`
+	Count    int                     `json:"count"`
+}
+
+// editableWatchlist returns a copy of the live watchlist whose Shows, Movies
+// and Profiles slices can be modified without touching the matcher's state.
+// Legacy-rules mode yields an empty watchlist.
+func (s *Server) editableWatchlist() *models.ShowsConfig {
+	cfg := s.matcher.ShowsConfig()
+	if cfg == nil {
+		cfg = &models.ShowsConfig{}
+	}
+	cfg.Shows = append([]models.ShowRule{}, cfg.Shows...)
+	cfg.Movies = append([]models.MovieRule{}, cfg.Movies...)
+	cfg.Profiles = append([]models.QualityProfile{}, cfg.Profiles...)
+	return cfg
+}
+
+// profileUsage counts the rules that reference the named profile.
+func profileUsage(cfg *models.ShowsConfig, name string) int {
+	n := 0
+	for _, r := range cfg.Shows {
+		if strings.EqualFold(r.Profile, name) {
+			n++
+		}
+	}
+	for _, r := range cfg.Movies {
+		if strings.EqualFold(r.Profile, name) {
+			n++
+		}
+	}
+	return n
+}
+
+// validateAndSaveWatchlist validates cfg and persists it, writing the error
+// response itself. It reports whether the save succeeded.
+func (s *Server) validateAndSaveWatchlist(w http.ResponseWriter, cfg *models.ShowsConfig) bool {
+	if err := matcher.ValidateShowsConfig(cfg); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return false
+	}
+	if err := s.saveWatchlist(cfg); err != nil {
+		s.logger.Error("saving watchlist failed", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return false
+	}
+	return true
+}
+
+// decodeProfile reads a QualityProfile body and normalises its name.
+func decodeProfile(r *http.Request) (models.QualityProfile, error) {
+	var p models.QualityProfile
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		return p, fmt.Errorf("invalid JSON: %w", err)
+	}
+	p.Name = strings.TrimSpace(p.Name)
+	if strings.Contains(p.Name, "/") {
+		return p, fmt.Errorf("profile name must not contain '/'")
+	}
+	return p, nil
+}
+
+// handleProfiles lists or creates quality profiles.
+// GET /api/watchlist/profiles — all profiles in watchlist order.
+// POST /api/watchlist/profiles — create; 201 with the profile, 409 on a duplicate name.
+func (s *Server) handleProfiles(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if s.matcher == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "matcher unavailable"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		cfg := s.editableWatchlist()
+		json.NewEncoder(w).Encode(ProfilesResponse{Profiles: cfg.Profiles, Count: len(cfg.Profiles)})
+
+	case http.MethodPost:
+		p, err := decodeProfile(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		cfg := s.editableWatchlist()
+		if cfg.Profile(p.Name) != nil {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "a profile with this name already exists"})
+			return
+		}
+		cfg.Profiles = append(cfg.Profiles, p)
+		if !s.validateAndSaveWatchlist(w, cfg) {
+			return
+		}
+		s.logger.Info("quality profile created", zap.String("profile", p.Name))
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(p)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "method not allowed"})
+	}
+}
+
+// handleProfile reads, replaces or deletes a single quality profile.
+// GET /api/watchlist/profiles/{name}
+// PUT /api/watchlist/profiles/{name} — replace; a new name in the body renames
+// the profile and the rules that reference it.
+// DELETE /api/watchlist/profiles/{name} — 204, or 409 while rules reference it.
+func (s *Server) handleProfile(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if s.matcher == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "matcher unavailable"})
+		return
+	}
+
+	name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/watchlist/profiles/"), "/")
+	cfg := s.editableWatchlist()
+	existing := cfg.Profile(name)
+	if name == "" || existing == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "profile not found"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(existing)
+
+	case http.MethodPut:
+		p, err := decodeProfile(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		oldName := existing.Name
+		if p.Name == "" {
+			p.Name = oldName
+		}
+		if !strings.EqualFold(p.Name, oldName) {
+			if cfg.Profile(p.Name) != nil {
+				w.WriteHeader(http.StatusConflict)
+				json.NewEncoder(w).Encode(ErrorResponse{Error: "a profile with this name already exists"})
+				return
+			}
+			for i := range cfg.Shows {
+				if strings.EqualFold(cfg.Shows[i].Profile, oldName) {
+					cfg.Shows[i].Profile = p.Name
+				}
+			}
+			for i := range cfg.Movies {
+				if strings.EqualFold(cfg.Movies[i].Profile, oldName) {
+					cfg.Movies[i].Profile = p.Name
+				}
+			}
+		}
+		*existing = p
+		if !s.validateAndSaveWatchlist(w, cfg) {
+			return
+		}
+		s.logger.Info("quality profile updated", zap.String("profile", p.Name))
+		json.NewEncoder(w).Encode(p)
+
+	case http.MethodDelete:
+		if n := profileUsage(cfg, existing.Name); n > 0 {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("profile is referenced by %d rule(s)", n)})
+			return
+		}
+		deleted := existing.Name
+		kept := make([]models.QualityProfile, 0, len(cfg.Profiles)-1)
+		for _, p := range cfg.Profiles {
+			if !strings.EqualFold(p.Name, deleted) {
+				kept = append(kept, p)
+			}
+		}
+		cfg.Profiles = kept
+		if !s.validateAndSaveWatchlist(w, cfg) {
+			return
+		}
+		s.logger.Info("quality profile deleted", zap.String("profile", deleted))
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: "method not allowed"})
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+func setupProfileServer(t *testing.T) *Server {
+	t.Helper()
+	server, _ := setupTestServer(t)
+	server.showsPath = filepath.Join(t.TempDir(), "watchlist.json")
+	server.matcher = matcher.NewMatcher(&models.ShowsConfig{
+		Shows: []models.ShowRule{{Name: "Abbott Elementary"}},
+	}, nil)
+	return server
+}
+
+func TestHandleProfiles_CRUD(t *testing.T) {
+	server := setupProfileServer(t)
+
+	body := `{"name":"Sitcom-720p","min_quality":"720p","max_quality":"1080p","preferred_codec":"x264"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/watchlist/profiles", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	server.handleProfiles(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Duplicate names conflict, case-insensitively.
+	req = httptest.NewRequest(http.MethodPost, "/api/watchlist/profiles", strings.NewReader(`{"name":"sitcom-720P"}`))
+	w = httptest.NewRecorder()
+	server.handleProfiles(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for duplicate name, got %d", w.Code)
+	}
+
+	// Invalid profiles are rejected by watchlist validation.
+	req = httptest.NewRequest(http.MethodPost, "/api/watchlist/profiles", strings.NewReader(`{"name":"Bad","min_size":5,"max_size":1}`))
+	w = httptest.NewRecorder()
+	server.handleProfiles(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for inverted sizes, got %d", w.Code)
+	}
+
+	// The profile is persisted and live in the matcher.
+	data, err := os.ReadFile(server.showsPath)
+	if err != nil || !strings.Contains(string(data), `"Sitcom-720p"`) {
+		t.Fatalf("watchlist not written: %v\n%s", err, data)
+	}
+	cfg := server.matcher.ShowsConfig()
+	if cfg.Profile("Sitcom-720p") == nil {
+		t.Fatal("matcher not reloaded with the new profile")
+	}
+
+	// Reference it from a rule, then rename it: the reference follows.
+	cfg.Shows = []models.ShowRule{{Name: "Abbott Elementary", Profile: "Sitcom-720p"}}
+	server.matcher.SetShowsConfig(cfg)
+	req = httptest.NewRequest(http.MethodPut, "/api/watchlist/profiles/Sitcom-720p", strings.NewReader(`{"name":"Sitcom","min_quality":"720p"}`))
+	w = httptest.NewRecorder()
+	server.handleProfile(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := server.matcher.ShowsConfig().Shows[0].Profile; got != "Sitcom" {
+		t.Errorf("rule profile after rename = %q, want Sitcom", got)
+	}
+
+	// In-use profiles cannot be deleted.
+	req = httptest.NewRequest(http.MethodDelete, "/api/watchlist/profiles/Sitcom", nil)
+	w = httptest.NewRecorder()
+	server.handleProfile(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 deleting an in-use profile, got %d", w.Code)
+	}
+
+	cfg = server.matcher.ShowsConfig()
+	cfg.Shows = []models.ShowRule{{Name: "Abbott Elementary"}}
+	server.matcher.SetShowsConfig(cfg)
+	req = httptest.NewRequest(http.MethodDelete, "/api/watchlist/profiles/Sitcom", nil)
+	w = httptest.NewRecorder()
+	server.handleProfile(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/watchlist/profiles", nil)
+	w = httptest.NewRecorder()
+	server.handleProfiles(w, req)
+	var resp ProfilesResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Count != 0 {
+		t.Errorf("expected no profiles left, got %+v", resp)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/watchlist/profiles/Sitcom", nil)
+	w = httptest.NewRecorder()
+	server.handleProfile(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a deleted profile, got %d", w.Code)
+	}
+}
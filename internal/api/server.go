@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"os"
 	"strconv"
@@ -79,21 +80,39 @@ type ErrorResponse struct {
 }
 
 type TorrentResponse struct {
-	ID                    int                `json:"id"`
-	Title                 string             `json:"title"`
-	Size                  int64              `json:"size"`
-	PubDate               time.Time          `json:"pub_date"`
-	StagedAt              time.Time          `json:"staged_at"`
-	MatchReason           string             `json:"match_reason"`
-	Status                string             `json:"status"`
-	Link                  string             `json:"link"`
-	AIScore               float64            `json:"ai_score"`
-	AIReason              string             `json:"ai_reason"`
-	AIScored              bool               `json:"ai_scored"`
-	MatchConfidence       float64            `json:"match_confidence"`
-	MatchConfidenceReason string             `json:"match_confidence_reason"`
-	ContentType           models.ContentType `json:"content_type"`
-	ReleaseYear           int                `json:"release_year,omitempty"`
+	ID                    int                 `json:"id"`
+	Title                 string              `json:"title"`
+	Size                  int64               `json:"size"`
+	PubDate               time.Time           `json:"pub_date"`
+	StagedAt              time.Time           `json:"staged_at"`
+	MatchReason           string              `json:"match_reason"`
+	Status                string              `json:"status"`
+	Link                  string              `json:"link"`
+	AIScore               float64             `json:"ai_score"`
+	AIReason              string              `json:"ai_reason"`
+	AIScored              bool                `json:"ai_scored"`
+	MatchConfidence       float64             `json:"match_confidence"`
+	MatchConfidenceReason string              `json:"match_confidence_reason"`
+	ContentType           models.ContentType  `json:"content_type"`
+	ReleaseYear           int                 `json:"release_year,omitempty"`
+	Seeders               *int                `json:"seeders,omitempty"`
+	Peers                 *int                `json:"peers,omitempty"`
+	InfoHash              string              `json:"info_hash,omitempty"`
+	Categories            []int               `json:"categories,omitempty"`
+	DownloadVolumeFactor  *float64            `json:"download_volume_factor,omitempty"`
+	IMDBID                string              `json:"imdb_id,omitempty"`
+	TVDBID                int                 `json:"tvdb_id,omitempty"`
+	Revision              int                 `json:"revision,omitempty"`
+	Audio                 []string            `json:"audio,omitempty"`
+	AudioChannels         string              `json:"audio_channels,omitempty"`
+	BitDepth              int                 `json:"bit_depth,omitempty"`
+	Edition               string              `json:"edition,omitempty"`
+	Languages             []string            `json:"languages,omitempty"`
+	Subbed                bool                `json:"subbed,omitempty"`
+	Dubbed                bool                `json:"dubbed,omitempty"`
+	UpgradeOf             int                 `json:"upgrade_of,omitempty"`
+	SupersededBy          int                 `json:"superseded_by,omitempty"`
+	Torrent               *models.TorrentMeta `json:"torrent,omitempty"`
 }
 
 type ListResponse struct {
@@ -190,6 +209,24 @@ func torrentToResponse(t models.StagedTorrent) TorrentResponse {
 		MatchConfidenceReason: t.MatchConfidenceReason,
 		ContentType:           t.FeedItem.ContentType,
 		ReleaseYear:           t.FeedItem.ReleaseYear,
+		Seeders:               t.FeedItem.Seeders,
+		Peers:                 t.FeedItem.Peers,
+		InfoHash:              t.FeedItem.InfoHash,
+		Categories:            t.FeedItem.Categories,
+		DownloadVolumeFactor:  t.FeedItem.DownloadVolumeFactor,
+		IMDBID:                t.FeedItem.IMDBID,
+		TVDBID:                t.FeedItem.TVDBID,
+		Revision:              t.FeedItem.Revision,
+		Audio:                 t.FeedItem.Audio,
+		AudioChannels:         t.FeedItem.AudioChannels,
+		BitDepth:              t.FeedItem.BitDepth,
+		Edition:               t.FeedItem.Edition,
+		Languages:             t.FeedItem.Languages,
+		Subbed:                t.FeedItem.Subbed,
+		Dubbed:                t.FeedItem.Dubbed,
+		UpgradeOf:             t.UpgradeOf,
+		SupersededBy:          t.SupersededBy,
+		Torrent:               t.Torrent,
 	}
 }
 
@@ -384,6 +421,9 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/api/suggestions/dismiss", s.handleSuggestionsDismiss)
 	mux.HandleFunc("/api/suggestions", s.handleSuggestions)
 	mux.HandleFunc("/api/feed-check", s.handleFeedCheck)
+	mux.HandleFunc("/api/feeds/status", s.handleFeedsStatus)
+	mux.HandleFunc("/api/feeds/", s.handleFeed)
+	mux.HandleFunc("/api/feeds", s.handleFeeds)
 	mux.HandleFunc("/api/auto-queue/preview", s.handleAutoQueuePreview)
 	mux.HandleFunc("/api/auto-queue", s.handleAutoQueue)
 	mux.HandleFunc("/api/jobs/stream", s.handleJobsStream)
@@ -393,7 +433,10 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/api/alerts/stream", s.handleAlertsStream)
 	mux.HandleFunc("/api/alerts", s.handleAlerts)
 	mux.HandleFunc("/api/settings", s.handleSettings)
+	mux.HandleFunc("/api/watchlist/profiles/", s.handleProfile)
+	mux.HandleFunc("/api/watchlist/profiles", s.handleProfiles)
 	mux.HandleFunc("/api/watchlist", s.handleWatchlist)
+	mux.HandleFunc("/api/match/explain", s.handleMatchExplain)
 	mux.HandleFunc("/api/qb/meta", s.handleQBMeta)
 	// Deprecated: /api/shows redirects to /api/watchlist for backward compatibility.
 	mux.HandleFunc("/api/shows", func(w http.ResponseWriter, r *http.Request) {
@@ -705,6 +748,31 @@ func (s *Server) handleApprove(w http.ResponseWriter, r *http.Request, id int) {
 	})
 }
 
+// downloadOptions returns the add options for item: its rule's category,
+// tags and expanded save path, plus the "title" pseudo-option the client uses
+// for link transformation.
+func (s *Server) downloadOptions(item models.FeedItem) map[string]string {
+	opts, err := ops.DownloadOptions(s.matcher, item)
+	if err != nil {
+		s.logger.Warn("save path template failed; using client defaults",
+			zap.String("title", item.Title), zap.Error(err))
+	}
+	opts["title"] = item.Title
+	return opts
+}
+
+// linkUpgrade points the torrent an upgrade replaces at the upgrade once the
+// upgrade has been queued. A no-op for ordinary torrents.
+func (s *Server) linkUpgrade(t *models.StagedTorrent) {
+	if t.UpgradeOf == 0 {
+		return
+	}
+	if err := s.store.SetSupersededBy(t.UpgradeOf, t.ID); err != nil {
+		s.logger.Error("failed to link superseded torrent",
+			zap.Int("id", t.UpgradeOf), zap.Int("upgrade", t.ID), zap.Error(err))
+	}
+}
+
 // handleQueue queues an accepted torrent for download to qBittorrent
 func (s *Server) handleQueue(w http.ResponseWriter, r *http.Request, id int) {
 	if r.Method != http.MethodPost {
@@ -757,12 +825,20 @@ func (s *Server) handleQueue(w http.ResponseWriter, r *http.Request, id int) {
 		return
 	}
 
-	if err := s.client.AddTorrent(torrent.FeedItem.Link, map[string]string{
-		"title":    torrent.FeedItem.Title,
-		"savePath": queueConfig.SavePath,
-		"tags":     queueConfig.Tags,
-		"category": queueConfig.Category,
-	}); err != nil {
+	// Rule routing first; explicit values in the request body win.
+	opts := s.downloadOptions(torrent.FeedItem)
+	if queueConfig.SavePath != "" {
+		opts["savepath"] = queueConfig.SavePath
+		opts["autoTMM"] = "false"
+	}
+	if queueConfig.Tags != "" {
+		opts["tags"] = queueConfig.Tags
+	}
+	if queueConfig.Category != "" {
+		opts["category"] = queueConfig.Category
+	}
+
+	if err := s.client.AddTorrentWithAuth(torrent.FeedItem.Link, opts, ops.FeedAuthFor(s.store, torrent.FeedItem.FeedURL)); err != nil {
 		s.logger.Error("failed to add torrent to qBittorrent", zap.Int("id", id), zap.Error(err))
 		// Persist the failure so the UI can surface the reason and offer a retry.
 		if ferr := s.store.SetFailed(id, err.Error()); ferr != nil {
@@ -780,6 +856,7 @@ func (s *Server) handleQueue(w http.ResponseWriter, r *http.Request, id int) {
 		s.logger.Error("failed to update torrent status to queued", zap.Int("id", id), zap.Error(err))
 		// Non-fatal: torrent is already in qBittorrent regardless.
 	}
+	s.linkUpgrade(torrent)
 
 	if err := s.store.LogActivity(id, torrent.FeedItem.Title, "queue", torrent.MatchReason); err != nil {
 		s.logger.Error("failed to log activity", zap.Int("id", id), zap.Error(err))
@@ -799,8 +876,8 @@ func (s *Server) handleQueue(w http.ResponseWriter, r *http.Request, id int) {
 		zap.Int("id", id),
 		zap.String("title", torrent.FeedItem.Title),
 		zap.String("quality", torrent.FeedItem.Quality),
-		zap.String("save_path", queueConfig.SavePath),
-		zap.String("category", queueConfig.Category),
+		zap.String("save_path", opts["savepath"]),
+		zap.String("category", opts["category"]),
 	)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(ApproveResponse{
@@ -951,10 +1028,8 @@ func (s *Server) handleRetryQBittorrent(w http.ResponseWriter, r *http.Request,
 	ctx, cancel := getContextWithTimeout(r.Context())
 	defer cancel()
 
-	opts := map[string]string{
-		"title": torrent.FeedItem.Title,
-	}
-	err = s.client.RetryAddTorrent(ctx, torrent.FeedItem.Link, opts)
+	opts := s.downloadOptions(torrent.FeedItem)
+	err = s.client.RetryAddTorrent(ctx, torrent.FeedItem.Link, opts, ops.FeedAuthFor(s.store, torrent.FeedItem.FeedURL))
 	if err != nil {
 		s.logger.Error("retry failed to add torrent to qBittorrent", zap.Int("id", id), zap.String("title", torrent.FeedItem.Title), zap.String("link", torrent.FeedItem.Link), zap.Error(err))
 		// Persist the updated failure reason (may have changed since the first attempt).
@@ -971,6 +1046,7 @@ func (s *Server) handleRetryQBittorrent(w http.ResponseWriter, r *http.Request,
 	if err := s.store.UpdateStatus(id, "queued"); err != nil {
 		s.logger.Error("failed to update status to queued after retry", zap.Int("id", id), zap.Error(err))
 	}
+	s.linkUpgrade(torrent)
 	if err := s.store.LogActivity(id, torrent.FeedItem.Title, "queue", torrent.MatchReason); err != nil {
 		s.logger.Error("failed to log queue activity after retry", zap.Int("id", id), zap.Error(err))
 	}
@@ -2122,21 +2198,66 @@ func (s *Server) applySettings(cfg settings.AppSettings) {
 				MinConfidence: aqCfg.MinConfidence,
 				HoldMins:      aqCfg.HoldMins,
 				MaxHoldMins:   aqCfg.MaxHoldMins,
+				Upgrades:      aqCfg.Upgrades,
 				DryRun:        aqCfg.DryRun,
 			}, s.autoQueueDeps
 		}
 	}
-	// Matcher default rules hot-reload
+	// Matcher default rules hot-reload. Only the fields the settings page
+	// owns are replaced; the rest of the watchlist defaults (HDR, keyword and
+	// attribute preferences) are kept, and the release constraints override
+	// the watchlist only when set. Constraints start from the watchlist file
+	// rather than the live snapshot, which still holds the previously applied
+	// settings, so clearing one reverts to the watchlist value immediately.
 	if s.matcher != nil {
-		s.matcher.SetDefaults(models.DefaultRules{
-			MinQuality:      cfg.Match.MinQuality,
-			PreferredCodec:  cfg.Match.PreferredCodec,
-			ExcludeGroups:   cfg.Match.ExcludeGroups,
-			PreferredGroups: cfg.Match.PreferredGroups,
-		})
+		var defaults models.DefaultRules
+		if cur := s.matcher.ShowsConfig(); cur != nil {
+			defaults = cur.Defaults
+		}
+		defaults.MinQuality = cfg.Match.MinQuality
+		defaults.PreferredCodec = cfg.Match.PreferredCodec
+		defaults.ExcludeGroups = cfg.Match.ExcludeGroups
+		defaults.PreferredGroups = cfg.Match.PreferredGroups
+		file := s.watchlistFileDefaults()
+		defaults.MaxQuality, defaults.MinSize, defaults.MaxSize = file.MaxQuality, file.MinSize, file.MaxSize
+		defaults.AllowedSources, defaults.ExcludedSources = file.AllowedSources, file.ExcludedSources
+		if cfg.Match.MaxQuality != "" {
+			defaults.MaxQuality = cfg.Match.MaxQuality
+		}
+		if cfg.Match.MinSize > 0 {
+			defaults.MinSize = cfg.Match.MinSize
+		}
+		if cfg.Match.MaxSize > 0 {
+			defaults.MaxSize = cfg.Match.MaxSize
+		}
+		if len(cfg.Match.AllowedSources) > 0 {
+			defaults.AllowedSources = cfg.Match.AllowedSources
+		}
+		if len(cfg.Match.ExcludedSources) > 0 {
+			defaults.ExcludedSources = cfg.Match.ExcludedSources
+		}
+		s.matcher.SetDefaults(defaults)
 	}
 }
 
+// watchlistFileDefaults returns the defaults as written in the watchlist
+// file, or zero rules when it cannot be read.
+func (s *Server) watchlistFileDefaults() models.DefaultRules {
+	path := s.showsPath
+	if path == "" {
+		path = "watchlist.json"
+	}
+	var cfg models.ShowsConfig
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &cfg)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.logger.Warn("could not read watchlist defaults", zap.String("path", path), zap.Error(err))
+	}
+	return cfg.Defaults
+}
+
 // handleSettings serves GET /api/settings and PATCH /api/settings.
 func (s *Server) handleSettings(w http.ResponseWriter, r *http.Request) {
 	if s.settingsMgr == nil {
@@ -2285,28 +2406,18 @@ func (s *Server) handleWatchlist(w http.ResponseWriter, r *http.Request) {
 		if cfg.Movies == nil {
 			cfg.Movies = []models.MovieRule{}
 		}
-
-		// Marshal to canonical pretty-printed JSON and write to disk.
-		out, err := json.MarshalIndent(cfg, "", "  ")
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(ErrorResponse{Error: "failed to serialise config: " + err.Error()})
+		if err := matcher.ValidateShowsConfig(&cfg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
 			return
 		}
-		writePath := s.showsPath
-		if writePath == "" {
-			writePath = "watchlist.json"
-		}
-		if err := os.WriteFile(writePath, out, 0o644); err != nil {
+
+		if err := s.saveWatchlist(&cfg); err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(ErrorResponse{Error: "failed to write watchlist.json: " + err.Error()})
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
 			return
 		}
 
-		// Hot-reload the matcher.
-		s.matcher.SetShowsConfig(&cfg)
-		s.logger.Info("watchlist.json reloaded", zap.Int("shows", len(cfg.Shows)), zap.Int("movies", len(cfg.Movies)))
-
 		json.NewEncoder(w).Encode(WatchlistResponse{
 			ShowsConfig: cfg,
 			ShowsCount:  len(cfg.Shows),
@@ -2319,6 +2430,26 @@ func (s *Server) handleWatchlist(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// saveWatchlist writes cfg to disk as canonical pretty-printed JSON and
+// hot-reloads the matcher with it. cfg must already be validated.
+func (s *Server) saveWatchlist(cfg *models.ShowsConfig) error {
+	out, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialise config: %w", err)
+	}
+	writePath := s.showsPath
+	if writePath == "" {
+		writePath = "watchlist.json"
+	}
+	if err := os.WriteFile(writePath, out, 0o644); err != nil {
+		return fmt.Errorf("failed to write watchlist.json: %w", err)
+	}
+
+	s.matcher.SetShowsConfig(cfg)
+	s.logger.Info("watchlist.json reloaded", zap.Int("shows", len(cfg.Shows)), zap.Int("movies", len(cfg.Movies)), zap.Int("profiles", len(cfg.Profiles)))
+	return nil
+}
+
 // handleAutoQueue triggers an on-demand auto-queue job.
 // POST /api/auto-queue — returns 202 or 409 if already active.
 func (s *Server) handleAutoQueue(w http.ResponseWriter, r *http.Request) {
@@ -2342,6 +2473,7 @@ func (s *Server) handleAutoQueue(w http.ResponseWriter, r *http.Request) {
 			MinConfidence: st.MinConfidence,
 			HoldMins:      st.HoldMins,
 			MaxHoldMins:   st.MaxHoldMins,
+			Upgrades:      st.Upgrades,
 			DryRun:        st.DryRun,
 		}
 	} else {
@@ -2382,6 +2514,7 @@ func (s *Server) handleAutoQueuePreview(w http.ResponseWriter, r *http.Request)
 			MinConfidence: st.MinConfidence,
 			HoldMins:      st.HoldMins,
 			MaxHoldMins:   st.MaxHoldMins,
+			Upgrades:      st.Upgrades,
 			DryRun:        true,
 		}
 	} else {
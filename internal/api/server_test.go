@@ -2,14 +2,20 @@ package api
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/killakam3084/rss-curator/internal/logbuffer"
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/internal/settings"
 	"github.com/killakam3084/rss-curator/internal/storage"
 	"github.com/killakam3084/rss-curator/pkg/models"
 	"go.uber.org/zap"
@@ -20,6 +26,8 @@ type mockStorage struct {
 	torrents   map[int]*models.StagedTorrent
 	activities []models.Activity
 	jobs       map[int]*models.JobRecord
+	feeds      map[int]*models.ManagedFeed
+	nextFeedID int
 }
 
 // Get returns a torrent by ID
@@ -47,6 +55,10 @@ func (m *mockStorage) Add(torrent models.StagedTorrent) error {
 	return nil
 }
 
+func (m *mockStorage) IsStaged(link, infoHash string) (bool, error) {
+	return false, nil
+}
+
 // UpdateStatus updates a torrent's status
 func (m *mockStorage) UpdateStatus(id int, status string) error {
 	if t, ok := m.torrents[id]; ok {
@@ -126,10 +138,11 @@ func (m *mockStorage) UpdateAIScore(id int, score float64, reason string, confid
 }
 
 // UpdateAfterRematch persists rematch changes for a torrent.
-func (m *mockStorage) UpdateAfterRematch(id int, item models.FeedItem, matchReason, status string) error {
+func (m *mockStorage) UpdateAfterRematch(id int, item models.FeedItem, matchReason, status string, releaseScore int) error {
 	if t, ok := m.torrents[id]; ok {
 		t.FeedItem = item
 		t.MatchReason = matchReason
+		t.ReleaseScore = releaseScore
 		t.Status = status
 		t.AIScore = 0
 		t.AIReason = ""
@@ -184,10 +197,64 @@ func (m *mockStorage) CancelJob(id int, summary any) error {
 func (m *mockStorage) ListJobs(limit int, statusFilter string) ([]models.JobRecord, error) {
 	return []models.JobRecord{}, nil
 }
-func (m *mockStorage) GetJob(id int) (*models.JobRecord, error)         { return m.jobs[id], nil }
-func (m *mockStorage) MarkStaleJobsFailed(reason string) (int64, error) { return 0, nil }
-func (m *mockStorage) GetSetting(key string) (string, error)            { return "", nil }
-func (m *mockStorage) SetSetting(key, value string) error               { return nil }
+func (m *mockStorage) GetJob(id int) (*models.JobRecord, error)           { return m.jobs[id], nil }
+func (m *mockStorage) MarkStaleJobsFailed(reason string) (int64, error)   { return 0, nil }
+func (m *mockStorage) GetFeedState(url string) (*models.FeedState, error) { return nil, nil }
+func (m *mockStorage) SetFeedValidators(url, etag, lastModified string) error {
+	return nil
+}
+func (m *mockStorage) ListFeedStates() ([]models.FeedState, error) {
+	return []models.FeedState{}, nil
+}
+func (m *mockStorage) RecordFeedSuccess(url string, items int, latency time.Duration) error {
+	return nil
+}
+func (m *mockStorage) RecordFeedFailure(url, errMsg string, latency time.Duration, nextAttempt time.Time) error {
+	return nil
+}
+func (m *mockStorage) ListFeeds() ([]models.ManagedFeed, error) {
+	out := []models.ManagedFeed{}
+	for id := 1; id <= m.nextFeedID; id++ {
+		if f, ok := m.feeds[id]; ok {
+			out = append(out, *f)
+		}
+	}
+	return out, nil
+}
+func (m *mockStorage) GetFeed(id int) (*models.ManagedFeed, error) {
+	if f, ok := m.feeds[id]; ok {
+		cp := *f
+		return &cp, nil
+	}
+	return nil, nil
+}
+func (m *mockStorage) CreateFeed(f models.ManagedFeed) (int, error) {
+	for _, existing := range m.feeds {
+		if existing.URL == f.URL {
+			return 0, fmt.Errorf("UNIQUE constraint failed: feeds.url")
+		}
+	}
+	m.nextFeedID++
+	f.ID = m.nextFeedID
+	m.feeds[f.ID] = &f
+	return f.ID, nil
+}
+func (m *mockStorage) UpdateFeed(f models.ManagedFeed) error {
+	if _, ok := m.feeds[f.ID]; !ok {
+		return sql.ErrNoRows
+	}
+	m.feeds[f.ID] = &f
+	return nil
+}
+func (m *mockStorage) DeleteFeed(id int) error {
+	if _, ok := m.feeds[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(m.feeds, id)
+	return nil
+}
+func (m *mockStorage) GetSetting(key string) (string, error) { return "", nil }
+func (m *mockStorage) SetSetting(key, value string) error    { return nil }
 func (m *mockStorage) GetAllSettings() (map[string]string, error) {
 	return map[string]string{}, nil
 }
@@ -196,6 +263,8 @@ func (m *mockStorage) GetGroupReputationStats() (map[string]float64, error) {
 	return map[string]float64{}, nil
 }
 func (m *mockStorage) SetFailed(id int, reason string) error                       { return nil }
+func (m *mockStorage) SetSupersededBy(id, upgradeID int) error                     { return nil }
+func (m *mockStorage) SetReleaseScore(id, score int) error                         { return nil }
 func (m *mockStorage) UpsertSuggestions(suggestions []storage.SuggestionRow) error { return nil }
 func (m *mockStorage) ListSuggestions() ([]storage.SuggestionRow, error)           { return nil, nil }
 func (m *mockStorage) DismissSuggestion(showName string, until time.Time) error    { return nil }
@@ -210,6 +279,7 @@ func setupTestServer(t *testing.T) (*Server, *mockStorage) {
 		torrents:   make(map[int]*models.StagedTorrent),
 		activities: []models.Activity{},
 		jobs:       make(map[int]*models.JobRecord),
+		feeds:      make(map[int]*models.ManagedFeed),
 	}
 
 	return &Server{
@@ -696,3 +766,39 @@ func TestHandleListMethodNotAllowed(t *testing.T) {
 		t.Fatalf("expected 405, got %d", w.Code)
 	}
 }
+
+// TestApplySettings_ClearedConstraintReverts verifies that clearing a release
+// constraint in the settings page restores the watchlist file's value without
+// a restart.
+func TestApplySettings_ClearedConstraintReverts(t *testing.T) {
+	server, _ := setupTestServer(t)
+	server.showsPath = filepath.Join(t.TempDir(), "watchlist.json")
+	cfg := &models.ShowsConfig{
+		Shows:    []models.ShowRule{{Name: "Severance"}},
+		Defaults: models.DefaultRules{MaxSize: 10, PreferredHDR: []string{"dv"}},
+	}
+	data, _ := json.Marshal(cfg)
+	if err := os.WriteFile(server.showsPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	server.matcher = matcher.NewMatcher(cfg, nil)
+
+	var app settings.AppSettings
+	app.Match.MaxSize = 5
+	app.Match.AllowedSources = []string{"WEB-DL"}
+	server.applySettings(app)
+	if d := server.matcher.ShowsConfig().Defaults; d.MaxSize != 5 || len(d.AllowedSources) != 1 {
+		t.Fatalf("settings not applied: %+v", d)
+	}
+
+	app.Match.MaxSize = 0
+	app.Match.AllowedSources = nil
+	server.applySettings(app)
+	d := server.matcher.ShowsConfig().Defaults
+	if d.MaxSize != 10 || len(d.AllowedSources) != 0 {
+		t.Errorf("cleared settings did not revert to the watchlist: max_size=%v allowed_sources=%v", d.MaxSize, d.AllowedSources)
+	}
+	if len(d.PreferredHDR) != 1 {
+		t.Errorf("watchlist-only defaults lost: %+v", d)
+	}
+}
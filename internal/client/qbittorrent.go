@@ -3,10 +3,12 @@ package client
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	qbt "github.com/autobrr/go-qbittorrent"
+	"github.com/killakam3084/rss-curator/internal/torrentfile"
 	"github.com/killakam3084/rss-curator/pkg/models"
 )
 
@@ -127,9 +129,79 @@ func (c *Client) AddTorrent(url string, options map[string]string) error {
 	return nil
 }
 
+// AddTorrentWithAuth adds a torrent like AddTorrent, but when auth carries
+// feed credentials and url is an http(s) link, the .torrent is downloaded here
+// with the feed's headers, cookies and passkey applied and uploaded to
+// qBittorrent as a file — qBittorrent cannot send custom headers when it
+// fetches a URL itself. Local file:// links are uploaded from disk, but only
+// from inside auth.LocalDir (the watch directory's processed/ folder).
+func (c *Client) AddTorrentWithAuth(url string, options map[string]string, auth models.FeedAuth) error {
+	if !torrentfile.IsFileURL(url) && (auth.IsZero() || !isHTTPURL(url)) {
+		return c.AddTorrent(url, options)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := c.addOnce(ctx, url, c.mergeOptions(options), auth); err != nil {
+		fmt.Printf("[QBittorrent] Failed to add authenticated torrent: %s\n", extractErrorDetails(err))
+		return fmt.Errorf("failed to add torrent: %w", err)
+	}
+	fmt.Printf("[QBittorrent] Successfully added torrent via authenticated download: %s\n", url)
+	return nil
+}
+
+// mergeOptions layers caller options over the client defaults (category,
+// savepath, paused), dropping the "title" pseudo-option.
+func (c *Client) mergeOptions(options map[string]string) map[string]string {
+	opts := map[string]string{
+		"category": c.category,
+		"paused":   strconv.FormatBool(c.addPaused),
+	}
+	if c.savePath != "" {
+		opts["savepath"] = c.savePath
+	}
+	for k, v := range options {
+		if k != "title" {
+			opts[k] = v
+		}
+	}
+	return opts
+}
+
+// addOnce performs a single add attempt. Local file:// torrents are read from
+// disk and uploaded; with credentials the .torrent is fetched locally and
+// uploaded; otherwise qBittorrent fetches the URL. A 202 Accepted response is
+// treated as success.
+func (c *Client) addOnce(ctx context.Context, url string, opts map[string]string, auth models.FeedAuth) error {
+	var err error
+	if torrentfile.IsFileURL(url) {
+		var buf []byte
+		buf, err = torrentfile.ReadFileURL(url, auth.LocalDir)
+		if err != nil {
+			return err
+		}
+		_, err = c.qb.AddTorrentFromMemoryCtx(ctx, buf, opts)
+	} else if !auth.IsZero() && isHTTPURL(url) {
+		var buf []byte
+		buf, err = torrentfile.Fetch(ctx, url, auth)
+		if err != nil {
+			return err
+		}
+		_, err = c.qb.AddTorrentFromMemoryCtx(ctx, buf, opts)
+	} else {
+		_, err = c.qb.AddTorrentFromUrlCtx(ctx, url, opts)
+	}
+	if err != nil && !isQBit202Accepted(err) {
+		return err
+	}
+	return nil
+}
+
 // RetryAddTorrent attempts to add a torrent with exponential backoff retry logic
-// This is designed for manual retries from the UI when initial add fails
-func (c *Client) RetryAddTorrent(ctx context.Context, url string, opts map[string]string) error {
+// This is designed for manual retries from the UI when initial add fails.
+// auth carries the originating feed's credentials (zero value for none).
+func (c *Client) RetryAddTorrent(ctx context.Context, url string, opts map[string]string, auth models.FeedAuth) error {
 	fmt.Printf("[QBittorrent] Retry: Adding torrent from URL: %s\n", url)
 
 	var lastErr error
@@ -153,11 +225,9 @@ func (c *Client) RetryAddTorrent(ctx context.Context, url string, opts map[strin
 		}
 
 		fmt.Printf("[QBittorrent] Retry attempt %d: Adding torrent from URL: %s\n", attempt+1, url)
-		_, err := c.qb.AddTorrentFromUrlCtx(ctx, url, opts)
-		if err == nil || isQBit202Accepted(err) {
-			if err != nil {
-				fmt.Printf("[QBittorrent] Got 202 Accepted (treating as success) on attempt %d\n", attempt+1)
-			} else if attempt > 0 {
+		err := c.addOnce(ctx, url, opts, auth)
+		if err == nil {
+			if attempt > 0 {
 				fmt.Printf("[QBittorrent] Successfully added torrent on retry attempt %d\n", attempt)
 			}
 			return nil
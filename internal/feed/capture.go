@@ -0,0 +1,157 @@
+package feed
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// Capture is one recorded feed response. On disk each capture is a pair of
+// files sharing a stem: <stem>.json holds this metadata and <stem>.xml the raw
+// body exactly as served.
+type Capture struct {
+	URL          string             `json:"url"` // unexpanded feed URL; no passkey
+	ContentType  models.ContentType `json:"content_type"`
+	CapturedAt   time.Time          `json:"captured_at"`
+	ETag         string             `json:"etag,omitempty"`
+	LastModified string             `json:"last_modified,omitempty"`
+	File         string             `json:"file"` // body file name, relative to the capture dir
+	Body         []byte             `json:"-"`
+}
+
+// WithCapture makes the parser save every successful (200) feed response to
+// dir for later replay. Bodies are written verbatim and may contain
+// passkey-bearing download links, so the directory is created 0700 and files
+// 0600. Capture errors are logged and never fail the fetch.
+func (p *Parser) WithCapture(dir string) *Parser {
+	p.captureDir = dir
+	return p
+}
+
+// saveCapture writes c (with its Body) into dir.
+func saveCapture(dir string, c Capture) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	sum := sha1.Sum([]byte(c.URL))
+	stem := c.CapturedAt.UTC().Format("20060102T150405.000000000Z") + "-" + hex.EncodeToString(sum[:4])
+	c.File = stem + ".xml"
+	if err := os.WriteFile(filepath.Join(dir, c.File), c.Body, 0o600); err != nil {
+		return err
+	}
+	meta, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, stem+".json"), meta, 0o600)
+}
+
+// LoadCaptures reads every capture in dir, bodies included, ordered by
+// CapturedAt (then URL for identical timestamps).
+func LoadCaptures(dir string) ([]Capture, error) {
+	metas, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var out []Capture
+	for _, path := range metas {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var c Capture
+		if err := json.Unmarshal(raw, &c); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		if c.URL == "" || c.File == "" {
+			return nil, fmt.Errorf("%s: not a feed capture (missing url or file)", filepath.Base(path))
+		}
+		if c.Body, err = os.ReadFile(filepath.Join(dir, filepath.Base(c.File))); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].CapturedAt.Equal(out[j].CapturedAt) {
+			return out[i].CapturedAt.Before(out[j].CapturedAt)
+		}
+		return out[i].URL < out[j].URL
+	})
+	return out, nil
+}
+
+// ReplayRounds groups chronologically ordered captures into feed-check
+// rounds: round n holds the n-th capture of every URL, so a directory with a
+// single capture per feed replays as one run and repeated captures replay in
+// the order they were taken.
+func ReplayRounds(captures []Capture) [][]Capture {
+	var rounds [][]Capture
+	seen := map[string]int{}
+	for _, c := range captures {
+		n := seen[c.URL]
+		seen[c.URL]++
+		if n == len(rounds) {
+			rounds = append(rounds, nil)
+		}
+		rounds[n] = append(rounds[n], c)
+	}
+	return rounds
+}
+
+// NewReplayParser returns a Parser whose HTTP client serves captures instead
+// of the network. Requests for a URL without a capture fail, so a replay can
+// never reach a tracker.
+func NewReplayParser(captures []Capture) *Parser {
+	rt := replayTransport{}
+	for _, c := range captures {
+		rt[replayKey(c.URL)] = c
+	}
+	return &Parser{client: &http.Client{Transport: rt}}
+}
+
+// replayTransport maps a normalized request URL to its capture.
+type replayTransport map[string]Capture
+
+func (rt replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c, ok := rt[replayKey(req.URL.String())]
+	if !ok {
+		return nil, fmt.Errorf("no capture for %s", req.URL.Redacted())
+	}
+	h := http.Header{}
+	if c.ETag != "" {
+		h.Set("ETag", c.ETag)
+	}
+	if c.LastModified != "" {
+		h.Set("Last-Modified", c.LastModified)
+	}
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     h,
+		Body:       io.NopCloser(bytes.NewReader(c.Body)),
+		Request:    req,
+	}, nil
+}
+
+// replayKey normalizes a URL the way net/http will render it on the request.
+func replayKey(raw string) string {
+	if u, err := url.Parse(strings.TrimSpace(raw)); err == nil {
+		return u.String()
+	}
+	return raw
+}
@@ -0,0 +1,96 @@
+package feed
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+const captureRSS = `<?xml version="1.0"?>
+<rss version="2.0"><channel>
+<item><title>Show.Name.S01E01.1080p.WEB-DL-GRP</title><link>http://tracker.example/dl/1</link></item>
+</channel></rss>`
+
+func TestCaptureAndReplay(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, captureRSS)
+	}))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "captures")
+	feedURL := srv.URL + "/rss?passkey={passkey}"
+	live, err := NewParser().WithCapture(dir).ParseConditional(feedURL, models.ContentTypeShow,
+		FetchOptions{Auth: models.FeedAuth{Passkey: "secret"}})
+	if err != nil {
+		t.Fatalf("ParseConditional: %v", err)
+	}
+
+	captures, err := LoadCaptures(dir)
+	if err != nil {
+		t.Fatalf("LoadCaptures: %v", err)
+	}
+	if len(captures) != 1 {
+		t.Fatalf("expected 1 capture, got %d", len(captures))
+	}
+	c := captures[0]
+	if c.URL != feedURL {
+		t.Errorf("URL = %q, want the unexpanded %q", c.URL, feedURL)
+	}
+	if c.ContentType != models.ContentTypeShow || c.ETag != `"v1"` || c.CapturedAt.IsZero() {
+		t.Errorf("capture metadata = %+v", c)
+	}
+	if string(c.Body) != captureRSS {
+		t.Errorf("Body not captured verbatim: %q", c.Body)
+	}
+	if fi, err := os.Stat(filepath.Join(dir, c.File)); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("body file mode = %v (err %v), want 0600", fi.Mode().Perm(), err)
+	}
+
+	// Replay goes through the same fetch path without touching the server.
+	srv.Close()
+	replayed, err := NewReplayParser(captures).ParseConditional(c.URL, c.ContentType, FetchOptions{})
+	if err != nil {
+		t.Fatalf("replay ParseConditional: %v", err)
+	}
+	if hits != 1 {
+		t.Errorf("server hit %d times, want 1", hits)
+	}
+	if len(replayed.Items) != 1 || replayed.Items[0].Title != live.Items[0].Title || replayed.Items[0].FeedURL != feedURL {
+		t.Errorf("replayed items = %+v, want %+v", replayed.Items, live.Items)
+	}
+	if replayed.Validators.ETag != `"v1"` {
+		t.Errorf("replayed ETag = %q", replayed.Validators.ETag)
+	}
+
+	if _, err := NewReplayParser(captures).Parse("http://other.example/rss", models.ContentTypeShow); err == nil {
+		t.Error("expected an error for a URL without a capture")
+	}
+}
+
+func TestReplayRounds(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	captures := []Capture{
+		{URL: "a", CapturedAt: t0},
+		{URL: "b", CapturedAt: t0.Add(time.Second)},
+		{URL: "a", CapturedAt: t0.Add(time.Hour)},
+	}
+	rounds := ReplayRounds(captures)
+	if len(rounds) != 2 {
+		t.Fatalf("expected 2 rounds, got %d", len(rounds))
+	}
+	if len(rounds[0]) != 2 || rounds[0][0].URL != "a" || rounds[0][1].URL != "b" {
+		t.Errorf("round 1 = %+v", rounds[0])
+	}
+	if len(rounds[1]) != 1 || !rounds[1][0].CapturedAt.Equal(t0.Add(time.Hour)) {
+		t.Errorf("round 2 = %+v", rounds[1])
+	}
+}
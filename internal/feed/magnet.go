@@ -0,0 +1,78 @@
+package feed
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Magnet is the subset of a magnet URI the curator cares about.
+type Magnet struct {
+	InfoHash string   // normalized: lowercase 40-char hex
+	Name     string   // dn (display name), may be empty
+	Trackers []string // tr parameters in order of appearance
+}
+
+// IsMagnet reports whether link is a magnet URI.
+func IsMagnet(link string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(link)), "magnet:")
+}
+
+// ParseMagnet extracts the BitTorrent info-hash (xt=urn:btih:…), display name
+// (dn) and trackers (tr) from a magnet URI. It returns an error when the URI
+// is not a magnet link or carries no valid btih.
+func ParseMagnet(uri string) (Magnet, error) {
+	uri = strings.TrimSpace(uri)
+	if !IsMagnet(uri) {
+		return Magnet{}, errors.New("not a magnet URI")
+	}
+	// Everything after "magnet:?" is a plain query string.
+	values, err := url.ParseQuery(strings.TrimPrefix(uri[len("magnet:"):], "?"))
+	if err != nil {
+		return Magnet{}, err
+	}
+
+	var m Magnet
+	for _, xt := range values["xt"] {
+		if len(xt) < len("urn:btih:") || !strings.EqualFold(xt[:len("urn:btih:")], "urn:btih:") {
+			continue
+		}
+		if h := NormalizeInfoHash(xt[len("urn:btih:"):]); h != "" {
+			m.InfoHash = h
+			break
+		}
+	}
+	if m.InfoHash == "" {
+		return Magnet{}, errors.New("magnet URI has no valid btih")
+	}
+	m.Name = strings.TrimSpace(values.Get("dn"))
+	for _, tr := range values["tr"] {
+		if tr = strings.TrimSpace(tr); tr != "" {
+			m.Trackers = append(m.Trackers, tr)
+		}
+	}
+	return m, nil
+}
+
+// NormalizeInfoHash returns a v1 BitTorrent info-hash as lowercase 40-char
+// hex. Magnet links may carry the hash base32-encoded (32 chars); those are
+// converted. Anything else yields "".
+func NormalizeInfoHash(s string) string {
+	s = strings.TrimSpace(s)
+	switch len(s) {
+	case 40:
+		if _, err := hex.DecodeString(s); err != nil {
+			return ""
+		}
+		return strings.ToLower(s)
+	case 32:
+		b, err := base32.StdEncoding.DecodeString(strings.ToUpper(s))
+		if err != nil || len(b) != 20 {
+			return ""
+		}
+		return hex.EncodeToString(b)
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package feed
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+const testHash = "abcdef0123456789abcdef0123456789abcdef01"
+
+func TestParseMagnet(t *testing.T) {
+	m, err := ParseMagnet("magnet:?xt=urn:btih:ABCDEF0123456789ABCDEF0123456789ABCDEF01" +
+		"&dn=Show.Name.S01E01.1080p.WEB-DL-GRP" +
+		"&tr=udp%3A%2F%2Ftracker.example%3A1337&tr=https%3A%2F%2Ftracker.example%2Fannounce")
+	if err != nil {
+		t.Fatalf("ParseMagnet: %v", err)
+	}
+	if m.InfoHash != testHash {
+		t.Errorf("InfoHash = %q, want %q", m.InfoHash, testHash)
+	}
+	if m.Name != "Show.Name.S01E01.1080p.WEB-DL-GRP" {
+		t.Errorf("Name = %q", m.Name)
+	}
+	if len(m.Trackers) != 2 || m.Trackers[0] != "udp://tracker.example:1337" {
+		t.Errorf("Trackers = %v", m.Trackers)
+	}
+
+	for _, bad := range []string{
+		"http://example.com/a.torrent",
+		"magnet:?dn=no-hash",
+		"magnet:?xt=urn:btih:nothex",
+		"magnet:?xt=urn:btmh:1220" + testHash,
+	} {
+		if _, err := ParseMagnet(bad); err == nil {
+			t.Errorf("ParseMagnet(%q): expected error", bad)
+		}
+	}
+}
+
+func TestNormalizeInfoHash(t *testing.T) {
+	cases := map[string]string{
+		testHash: testHash,
+		"  ABCDEF0123456789ABCDEF0123456789ABCDEF01": testHash,
+		"VPG66AJDIVTYTK6N54ASGRLHRGV433YB":           testHash, // base32
+		"vpg66ajdivtytk6n54asgrlhrgv433yb":           testHash,
+		"":                                           "",
+		"abc":                                        "",
+		"zzcdef0123456789abcdef0123456789abcdef01": "",
+	}
+	for in, want := range cases {
+		if got := NormalizeInfoHash(in); got != want {
+			t.Errorf("NormalizeInfoHash(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParse_MagnetItems(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0"?>
+<rss version="2.0"><channel>
+<item>
+  <title>Show.Name.S01E02.1080p.WEB-DL-GRP</title>
+  <link>magnet:?xt=urn:btih:VPG66AJDIVTYTK6N54ASGRLHRGV433YB&amp;dn=ignored</link>
+</item>
+<item>
+  <title></title>
+  <enclosure url="magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&amp;dn=Show.Name.S01E03.720p.HDTV-GRP" type="application/x-bittorrent"/>
+</item>
+</channel></rss>`)
+	}))
+	defer srv.Close()
+
+	items, err := NewParser().Parse(srv.URL, models.ContentTypeShow)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].InfoHash != testHash {
+		t.Errorf("InfoHash = %q, want %q (from base32 btih)", items[0].InfoHash, testHash)
+	}
+	if items[0].Title != "Show.Name.S01E02.1080p.WEB-DL-GRP" {
+		t.Errorf("Title = %q, feed title should win over dn", items[0].Title)
+	}
+
+	enc := items[1]
+	if !IsMagnet(enc.Link) {
+		t.Errorf("Link = %q, want the enclosure magnet", enc.Link)
+	}
+	if enc.InfoHash != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("InfoHash = %q", enc.InfoHash)
+	}
+	if enc.Title != "Show.Name.S01E03.720p.HDTV-GRP" || enc.Episode != 3 {
+		t.Errorf("Title = %q Episode = %d, want dn-derived title and metadata", enc.Title, enc.Episode)
+	}
+}
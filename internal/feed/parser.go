@@ -1,10 +1,13 @@
 package feed
 
 import (
+	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"sort"
 	"strconv"
@@ -24,15 +27,26 @@ var (
 	groupRe           = regexp.MustCompile(`-([A-Za-z0-9]+)(?:\[.*\])?$`)
 	yearRe            = regexp.MustCompile(`(?:^|[\s.])\(?((19|20)\d{2})\)?(?:[\s.]|$)`)
 	movieQualityRe    = regexp.MustCompile(`(?i)\b(2160p|1080p|720p|4K)\b.*`)
-	seasonEpisodeRe   = regexp.MustCompile(`^(.+?)[\s.]+[Ss](\d+)(?:[Ee](\d+))?`)
+	seasonEpisodeRe   = regexp.MustCompile(`^(.+?)[\s.]+[Ss](\d+)((?:[Ee]\d+)(?:-?[Ee]\d+|-\d{1,3}\b)*)?`)
+	episodeNumRe      = regexp.MustCompile(`\d+`)
+	airDateRe         = regexp.MustCompile(`^(.+?)[\s.]+((?:19|20)\d{2})[\s.-](\d{2})[\s.-](\d{2})\b`)
 	resolutionTrailRe = regexp.MustCompile(`\d{4}p.*`)
+	revisionRe        = regexp.MustCompile(`(?i)\b(PROPER|REPACK|RERIP)(\d)?\b`)
+	realRe            = regexp.MustCompile(`\bREAL\b`) // case-sensitive: "Real" is common in show names
+	audioRe           = regexp.MustCompile(`(?i)(?:^|[\s._\-\[(])(DDP|DD\+|E-?AC-?3|DD|AC-?3|AAC|FLAC|OPUS|TRUE-?HD|ATMOS|DTS-?HD(?:[\s.-]?MA)?|DTS-?X|DTS)(?:[\s.]?([1-9]\.[0-2]))?`)
+	bitDepthRe        = regexp.MustCompile(`(?i)\b(?:(8|10|12)[\s.-]?bits?|Hi10P?)\b`)
+	editionRe         = regexp.MustCompile(`(?i)\b(Extended(?:[\s.](?:Cut|Edition))?|Director'?s[\s.]Cut|IMAX|Unrated|Uncut|Theatrical(?:[\s.]Cut)?|Remastered|Final[\s.]Cut|Criterion)\b`)
+	languageRe        = regexp.MustCompile(`(?i)\b(MULTI|DUAL(?:[\s.-]?AUDIO)?|ENGLISH|TRUEFRENCH|FRENCH|VFF|GERMAN|ITALIAN|SPANISH|CASTELLANO|LATINO|JAPANESE|KOREAN|CHINESE|MANDARIN|CANTONESE|RUSSIAN|HINDI|PORTUGUESE|DUTCH|SWEDISH|NORWEGIAN|DANISH|FINNISH|POLISH|NORDIC)\b`)
+	subbedRe          = regexp.MustCompile(`(?i)\b(SUBBED|SUBS|VOSTFR|HARDSUBS?)\b`)
+	dubbedRe          = regexp.MustCompile(`(?i)\b(DUBBED|DUB)\b`)
 	hdrRe             = regexp.MustCompile(`(?i)\b(DoVi|Dolby[\s.]?Vision|HDR10\+|HDR10Plus|HDR10|HDR|HLG|DV)\b`)
 )
 
 // Parser handles RSS feed parsing
 type Parser struct {
-	client   *http.Client
-	enricher *ai.Enricher
+	client     *http.Client
+	enricher   *ai.Enricher
+	captureDir string // when set, raw 200 responses are saved here (see WithCapture)
 }
 
 // NewParser creates a new RSS parser
@@ -67,24 +81,140 @@ type enclosure struct {
 	Type   string `xml:"type,attr"`
 }
 
+// indexerAttr is a Torznab (<torznab:attr>) or Newznab (<newznab:attr>)
+// name/value pair. The namespace is left unqualified so both prefixes match.
+type indexerAttr struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
 type item struct {
-	Title       string    `xml:"title"`
-	Link        string    `xml:"link"`
-	GUID        string    `xml:"guid"`
-	PubDate     string    `xml:"pubDate"`
-	Description string    `xml:"description"`
-	Enclosure   enclosure `xml:"enclosure"`
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        string        `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Description string        `xml:"description"`
+	Enclosure   enclosure     `xml:"enclosure"`
+	Attrs       []indexerAttr `xml:"attr"`
+}
+
+// Atom feed structures
+type atomFeed struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length int64  `xml:"length,attr"`
+}
+
+type atomEntry struct {
+	Title     string     `xml:"title"`
+	ID        string     `xml:"id"`
+	Updated   string     `xml:"updated"`
+	Published string     `xml:"published"`
+	Summary   string     `xml:"summary"`
+	Content   string     `xml:"content"`
+	Links     []atomLink `xml:"link"`
+}
+
+// pubDateFormats lists the date layouts tried, in order, for RSS pubDate and
+// Atom published/updated values.
+var pubDateFormats = []string{
+	time.RFC1123Z,                    // Mon, 02 Jan 2006 15:04:05 -0700
+	time.RFC1123,                     // Mon, 02 Jan 2006 15:04:05 MST
+	"Mon, 2 Jan 2006 15:04:05 -0700", // single-digit day, numeric tz
+	"Mon, 2 Jan 2006 15:04:05 MST",   // single-digit day, named tz
+	time.RFC3339,
+}
+
+// Validators are the HTTP cache validators carried between fetches of the
+// same feed for conditional GETs.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// FetchOptions are the per-feed request options for ParseConditional.
+type FetchOptions struct {
+	// Validators from the previous fetch; sent as conditional GET headers.
+	Validators Validators
+	// Auth is applied to the request via NewRequest.
+	Auth models.FeedAuth
+}
+
+// NewRequest builds a GET request for rawURL with auth applied: {passkey} is
+// expanded in the URL, header values and cookie values, then headers and
+// cookies are set on the request. It is used for both the feed fetch and the
+// .torrent download so private-tracker credentials travel end to end.
+func NewRequest(rawURL string, auth models.FeedAuth) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, auth.Expand(rawURL), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range auth.Headers {
+		req.Header.Set(k, auth.Expand(v))
+	}
+	for name, v := range auth.Cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: auth.Expand(v)})
+	}
+	return req, nil
 }
 
-// Parse fetches and parses an RSS feed. The contentType is applied to every
-// item returned so callers can route show and movie feeds differently.
+// FetchResult is the outcome of a conditional fetch. When NotModified is true
+// the server answered 304, Items is nil, and Validators echoes the ones sent.
+type FetchResult struct {
+	Items       []models.FeedItem
+	Validators  Validators
+	NotModified bool
+}
+
+// Parse fetches and parses an RSS 2.0 or Atom feed. The contentType is applied
+// to every item returned so callers can route show and movie feeds differently.
 func (p *Parser) Parse(feedURL string, contentType models.ContentType) ([]models.FeedItem, error) {
-	resp, err := p.client.Get(feedURL)
+	res, err := p.ParseConditional(feedURL, contentType, FetchOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// ParseConditional is Parse with per-feed request options. opts.Auth is
+// applied to the request, and non-empty opts.Validators are sent as
+// If-None-Match / If-Modified-Since; a 304 response short-circuits parsing
+// and returns NotModified. On a 200 the response's ETag / Last-Modified
+// headers are returned for the next call. Each item's FeedURL is set to the
+// unexpanded feedURL so the passkey is not persisted with it.
+func (p *Parser) ParseConditional(feedURL string, contentType models.ContentType, opts FetchOptions) (*FetchResult, error) {
+	prev := opts.Validators
+	req, err := NewRequest(feedURL, opts.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	if prev.ETag != "" {
+		req.Header.Set("If-None-Match", prev.ETag)
+	}
+	if prev.LastModified != "" {
+		req.Header.Set("If-Modified-Since", prev.LastModified)
+	}
+
+	resp, err := p.client.Do(req)
 	if err != nil {
+		// Report the unexpanded URL so a templated passkey stays out of logs
+		// and the persisted feed error.
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			ue.URL = feedURL
+		}
 		return nil, fmt.Errorf("failed to fetch feed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return &FetchResult{Validators: prev, NotModified: true}, nil
+	}
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
@@ -94,9 +224,93 @@ func (p *Parser) Parse(feedURL string, contentType models.ContentType) ([]models
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
+	validators := Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	// Capture before parsing so a body that fails to parse is still recorded.
+	if p.captureDir != "" {
+		if err := saveCapture(p.captureDir, Capture{
+			URL:          feedURL,
+			ContentType:  contentType,
+			CapturedAt:   time.Now(),
+			ETag:         validators.ETag,
+			LastModified: validators.LastModified,
+			Body:         body,
+		}); err != nil {
+			fmt.Printf("[Feed] WARNING: failed to capture %s: %v\n", feedURL, err)
+		}
+	}
+
+	items, err := p.parseBody(body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].FeedURL = feedURL
+	}
+	return &FetchResult{Items: items, Validators: validators}, nil
+}
+
+// parseBody detects the document root and dispatches to the RSS or Atom
+// decoder. Both paths produce FeedItems that go through the same metadata
+// extraction and optional enrichment.
+func (p *Parser) parseBody(body []byte, contentType models.ContentType) ([]models.FeedItem, error) {
+	root, err := documentRoot(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse XML: %w", err)
+	}
+
+	var items []models.FeedItem
+	switch root {
+	case "feed":
+		items, err = decodeAtom(body)
+	default:
+		items, err = decodeRSS(body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse XML: %w", err)
+	}
+
+	// Only remote links are accepted from a document: a file:// or other
+	// local link would let the feed name a file for the client to upload.
+	kept := items[:0]
+	for i := range items {
+		if !remoteLink(items[i].Link) {
+			continue
+		}
+		p.finishItem(&items[i], contentType)
+		kept = append(kept, items[i])
+	}
+	return kept, nil
+}
+
+// remoteLink reports whether link is an http(s) or magnet link.
+func remoteLink(link string) bool {
+	l := strings.ToLower(strings.TrimSpace(link))
+	return strings.HasPrefix(l, "http://") || strings.HasPrefix(l, "https://") || IsMagnet(link)
+}
+
+// documentRoot returns the local name of the first element in an XML document
+// ("rss" for RSS 2.0, "feed" for Atom).
+func documentRoot(body []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			return se.Name.Local, nil
+		}
+	}
+}
+
+// decodeRSS maps RSS 2.0 <item> elements to FeedItems.
+func decodeRSS(body []byte) ([]models.FeedItem, error) {
 	var feed rss
 	if err := xml.Unmarshal(body, &feed); err != nil {
-		return nil, fmt.Errorf("failed to parse XML: %w", err)
+		return nil, err
 	}
 
 	items := make([]models.FeedItem, 0, len(feed.Channel.Items))
@@ -106,52 +320,182 @@ func (p *Parser) Parse(feedURL string, contentType models.ContentType) ([]models
 			Link:        rssItem.Link,
 			GUID:        rssItem.GUID,
 			Description: rssItem.Description,
+			PubDate:     parsePubDate(rssItem.PubDate),
 		}
 
-		// Parse pub date — try common RSS date formats in order
-		pubDateFormats := []string{
-			time.RFC1123Z,                    // Mon, 02 Jan 2006 15:04:05 -0700
-			time.RFC1123,                     // Mon, 02 Jan 2006 15:04:05 MST
-			"Mon, 2 Jan 2006 15:04:05 -0700", // single-digit day, numeric tz
-			"Mon, 2 Jan 2006 15:04:05 MST",   // single-digit day, named tz
-			time.RFC3339,
-		}
-		for _, layout := range pubDateFormats {
-			if t, err := time.Parse(layout, strings.TrimSpace(rssItem.PubDate)); err == nil {
-				item.PubDate = t
-				break
-			}
+		applyIndexerAttrs(&item, rssItem.Attrs)
+
+		// Some indexers only put the magnet URI in the enclosure.
+		if item.Link == "" {
+			item.Link = strings.TrimSpace(rssItem.Enclosure.URL)
 		}
 
-		// Parse size: prefer <enclosure length="..."> (bytes), fall back to description text
+		// Parse size: prefer <enclosure length="..."> (bytes), then the
+		// indexer size attribute, then fall back to description text
 		if rssItem.Enclosure.Length > 0 {
 			item.Size = rssItem.Enclosure.Length
-		} else {
+		} else if item.Size == 0 {
 			item.Size = parseSize(rssItem.Description)
 		}
 
-		// Extract metadata from title
-		item.ContentType = contentType
-		ParseParserMetadata(&item)
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// decodeAtom maps Atom <entry> elements to FeedItems: id → GUID,
+// published (falling back to updated) → PubDate, and the rel="enclosure" link
+// href/length → Link/Size.
+func decodeAtom(body []byte) ([]models.FeedItem, error) {
+	var feed atomFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, err
+	}
+
+	items := make([]models.FeedItem, 0, len(feed.Entries))
+	for _, entry := range feed.Entries {
+		description := entry.Summary
+		if description == "" {
+			description = entry.Content
+		}
+		item := models.FeedItem{
+			Title:       strings.TrimSpace(entry.Title),
+			GUID:        strings.TrimSpace(entry.ID),
+			Description: description,
+		}
 
-		// Optionally enrich missing fields via AI.
-		if p.enricher != nil {
-			p.enricher.Enrich(&item)
+		item.PubDate = parsePubDate(entry.Published)
+		if item.PubDate.IsZero() {
+			item.PubDate = parsePubDate(entry.Updated)
 		}
 
-		// Log the parsed link for observability
-		if strings.Contains(item.Link, "download") {
-			fmt.Printf("[Feed] Parsed authenticated download link: %s\n", item.Link)
-		} else if strings.Contains(item.Link, "/t/") {
-			fmt.Printf("[Feed] WARNING: Parsed info page link (not authenticated): %s\n", item.Link)
+		// Prefer the enclosure link (the .torrent); fall back to the alternate
+		// link, then to whatever link comes first.
+		var enclosure, alternate, first *atomLink
+		for i := range entry.Links {
+			l := &entry.Links[i]
+			if first == nil {
+				first = l
+			}
+			switch l.Rel {
+			case "enclosure":
+				if enclosure == nil {
+					enclosure = l
+				}
+			case "", "alternate":
+				if alternate == nil {
+					alternate = l
+				}
+			}
+		}
+		switch {
+		case enclosure != nil:
+			item.Link = enclosure.Href
+			item.Size = enclosure.Length
+		case alternate != nil:
+			item.Link = alternate.Href
+		case first != nil:
+			item.Link = first.Href
+		}
+		if item.Size == 0 {
+			item.Size = parseSize(description)
 		}
 
 		items = append(items, item)
 	}
-
 	return items, nil
 }
 
+// applyIndexerAttrs copies recognised Torznab/Newznab attributes onto item.
+// Unknown names and unparseable values are ignored; repeated category
+// attributes accumulate.
+func applyIndexerAttrs(item *models.FeedItem, attrs []indexerAttr) {
+	for _, a := range attrs {
+		v := strings.TrimSpace(a.Value)
+		switch strings.ToLower(a.Name) {
+		case "seeders":
+			if n, err := strconv.Atoi(v); err == nil {
+				item.Seeders = &n
+			}
+		case "peers":
+			if n, err := strconv.Atoi(v); err == nil {
+				item.Peers = &n
+			}
+		case "infohash":
+			item.InfoHash = NormalizeInfoHash(v)
+		case "size":
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+				item.Size = n
+			}
+		case "category":
+			if n, err := strconv.Atoi(v); err == nil {
+				item.Categories = append(item.Categories, n)
+			}
+		case "downloadvolumefactor":
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				item.DownloadVolumeFactor = &f
+			}
+		case "imdbid", "imdb":
+			item.IMDBID = normalizeIMDBID(v)
+		case "tvdbid":
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				item.TVDBID = n
+			}
+		}
+	}
+}
+
+// normalizeIMDBID returns id in canonical "tt" + at-least-7-digit form.
+// Indexers variously emit "tt0903747", "0903747" or "903747".
+func normalizeIMDBID(id string) string {
+	digits := strings.TrimPrefix(strings.ToLower(id), "tt")
+	n, err := strconv.Atoi(digits)
+	if err != nil || n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("tt%07d", n)
+}
+
+// parsePubDate tries each of pubDateFormats in order and returns the zero time
+// when none match.
+func parsePubDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range pubDateFormats {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// finishItem applies the content type, extracts title metadata, runs optional
+// AI enrichment, and logs the link kind. Shared by the RSS and Atom paths.
+func (p *Parser) finishItem(item *models.FeedItem, contentType models.ContentType) {
+	// Magnet links carry the info-hash (and often the release name) inline.
+	if IsMagnet(item.Link) {
+		if m, err := ParseMagnet(item.Link); err == nil {
+			if item.InfoHash == "" {
+				item.InfoHash = m.InfoHash
+			}
+			if strings.TrimSpace(item.Title) == "" {
+				item.Title = m.Name
+			}
+		}
+	}
+
+	// Extract metadata from title
+	item.ContentType = contentType
+	ParseTitleMetadata(item)
+
+	// Optionally enrich missing fields via AI.
+	if p.enricher != nil {
+		p.enricher.Enrich(item)
+	}
+}
+
 // parseSize extracts size from description like "1.44 GB; TV/Web-DL"
 func parseSize(description string) int64 {
 	// Match patterns like "1.44 GB", "500 MB", "21 GB"
@@ -188,22 +532,17 @@ func ParseTitleMetadata(item *models.FeedItem) {
 	item.ShowName = ""
 	item.Season = 0
 	item.Episode = 0
-	item.ReleaseYear = 0
-	item.Quality = ""
-	item.Codec = ""
-	item.Source = ""
-	item.ReleaseGroup = ""
-	item.HDR = nil
-
-	extractMetadata(item)
-}
-
-// ParseParserMetadata is an alias for ParseTitleMetadata kept for internal use
-// inside Parse so the ContentType is preserved after being set by the caller.
-func ParseParserMetadata(item *models.FeedItem) {
-	item.ShowName = ""
-	item.Season = 0
-	item.Episode = 0
+	item.EpisodeEnd = 0
+	item.IsSeasonPack = false
+	item.AirDate = ""
+	item.Revision = 0
+	item.Audio = nil
+	item.AudioChannels = ""
+	item.BitDepth = 0
+	item.Edition = ""
+	item.Languages = nil
+	item.Subbed = false
+	item.Dubbed = false
 	item.ReleaseYear = 0
 	item.Quality = ""
 	item.Codec = ""
@@ -246,6 +585,10 @@ func extractMetadata(item *models.FeedItem) {
 		item.ReleaseGroup = matches[1]
 	}
 
+	tail := releaseTail(title)
+	item.Revision = parseRevision(tail)
+	extractReleaseAttributes(item, tail)
+
 	// Extract HDR formats — all matches, deduplicated and sorted for determinism.
 	if hdrMatches := hdrRe.FindAllStringSubmatch(title, -1); len(hdrMatches) > 0 {
 		seen := make(map[string]bool)
@@ -289,36 +632,189 @@ func extractMetadata(item *models.FeedItem) {
 	}
 
 	// Show: extract show name, season, episode
-	// Pattern: Show.Name.S01E02 or Show.Name.S01 or Show Name S01E02
+	// Patterns: Show.Name.S01E02, Show.Name.S01E01E02, Show.Name.S01E01-E03,
+	// Show.Name.S01 (season pack) or Show.Name.2026.10.15 (daily show)
 	if matches := seasonEpisodeRe.FindStringSubmatch(title); len(matches) >= 3 {
 		// Show name is everything before S01E02
-		showName := matches[1]
-		showName = strings.ReplaceAll(showName, ".", " ")
-		showName = strings.TrimSpace(showName)
-		item.ShowName = showName
+		item.ShowName = cleanShowName(matches[1])
 
 		// Season number
 		if season, err := strconv.Atoi(matches[2]); err == nil {
 			item.Season = season
 		}
 
-		// Episode number (if present)
+		// Episode block: E02, E01E02, E01-E03 or E01-03. The first number is
+		// the episode; the last one closes the range when it is larger.
+		// No episode block at all means the release is a full-season pack.
 		if len(matches) > 3 && matches[3] != "" {
-			if episode, err := strconv.Atoi(matches[3]); err == nil {
-				item.Episode = episode
+			nums := episodeNumRe.FindAllString(matches[3], -1)
+			if first, err := strconv.Atoi(nums[0]); err == nil {
+				item.Episode = first
+			}
+			if last, err := strconv.Atoi(nums[len(nums)-1]); err == nil && last > item.Episode {
+				item.EpisodeEnd = last
 			}
+		} else {
+			item.IsSeasonPack = true
 		}
+	} else if matches := airDateRe.FindStringSubmatch(title); matches != nil && validAirDate(matches[2], matches[3], matches[4]) {
+		// Daily show: the air date stands in for season/episode.
+		item.ShowName = cleanShowName(matches[1])
+		item.AirDate = matches[2] + "-" + matches[3] + "-" + matches[4]
 	} else {
 		// If no season/episode pattern, just use the title as show name
 		// Clean up common separators
 		showName := title
 		showName = resolutionTrailRe.ReplaceAllString(showName, "")
-		showName = strings.ReplaceAll(showName, ".", " ")
-		showName = strings.TrimSpace(showName)
-		item.ShowName = showName
+		item.ShowName = cleanShowName(showName)
 	}
 }
 
+// parseRevision returns how many times a release has been re-issued:
+// PROPER, REPACK and RERIP count as 1 unless numbered (REPACK2 → 2), and each
+// upper-case REAL tag ("REAL.PROPER") adds one more. Originals return 0.
+// Callers pass the release tail so a name like "The Proper Way" is not read
+// as a tag.
+func parseRevision(title string) int {
+	rev := 0
+	for _, m := range revisionRe.FindAllStringSubmatch(title, -1) {
+		n := 1
+		if m[2] != "" {
+			n, _ = strconv.Atoi(m[2])
+		}
+		if n > rev {
+			rev = n
+		}
+	}
+	return rev + len(realRe.FindAllString(title, -1))
+}
+
+// releaseTail returns the part of title after the show or movie name — past
+// the SxxEyy marker, air date or year — so that tag scans do not trip over
+// words in the name itself ("The French Dispatch", "Extended Family").
+func releaseTail(title string) string {
+	for _, re := range []*regexp.Regexp{seasonEpisodeRe, airDateRe, yearRe} {
+		if loc := re.FindStringIndex(title); loc != nil {
+			return title[loc[1]:]
+		}
+	}
+	return title
+}
+
+// extractReleaseAttributes fills audio, bit depth, edition and language
+// fields from the release tags in tail.
+func extractReleaseAttributes(item *models.FeedItem, tail string) {
+	// Audio: a format token may be glued to its channel layout ("DDP5.1") and
+	// directly followed by another format ("DDP5.1.Atmos"), so only a trailing
+	// letter disqualifies a match ("DDR", "Dutch").
+	seen := make(map[string]bool)
+	for _, m := range audioRe.FindAllStringSubmatchIndex(tail, -1) {
+		if m[1] < len(tail) && isASCIILetter(tail[m[1]]) {
+			continue
+		}
+		canonical := normalizeAudioToken(tail[m[2]:m[3]])
+		if !seen[canonical] {
+			seen[canonical] = true
+			item.Audio = append(item.Audio, canonical)
+		}
+		if m[4] >= 0 && item.AudioChannels == "" {
+			item.AudioChannels = tail[m[4]:m[5]]
+		}
+	}
+	sort.Strings(item.Audio)
+
+	if m := bitDepthRe.FindStringSubmatch(tail); m != nil {
+		item.BitDepth = 10 // Hi10/Hi10P
+		if m[1] != "" {
+			item.BitDepth, _ = strconv.Atoi(m[1])
+		}
+	}
+
+	if m := editionRe.FindStringSubmatch(tail); m != nil {
+		item.Edition = normalizeEditionToken(m[1])
+	}
+
+	seen = make(map[string]bool)
+	for _, m := range languageRe.FindAllStringSubmatch(tail, -1) {
+		canonical := normalizeLanguageToken(m[1])
+		if !seen[canonical] {
+			seen[canonical] = true
+			item.Languages = append(item.Languages, canonical)
+		}
+	}
+	sort.Strings(item.Languages)
+
+	item.Subbed = subbedRe.MatchString(tail)
+	item.Dubbed = dubbedRe.MatchString(tail)
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// normalizeAudioToken maps a raw audio token to its canonical value.
+func normalizeAudioToken(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case lower == "ddp" || lower == "dd+" || strings.HasPrefix(lower, "e"):
+		return "ddp"
+	case lower == "dd" || strings.HasPrefix(lower, "ac"):
+		return "dd"
+	case strings.HasPrefix(lower, "true"):
+		return "truehd"
+	case strings.HasPrefix(lower, "dts") && strings.Contains(lower, "hd"):
+		return "dts-hd"
+	case lower == "dtsx" || lower == "dts-x":
+		return "dts-x"
+	}
+	return lower // aac, flac, opus, atmos, dts
+}
+
+// normalizeEditionToken maps a raw edition token to its canonical value.
+func normalizeEditionToken(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case strings.HasPrefix(lower, "extended"):
+		return "extended"
+	case strings.HasPrefix(lower, "director"):
+		return "directors_cut"
+	case strings.HasPrefix(lower, "theatrical"):
+		return "theatrical"
+	case strings.HasPrefix(lower, "final"):
+		return "final_cut"
+	}
+	return lower // imax, unrated, uncut, remastered, criterion
+}
+
+// normalizeLanguageToken maps a raw language tag to its canonical value.
+func normalizeLanguageToken(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case strings.HasPrefix(lower, "dual"):
+		return "dual"
+	case lower == "truefrench" || lower == "vff":
+		return "french"
+	case lower == "castellano" || lower == "latino":
+		return "spanish"
+	case lower == "mandarin" || lower == "cantonese":
+		return "chinese"
+	}
+	return lower
+}
+
+// cleanShowName turns a dotted release-name prefix into a display name.
+func cleanShowName(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(s, ".", " "))
+}
+
+// validAirDate reports whether the captured year, month and day form a real
+// calendar date, so that titles such as "Show 2024 10 80" are not mistaken
+// for daily episodes.
+func validAirDate(year, month, day string) bool {
+	_, err := time.Parse("2006-01-02", year+"-"+month+"-"+day)
+	return err == nil
+}
+
 // normalizeHDRToken maps a raw regex-matched HDR token to its canonical value.
 func normalizeHDRToken(s string) string {
 	lower := strings.ToLower(s)
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/killakam3084/rss-curator/pkg/models"
 )
@@ -113,6 +115,136 @@ func TestParseTitleMetadata_SeasonOnly(t *testing.T) {
 	if item.Episode != 0 {
 		t.Errorf("Episode = %d, want 0 (season pack)", item.Episode)
 	}
+	if !item.IsSeasonPack {
+		t.Error("IsSeasonPack = false, want true")
+	}
+}
+
+func TestParseTitleMetadata_EpisodeForms(t *testing.T) {
+	cases := []struct {
+		name        string
+		title       string
+		wantShow    string
+		wantSeason  int
+		wantEpisode int
+		wantEnd     int
+		wantPack    bool
+		wantAirDate string
+	}{
+		{"single episode", "Severance.S02E03.1080p.WEB-DL.x265-GROUP", "Severance", 2, 3, 0, false, ""},
+		{"adjacent multi-episode", "Severance.S01E01E02.1080p.WEB-DL-GROUP", "Severance", 1, 1, 2, false, ""},
+		{"dashed range", "The.Bear.S03E01-E03.2160p.WEB-DL-GROUP", "The Bear", 3, 1, 3, false, ""},
+		{"dashed bare range", "The Bear S03E01-03 720p HDTV-GROUP", "The Bear", 3, 1, 3, false, ""},
+		{"resolution after dash is not a range", "The.Bear.S03E04-720p-GROUP", "The Bear", 3, 4, 0, false, ""},
+		{"season pack", "Andor.S02.2160p.DSNP.WEB-DL-GROUP", "Andor", 2, 0, 0, true, ""},
+		{"daily show", "The.Daily.Show.2026.10.15.1080p.WEB.h264-GROUP", "The Daily Show", 0, 0, 0, false, "2026-10-15"},
+		{"daily show spaces", "Jimmy Kimmel Live 2026 10 14 720p WEB h264-GROUP", "Jimmy Kimmel Live", 0, 0, 0, false, "2026-10-14"},
+		{"invalid date is not daily", "Some.Show.2026.13.45.1080p-GROUP", "Some Show 2026 13 45", 0, 0, 0, false, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			item := &models.FeedItem{Title: tc.title, ContentType: models.ContentTypeShow}
+			ParseTitleMetadata(item)
+			if item.ShowName != tc.wantShow {
+				t.Errorf("ShowName = %q, want %q", item.ShowName, tc.wantShow)
+			}
+			if item.Season != tc.wantSeason || item.Episode != tc.wantEpisode || item.EpisodeEnd != tc.wantEnd {
+				t.Errorf("S/E/End = %d/%d/%d, want %d/%d/%d",
+					item.Season, item.Episode, item.EpisodeEnd, tc.wantSeason, tc.wantEpisode, tc.wantEnd)
+			}
+			if item.IsSeasonPack != tc.wantPack {
+				t.Errorf("IsSeasonPack = %v, want %v", item.IsSeasonPack, tc.wantPack)
+			}
+			if item.AirDate != tc.wantAirDate {
+				t.Errorf("AirDate = %q, want %q", item.AirDate, tc.wantAirDate)
+			}
+		})
+	}
+}
+
+func TestParseTitleMetadata_Revision(t *testing.T) {
+	cases := []struct {
+		title string
+		want  int
+	}{
+		{"Severance.S02E03.1080p.WEB-DL.x265-GROUP", 0},
+		{"Severance.S02E03.PROPER.1080p.WEB-DL.x265-GROUP", 1},
+		{"Severance.S02E03.REPACK.1080p.WEB-DL.x265-GROUP", 1},
+		{"Severance.S02E03.RERIP.1080p.WEB-DL.x265-GROUP", 1},
+		{"Severance.S02E03.REPACK2.1080p.WEB-DL.x265-GROUP", 2},
+		{"Severance.S02E03.REAL.PROPER.1080p.WEB-DL.x265-GROUP", 2},
+		{"Severance S02E03 Repack 1080p WEB-DL x265-GROUP", 1},
+		{"Real.Time.with.Bill.Maher.S22E30.1080p.WEB-DL-GROUP", 0},
+		{"The.Proper.Way.S01E02.1080p.WEB-DL-GROUP", 0},
+		{"The.Proper.Way.S01E02.REPACK.1080p.WEB-DL-GROUP", 1},
+		{"Repack.Rats.2021.1080p.BluRay.x264-GROUP", 0},
+	}
+	for _, tc := range cases {
+		item := &models.FeedItem{Title: tc.title}
+		ParseTitleMetadata(item)
+		if item.Revision != tc.want {
+			t.Errorf("%s: Revision = %d, want %d", tc.title, item.Revision, tc.want)
+		}
+	}
+}
+
+func TestParseTitleMetadata_ReleaseAttributes(t *testing.T) {
+	cases := []struct {
+		name         string
+		title        string
+		contentType  models.ContentType
+		wantAudio    string // joined with "+"
+		wantChannels string
+		wantBits     int
+		wantEdition  string
+		wantLangs    string // joined with "+"
+		wantSubbed   bool
+		wantDubbed   bool
+	}{
+		{name: "ddp atmos 10bit", title: "Shogun.S01E01.2160p.DSNP.WEB-DL.DDP5.1.Atmos.DV.HDR.10bit.H.265-GROUP",
+			wantAudio: "atmos+ddp", wantChannels: "5.1", wantBits: 10},
+		{name: "truehd movie edition", title: "Aliens.1986.Directors.Cut.2160p.UHD.BluRay.TrueHD.7.1.Atmos.x265-GROUP", contentType: models.ContentTypeMovie,
+			wantAudio: "atmos+truehd", wantChannels: "7.1", wantEdition: "directors_cut"},
+		{name: "dts-hd ma extended", title: "The.Hobbit.2012.EXTENDED.1080p.BluRay.DTS-HD.MA.7.1.x264-GROUP", contentType: models.ContentTypeMovie,
+			wantAudio: "dts-hd", wantChannels: "7.1", wantEdition: "extended"},
+		{name: "aac multi subbed", title: "Dark.S01E01.MULTi.1080p.WEB.AAC2.0.x264.SUBBED-GROUP",
+			wantAudio: "aac", wantChannels: "2.0", wantLangs: "multi", wantSubbed: true},
+		{name: "french dubbed imax", title: "Dune.2021.IMAX.FRENCH.DUBBED.1080p.WEB.DD5.1.H264-GROUP", contentType: models.ContentTypeMovie,
+			wantAudio: "dd", wantChannels: "5.1", wantEdition: "imax", wantLangs: "french", wantDubbed: true},
+		{name: "name words are not tags", title: "The.French.Dispatch.2021.1080p.WEB-DL-GROUP", contentType: models.ContentTypeMovie},
+		{name: "eac3 and hi10p", title: "Show.S02E05.1080p.WEB.EAC3.Hi10P-GROUP",
+			wantAudio: "ddp", wantBits: 10},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ct := tc.contentType
+			if ct == "" {
+				ct = models.ContentTypeShow
+			}
+			item := &models.FeedItem{Title: tc.title, ContentType: ct}
+			ParseTitleMetadata(item)
+			if got := strings.Join(item.Audio, "+"); got != tc.wantAudio {
+				t.Errorf("Audio = %q, want %q", got, tc.wantAudio)
+			}
+			if item.AudioChannels != tc.wantChannels {
+				t.Errorf("AudioChannels = %q, want %q", item.AudioChannels, tc.wantChannels)
+			}
+			if item.BitDepth != tc.wantBits {
+				t.Errorf("BitDepth = %d, want %d", item.BitDepth, tc.wantBits)
+			}
+			if item.Edition != tc.wantEdition {
+				t.Errorf("Edition = %q, want %q", item.Edition, tc.wantEdition)
+			}
+			if got := strings.Join(item.Languages, "+"); got != tc.wantLangs {
+				t.Errorf("Languages = %q, want %q", got, tc.wantLangs)
+			}
+			if item.Subbed != tc.wantSubbed || item.Dubbed != tc.wantDubbed {
+				t.Errorf("Subbed/Dubbed = %v/%v, want %v/%v", item.Subbed, item.Dubbed, tc.wantSubbed, tc.wantDubbed)
+			}
+		})
+	}
 }
 
 func TestParseTitleMetadata_ResetsFields(t *testing.T) {
@@ -120,6 +252,9 @@ func TestParseTitleMetadata_ResetsFields(t *testing.T) {
 		Title:        "Show.S01E01.1080p-GROUP",
 		ShowName:     "stale",
 		Season:       99,
+		EpisodeEnd:   7,
+		IsSeasonPack: true,
+		AirDate:      "2020-01-01",
 		Quality:      "STALE",
 		ReleaseGroup: "OLD",
 	}
@@ -130,6 +265,9 @@ func TestParseTitleMetadata_ResetsFields(t *testing.T) {
 	if item.Season == 99 {
 		t.Error("Season was not reset before re-parsing")
 	}
+	if item.EpisodeEnd != 0 || item.IsSeasonPack || item.AirDate != "" {
+		t.Error("episode range / season pack / air date were not reset before re-parsing")
+	}
 }
 
 const testRSS = `<?xml version="1.0" encoding="UTF-8"?>
@@ -169,6 +307,21 @@ func TestParse_ReturnsTwoItems(t *testing.T) {
 	}
 }
 
+func TestParseDocument_DropsLocalLinks(t *testing.T) {
+	doc := `<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>Show.S01E01.1080p.WEB-DL-GRP</title><link>file:///etc/passwd</link></item>
+<item><title>Show.S01E02.1080p.WEB-DL-GRP</title><link>https://tracker.test/dl/2</link></item>
+<item><title>Show.S01E03.1080p.WEB-DL-GRP</title><link>magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567</link></item>
+</channel></rss>`
+	items, err := NewParser().ParseDocument([]byte(doc), models.ContentTypeShow)
+	if err != nil {
+		t.Fatalf("ParseDocument: %v", err)
+	}
+	if len(items) != 2 || items[0].Episode != 2 || items[1].Episode != 3 {
+		t.Errorf("items = %+v, want only the https and magnet links", items)
+	}
+}
+
 func TestParse_MetadataExtracted(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, testRSS)
@@ -257,6 +410,234 @@ func TestParse_MalformedXMLReturnsError(t *testing.T) {
 	}
 }
 
+const testAtom = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example Tracker</title>
+  <entry>
+    <title>Severance.S02E03.1080p.ATVP.WEB-DL.x265-FLUX</title>
+    <id>urn:example:severance-s02e03</id>
+    <published>2024-01-03T10:00:00Z</published>
+    <updated>2024-01-04T10:00:00Z</updated>
+    <link rel="alternate" href="http://example.com/t/123"/>
+    <link rel="enclosure" type="application/x-bittorrent" href="http://example.com/download/123.torrent" length="2147483648"/>
+  </entry>
+  <entry>
+    <title>Andor.S01E05.720p.WEB-DL.x264-GROUP</title>
+    <id>urn:example:andor-s01e05</id>
+    <updated>2024-01-05T08:30:00Z</updated>
+    <link href="http://example.com/download/456.torrent"/>
+    <summary>700 MB; TV/WEB-DL</summary>
+  </entry>
+</feed>`
+
+func TestParse_Atom(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		fmt.Fprint(w, testAtom)
+	}))
+	defer srv.Close()
+
+	p := NewParser()
+	items, err := p.Parse(srv.URL, models.ContentTypeShow)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	sev := items[0]
+	if sev.GUID != "urn:example:severance-s02e03" {
+		t.Errorf("GUID = %q, want urn:example:severance-s02e03", sev.GUID)
+	}
+	if sev.Link != "http://example.com/download/123.torrent" {
+		t.Errorf("Link = %q, want enclosure href", sev.Link)
+	}
+	if sev.Size != 2_147_483_648 {
+		t.Errorf("Size = %d, want 2147483648 (from enclosure)", sev.Size)
+	}
+	if want := time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC); !sev.PubDate.Equal(want) {
+		t.Errorf("PubDate = %v, want %v (published preferred over updated)", sev.PubDate, want)
+	}
+	if sev.ShowName != "Severance" || sev.Season != 2 || sev.Episode != 3 {
+		t.Errorf("ShowName=%q Season=%d Episode=%d, want Severance S02E03", sev.ShowName, sev.Season, sev.Episode)
+	}
+	if sev.Quality != "1080P" || sev.Codec != "x265" || sev.ReleaseGroup != "FLUX" {
+		t.Errorf("Quality=%q Codec=%q Group=%q, want 1080P/x265/FLUX", sev.Quality, sev.Codec, sev.ReleaseGroup)
+	}
+	if sev.ContentType != models.ContentTypeShow {
+		t.Errorf("ContentType = %q, want show", sev.ContentType)
+	}
+
+	andor := items[1]
+	if andor.Link != "http://example.com/download/456.torrent" {
+		t.Errorf("Link = %q, want plain link href", andor.Link)
+	}
+	if want := time.Date(2024, 1, 5, 8, 30, 0, 0, time.UTC); !andor.PubDate.Equal(want) {
+		t.Errorf("PubDate = %v, want %v (updated fallback)", andor.PubDate, want)
+	}
+	if andor.Size == 0 {
+		t.Error("expected size parsed from summary, got 0")
+	}
+}
+
+func TestParseConditional_NotModified(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 12:00:00 GMT")
+		fmt.Fprint(w, testRSS)
+	}))
+	defer srv.Close()
+
+	p := NewParser()
+	first, err := p.ParseConditional(srv.URL, models.ContentTypeShow, FetchOptions{})
+	if err != nil {
+		t.Fatalf("ParseConditional: %v", err)
+	}
+	if first.NotModified || len(first.Items) != 2 {
+		t.Fatalf("first fetch: NotModified=%v items=%d, want full parse of 2", first.NotModified, len(first.Items))
+	}
+	if first.Validators.ETag != `"v1"` || first.Validators.LastModified == "" {
+		t.Errorf("Validators = %+v, want ETag and Last-Modified from response", first.Validators)
+	}
+
+	second, err := p.ParseConditional(srv.URL, models.ContentTypeShow, FetchOptions{Validators: first.Validators})
+	if err != nil {
+		t.Fatalf("ParseConditional: %v", err)
+	}
+	if !second.NotModified {
+		t.Fatal("expected NotModified on second fetch with matching ETag")
+	}
+	if len(second.Items) != 0 {
+		t.Errorf("expected no items on 304, got %d", len(second.Items))
+	}
+	if second.Validators != first.Validators {
+		t.Errorf("Validators = %+v, want previous validators echoed", second.Validators)
+	}
+}
+
+func TestParseConditional_AppliesFeedAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("passkey") != "s3cret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("X-Api-Key") != "key-s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if c, err := r.Cookie("uid"); err != nil || c.Value != "42" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, testRSS)
+	}))
+	defer srv.Close()
+
+	feedURL := srv.URL + "/rss?passkey={passkey}"
+	auth := models.FeedAuth{
+		Headers: map[string]string{"X-Api-Key": "key-{passkey}"},
+		Cookies: map[string]string{"uid": "42"},
+		Passkey: "s3cret",
+	}
+
+	p := NewParser()
+	res, err := p.ParseConditional(feedURL, models.ContentTypeShow, FetchOptions{Auth: auth})
+	if err != nil {
+		t.Fatalf("ParseConditional: %v", err)
+	}
+	if len(res.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(res.Items))
+	}
+	if res.Items[0].FeedURL != feedURL {
+		t.Errorf("FeedURL = %q, want unexpanded template %q", res.Items[0].FeedURL, feedURL)
+	}
+
+	if _, err := p.ParseConditional(feedURL, models.ContentTypeShow, FetchOptions{}); err == nil {
+		t.Error("expected error when credentials are missing")
+	}
+}
+
+const testTorznab = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed">
+  <channel>
+    <item>
+      <title>Breaking.Bad.S01E01.1080p.WEB-DL.x265-NTB</title>
+      <link>http://example.com/download/1.torrent</link>
+      <guid>http://example.com/bb-guid-1</guid>
+      <torznab:attr name="seeders" value="0"/>
+      <torznab:attr name="peers" value="3"/>
+      <torznab:attr name="infohash" value="ABCDEF0123456789ABCDEF0123456789ABCDEF01"/>
+      <torznab:attr name="size" value="1546188226"/>
+      <torznab:attr name="category" value="5000"/>
+      <torznab:attr name="category" value="5040"/>
+      <torznab:attr name="downloadvolumefactor" value="0"/>
+      <torznab:attr name="imdbid" value="903747"/>
+      <torznab:attr name="tvdbid" value="81189"/>
+    </item>
+    <item>
+      <title>Andor.S01E05.720p.WEB-DL.x264-GROUP</title>
+      <link>http://example.com/download/2.torrent</link>
+      <guid>http://example.com/andor-guid-1</guid>
+    </item>
+  </channel>
+</rss>`
+
+func TestParse_TorznabAttrs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testTorznab)
+	}))
+	defer srv.Close()
+
+	p := NewParser()
+	items, err := p.Parse(srv.URL, models.ContentTypeShow)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	bb := items[0]
+	if bb.Seeders == nil || *bb.Seeders != 0 {
+		t.Errorf("Seeders = %v, want reported 0", bb.Seeders)
+	}
+	if bb.Peers == nil || *bb.Peers != 3 {
+		t.Errorf("Peers = %v, want 3", bb.Peers)
+	}
+	if bb.InfoHash != "abcdef0123456789abcdef0123456789abcdef01" {
+		t.Errorf("InfoHash = %q, want lowercased hash", bb.InfoHash)
+	}
+	if bb.Size != 1_546_188_226 {
+		t.Errorf("Size = %d, want 1546188226 (from size attr)", bb.Size)
+	}
+	if len(bb.Categories) != 2 || bb.Categories[0] != 5000 || bb.Categories[1] != 5040 {
+		t.Errorf("Categories = %v, want [5000 5040]", bb.Categories)
+	}
+	if !bb.IsFreeleech() {
+		t.Error("expected IsFreeleech() for downloadvolumefactor=0")
+	}
+	if bb.IMDBID != "tt0903747" {
+		t.Errorf("IMDBID = %q, want tt0903747", bb.IMDBID)
+	}
+	if bb.TVDBID != 81189 {
+		t.Errorf("TVDBID = %d, want 81189", bb.TVDBID)
+	}
+
+	andor := items[1]
+	if andor.Seeders != nil || andor.Peers != nil || andor.DownloadVolumeFactor != nil {
+		t.Errorf("expected unreported attrs to stay nil, got seeders=%v peers=%v dvf=%v",
+			andor.Seeders, andor.Peers, andor.DownloadVolumeFactor)
+	}
+	if andor.IsFreeleech() {
+		t.Error("IsFreeleech() should be false when downloadvolumefactor is absent")
+	}
+}
+
 func TestParseTitleMetadata_Movie(t *testing.T) {
 	cases := []struct {
 		name        string
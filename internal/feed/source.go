@@ -0,0 +1,50 @@
+package feed
+
+import (
+	"context"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// FeedSource is one origin of FeedItems for the feed check. The RSS/Atom/
+// Torznab parser is one implementation (RSSSource); others turn local input,
+// such as a watch directory, into the same FeedItems so everything goes
+// through one match → score → stage path.
+type FeedSource interface {
+	// ID identifies the source in feed health tracking and feed-check
+	// results. For RSS sources it is the (unexpanded) feed URL.
+	ID() string
+	// Fetch returns the source's current items. prev holds the validators
+	// from the last successful fetch; sources without conditional requests
+	// ignore it and never report NotModified.
+	Fetch(ctx context.Context, prev Validators) (*FetchResult, error)
+}
+
+// RSSSource is the FeedSource for one configured feed URL.
+type RSSSource struct {
+	parser *Parser
+	config models.FeedConfig
+}
+
+// NewRSSSource returns a FeedSource that fetches fc with p, applying the
+// feed's credentials and conditional-GET validators.
+func NewRSSSource(p *Parser, fc models.FeedConfig) *RSSSource {
+	return &RSSSource{parser: p, config: fc}
+}
+
+// ID returns the feed URL.
+func (s *RSSSource) ID() string { return s.config.URL }
+
+// Fetch performs a conditional fetch of the feed.
+func (s *RSSSource) Fetch(_ context.Context, prev Validators) (*FetchResult, error) {
+	return s.parser.ParseConditional(s.config.URL, s.config.ContentType, FetchOptions{
+		Validators: prev,
+		Auth:       s.config.Auth(),
+	})
+}
+
+// ParseDocument parses an RSS 2.0 or Atom document that is already in memory
+// (an exported feed, a capture) exactly as Parse would a fetched one.
+func (p *Parser) ParseDocument(body []byte, contentType models.ContentType) ([]models.FeedItem, error) {
+	return p.parseBody(body, contentType)
+}
@@ -0,0 +1,70 @@
+package matcher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// checkEpisodeRange applies a show rule's season and episode bounds,
+// returning a rejection reason or "" when item is in range. Specials (S00)
+// are only subject to SkipSpecials; date-based episodes and items without a
+// season pass every bound.
+func checkEpisodeRange(item models.FeedItem, rule *models.ShowRule) string {
+	if item.Season == 0 {
+		if rule.SkipSpecials && (item.Episode > 0 || item.IsSeasonPack) {
+			return fmt.Sprintf("special %s skipped", episodeTag(item))
+		}
+		return ""
+	}
+
+	if len(rule.OnlySeasons) > 0 && !containsInt(rule.OnlySeasons, item.Season) {
+		seasons := make([]string, len(rule.OnlySeasons))
+		for i, s := range rule.OnlySeasons {
+			seasons[i] = strconv.Itoa(s)
+		}
+		return fmt.Sprintf("season %d not in only_seasons (%s)", item.Season, strings.Join(seasons, ", "))
+	}
+
+	if rule.FromSeason > 0 && item.Season < rule.FromSeason {
+		return fmt.Sprintf("season %d before from_season %d", item.Season, rule.FromSeason)
+	}
+	if rule.FromEpisode > 0 && item.Season == rule.FromSeason {
+		start := fmt.Sprintf("S%02dE%02d", rule.FromSeason, rule.FromEpisode)
+		if item.IsSeasonPack {
+			return fmt.Sprintf("season pack %s starts before %s", episodeTag(item), start)
+		}
+		last := item.Episode
+		if item.EpisodeEnd > last {
+			last = item.EpisodeEnd
+		}
+		if last > 0 && last < rule.FromEpisode {
+			return fmt.Sprintf("%s before %s", episodeTag(item), start)
+		}
+	}
+	return ""
+}
+
+// episodeTag formats item's season/episode as "S03E05", "S03E05-E07" or
+// "S03" for a season pack.
+func episodeTag(item models.FeedItem) string {
+	tag := fmt.Sprintf("S%02d", item.Season)
+	if item.Episode > 0 {
+		tag += fmt.Sprintf("E%02d", item.Episode)
+		if item.EpisodeEnd > item.Episode {
+			tag += fmt.Sprintf("-E%02d", item.EpisodeEnd)
+		}
+	}
+	return tag
+}
+
+func containsInt(list []int, v int) bool {
+	for _, n := range list {
+		if n == v {
+			return true
+		}
+	}
+	return false
+}
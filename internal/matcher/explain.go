@@ -0,0 +1,145 @@
+package matcher
+
+import (
+	"strings"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// Effective-value sources reported by Explain.
+const (
+	sourceRule     = "rule"
+	sourceProfile  = "profile"
+	sourceDefaults = "defaults"
+)
+
+// CheckTrace is one rule check evaluated against an item. Effect is
+// "require", "exclude" or "prefer"; for preferences Passed reports whether
+// the preference was satisfied, not whether the item was rejected. Source
+// names where the effective value came from ("rule", "profile", "defaults"),
+// or is empty when nothing set it.
+type CheckTrace struct {
+	Check     string `json:"check"`
+	Effect    string `json:"effect"`
+	Value     string `json:"value"`
+	Effective string `json:"effective"`
+	Source    string `json:"source,omitempty"`
+	Passed    bool   `json:"passed"`
+}
+
+// RuleTrace explains how one watchlist rule treats an item. Result is the
+// outcome of matching the item against this rule alone; it is only set when
+// NameMatched is true.
+type RuleTrace struct {
+	Rule        string       `json:"rule"`
+	Kind        string       `json:"kind"` // "show" or "movie"
+	Profile     string       `json:"profile,omitempty"`
+	NameMatched bool         `json:"name_matched"`
+	Via         string       `json:"via,omitempty"`
+	Checks      []CheckTrace `json:"checks"`
+	Result      *MatchResult `json:"result,omitempty"`
+}
+
+// Explanation is the full trace for one item: the parsed fields, the
+// matcher's overall verdict and a trace per watchlist rule.
+type Explanation struct {
+	Item   models.FeedItem `json:"item"`
+	Result MatchResult     `json:"result"`
+	Rules  []RuleTrace     `json:"rules"`
+}
+
+// Explain evaluates item against every watchlist rule and reports each
+// rule's checks with their effective values. In legacy-rules mode only the
+// overall result is returned.
+func (m *Matcher) Explain(item models.FeedItem) Explanation {
+	s := m.snap.Load()
+	exp := Explanation{Item: item, Result: s.evaluate(item), Rules: []RuleTrace{}}
+	cfg := s.cfg
+	if cfg == nil {
+		return exp
+	}
+
+	for i := range cfg.Shows {
+		cr := &s.shows.rules[i]
+		trace := explainRule(item, cfg, cr, cfg.Shows[i].Settings())
+		if rule, via := FindShowRule(cfg.Shows[i:i+1], item); rule != nil && item.ContentType != models.ContentTypeMovie {
+			res := s.matchRule(item, cr, via)
+			trace.NameMatched, trace.Via, trace.Result = true, via, &res
+		}
+		exp.Rules = append(exp.Rules, trace)
+	}
+	for i := range cfg.Movies {
+		cr := &s.movies.rules[i]
+		trace := explainRule(item, cfg, cr, cfg.Movies[i].Settings())
+		if rule, via := FindMovieRule(cfg.Movies[i:i+1], item); rule != nil && item.ContentType == models.ContentTypeMovie {
+			res := s.matchRule(item, cr, via)
+			trace.NameMatched, trace.Via, trace.Result = true, via, &res
+		}
+		exp.Rules = append(exp.Rules, trace)
+	}
+	return exp
+}
+
+// explainRule traces the quality, group, codec and HDR checks of one
+// compiled rule. Effective values are the rule's compiled settings, exactly
+// what the matcher applies; own is the rule's own settings, used only to
+// attribute each value to the rule, its profile or the defaults.
+func explainRule(item models.FeedItem, cfg *models.ShowsConfig, cr *compiledRule, own models.DefaultRules) RuleTrace {
+	var profile models.DefaultRules
+	if p := cfg.Profile(cr.profile); p != nil {
+		profile = p.DefaultRules
+	}
+	eff := cr.eff
+
+	// source reports the first layer that set a value; the defaults are
+	// credited whenever the effective value is set but neither layer above is.
+	source := func(rule, prof, effective bool) string {
+		switch {
+		case rule:
+			return sourceRule
+		case prof:
+			return sourceProfile
+		case effective:
+			return sourceDefaults
+		}
+		return ""
+	}
+
+	return RuleTrace{
+		Rule:    cr.name,
+		Kind:    cr.kind,
+		Profile: cr.profile,
+		Checks: []CheckTrace{
+			{
+				Check: "min_quality", Effect: "require",
+				Value: item.Quality, Effective: eff.MinQuality,
+				Source: source(own.MinQuality != "", profile.MinQuality != "", eff.MinQuality != ""),
+				Passed: meetsQuality(item.Quality, eff.MinQuality),
+			},
+			{
+				Check: "exclude_groups", Effect: "exclude",
+				Value: item.ReleaseGroup, Effective: strings.Join(eff.ExcludeGroups, ", "),
+				Source: source(len(own.ExcludeGroups) > 0, len(profile.ExcludeGroups) > 0, len(eff.ExcludeGroups) > 0),
+				Passed: !isExcludedGroup(item.ReleaseGroup, eff.ExcludeGroups),
+			},
+			{
+				Check: "preferred_groups", Effect: "prefer",
+				Value: item.ReleaseGroup, Effective: strings.Join(eff.PreferredGroups, ", "),
+				Source: source(len(own.PreferredGroups) > 0, len(profile.PreferredGroups) > 0, len(eff.PreferredGroups) > 0),
+				Passed: isPreferredGroup(item.ReleaseGroup, eff.PreferredGroups),
+			},
+			{
+				Check: "preferred_codec", Effect: "prefer",
+				Value: item.Codec, Effective: eff.PreferredCodec,
+				Source: source(own.PreferredCodec != "", profile.PreferredCodec != "", eff.PreferredCodec != ""),
+				Passed: eff.PreferredCodec != "" && strings.EqualFold(item.Codec, eff.PreferredCodec),
+			},
+			{
+				Check: "preferred_hdr", Effect: "prefer",
+				Value: strings.Join(item.HDR, ", "), Effective: strings.Join(eff.PreferredHDR, ", "),
+				Source: source(len(own.PreferredHDR) > 0, len(profile.PreferredHDR) > 0, len(eff.PreferredHDR) > 0),
+				Passed: len(item.HDR) > 0 && isPreferredHDR(item.HDR, eff.PreferredHDR),
+			},
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package matcher
+
+import (
+	"strings"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// MigrateLegacyRules converts env-var match rules into watchlist form, merged
+// into base (nil starts an empty watchlist; base itself is not modified).
+// Each legacy show name becomes a ShowRule unless a rule already claims it by
+// name or alias, and the legacy quality, codec and group settings fill empty
+// Defaults fields. It returns the result and how many show rules were added.
+func MigrateLegacyRules(base *models.ShowsConfig, legacy models.MatchRule) (*models.ShowsConfig, int) {
+	cfg := &models.ShowsConfig{
+		Shows:    []models.ShowRule{},
+		Movies:   []models.MovieRule{},
+		Defaults: models.DefaultRules{PreferredGroups: []string{}, ExcludeGroups: []string{}},
+	}
+	if base != nil {
+		cfg = cloneShowsConfig(base)
+		// Keep empty lists empty rather than null so an unchanged watchlist
+		// marshals identically.
+		if base.Shows != nil && cfg.Shows == nil {
+			cfg.Shows = []models.ShowRule{}
+		}
+		if base.Movies != nil && cfg.Movies == nil {
+			cfg.Movies = []models.MovieRule{}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range cfg.Shows {
+		for _, n := range append([]string{r.Name}, r.Aliases...) {
+			seen[NormalizeTitle(n)] = true
+		}
+	}
+	added := 0
+	for _, name := range legacy.ShowNames {
+		name = strings.TrimSpace(name)
+		norm := NormalizeTitle(name)
+		if norm == "" || seen[norm] {
+			continue
+		}
+		seen[norm] = true
+		cfg.Shows = append(cfg.Shows, models.ShowRule{Name: name})
+		added++
+	}
+
+	d := &cfg.Defaults
+	if d.MinQuality == "" {
+		d.MinQuality = legacy.MinQuality
+	}
+	if d.PreferredCodec == "" {
+		d.PreferredCodec = legacy.PreferredCodec
+	}
+	if len(d.PreferredGroups) == 0 && len(legacy.PreferredGroups) > 0 {
+		d.PreferredGroups = append([]string(nil), legacy.PreferredGroups...)
+	}
+	if len(d.ExcludeGroups) == 0 && len(legacy.ExcludeGroups) > 0 {
+		d.ExcludeGroups = append([]string(nil), legacy.ExcludeGroups...)
+	}
+	return cfg, added
+}
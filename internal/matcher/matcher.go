@@ -2,48 +2,108 @@ package matcher
 
 import (
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/killakam3084/rss-curator/pkg/models"
 )
 
-// Matcher filters feed items based on rules
+// Matcher filters feed items based on rules. The configuration lives in an
+// immutable compiled snapshot that is replaced atomically, so matching is
+// lock-free and safe to run concurrently with SetShowsConfig/SetDefaults.
 type Matcher struct {
-	mu          sync.RWMutex
-	showsConfig *models.ShowsConfig
-	legacyRules *models.MatchRule
+	snap atomic.Pointer[snapshot]
+	mu   sync.Mutex // serialises writers' read-modify-write of snap
 }
 
 // NewMatcher creates a new matcher
 func NewMatcher(showsConfig *models.ShowsConfig, legacyRules *models.MatchRule) *Matcher {
-	return &Matcher{
-		showsConfig: showsConfig,
-		legacyRules: legacyRules,
-	}
+	m := &Matcher{}
+	m.snap.Store(compile(showsConfig, legacyRules))
+	return m
+}
+
+// MatchResult is the outcome of evaluating one feed item. Reason holds the
+// match reasons, or the rejection when Matched is false. Score and Formats
+// are only set for matches.
+type MatchResult struct {
+	Matched bool     `json:"matched"`
+	Reason  string   `json:"reason"`
+	Score   int      `json:"score"`
+	Formats []string `json:"formats,omitempty"`
+}
+
+// rejected builds a non-matching result.
+func rejected(reason string) MatchResult {
+	return MatchResult{Reason: reason}
 }
 
 // Match checks if a feed item matches the configured rules
 func (m *Matcher) Match(item models.FeedItem) (bool, string) {
-	// If we have shows config, use that
-	if m.showsConfig != nil {
-		return m.matchWithShowsConfig(item)
+	res := m.Evaluate(item)
+	return res.Matched, res.Reason
+}
+
+// Evaluate matches item against the configured rules and, for a match,
+// computes its release score.
+func (m *Matcher) Evaluate(item models.FeedItem) MatchResult {
+	return m.snap.Load().evaluate(item)
+}
+
+// FindRule returns a copy of the watchlist rule that claims item — a show
+// rule, or a movie rule for movies — using the snapshot's name index. Both
+// are nil in legacy-rules mode or when no rule matches.
+func (m *Matcher) FindRule(item models.FeedItem) (*models.ShowRule, *models.MovieRule) {
+	s := m.snap.Load()
+	if s.cfg == nil {
+		return nil, nil
+	}
+	if item.ContentType == models.ContentTypeMovie {
+		if r, _ := s.movies.find(item); r != nil {
+			movie := *r.movie
+			return nil, &movie
+		}
+		return nil, nil
+	}
+	if r, _ := s.shows.find(item); r != nil {
+		show := *r.show
+		return &show, nil
 	}
+	return nil, nil
+}
 
-	// Fall back to legacy rules
-	return m.matchLegacy(item)
+// RuleSettings returns the effective settings (rule → profile → defaults) of
+// the watchlist rule that claims item. ok is false in legacy-rules mode or
+// when no rule matches.
+func (m *Matcher) RuleSettings(item models.FeedItem) (rules models.DefaultRules, ok bool) {
+	s := m.snap.Load()
+	if s.cfg == nil {
+		return rules, false
+	}
+	ix := &s.shows
+	if item.ContentType == models.ContentTypeMovie {
+		ix = &s.movies
+	}
+	if r, _ := ix.find(item); r != nil {
+		return r.eff, true
+	}
+	return rules, false
 }
 
 // SetDefaults replaces the global default rules used when a show has no
 // per-show override. Safe to call concurrently with Match.
 func (m *Matcher) SetDefaults(rules models.DefaultRules) {
 	m.mu.Lock()
-	if m.showsConfig != nil {
-		m.showsConfig.Defaults = rules
-	}
-	m.mu.Unlock()
+	defer m.mu.Unlock()
+	cur := m.snap.Load()
+	if cur.cfg == nil {
+		return
+	}
+	cfg := *cur.cfg
+	cfg.Defaults = rules
+	m.snap.Store(compile(&cfg, cur.legacy))
 }
 
 // SetShowsConfig atomically replaces the entire shows configuration and
@@ -51,227 +111,262 @@ func (m *Matcher) SetDefaults(rules models.DefaultRules) {
 // rules. Safe to call concurrently with Match.
 func (m *Matcher) SetShowsConfig(cfg *models.ShowsConfig) {
 	m.mu.Lock()
-	m.showsConfig = cfg
-	m.mu.Unlock()
+	defer m.mu.Unlock()
+	m.snap.Store(compile(cfg, m.snap.Load().legacy))
 }
 
 // ShowsConfig returns a copy of the current shows configuration, or nil if
 // the matcher is running in legacy-rules mode. Safe to call concurrently.
 func (m *Matcher) ShowsConfig() *models.ShowsConfig {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	if m.showsConfig == nil {
+	cfg := m.snap.Load().cfg
+	if cfg == nil {
 		return nil
 	}
-	// Return a shallow copy so the caller cannot mutate internal state.
-	cp := *m.showsConfig
-	return &cp
+	// Copy the slices too so the caller cannot mutate the snapshot.
+	return cloneShowsConfig(cfg)
 }
 
-// matchWithShowsConfig uses the new per-show/per-movie rules
-func (m *Matcher) matchWithShowsConfig(item models.FeedItem) (bool, string) {
+// evaluate matches item against the snapshot's watchlist rules, or the
+// legacy rules when there is no watchlist.
+func (s *snapshot) evaluate(item models.FeedItem) MatchResult {
+	if s.cfg == nil {
+		return s.matchLegacy(item)
+	}
+	ix, missing := &s.shows, "show not in watch list"
 	if item.ContentType == models.ContentTypeMovie {
-		return m.matchMovie(item)
+		ix, missing = &s.movies, "movie not in watch list"
 	}
-	return m.matchShow(item)
+	rule, via := ix.find(item)
+	if rule == nil {
+		return rejected(missing)
+	}
+	return s.matchRule(item, rule, via)
 }
 
-// matchShow applies show-specific rules from ShowsConfig.
-func (m *Matcher) matchShow(item models.FeedItem) (bool, string) {
-	reasons := []string{}
-
-	// Snapshot defaults under read lock before any comparisons.
-	m.mu.RLock()
-	defaultRules := m.showsConfig.Defaults
-	m.mu.RUnlock()
-
-	// Find matching show rule
-	var showRule *models.ShowRule
-	for i := range m.showsConfig.Shows {
-		if matchShowName(item.ShowName, m.showsConfig.Shows[i].Name) {
-			showRule = &m.showsConfig.Shows[i]
-			break
-		}
+// matchRule applies one compiled show or movie rule to item.
+func (s *snapshot) matchRule(item models.FeedItem, rule *compiledRule, via string) MatchResult {
+	eff := &rule.eff
+	reasons := []string{fmt.Sprintf("matches %s: %s", rule.kind, rule.name)}
+	if via != "" {
+		reasons = append(reasons, via)
 	}
-
-	if showRule == nil {
-		return false, "show not in watch list"
+	if rule.profile != "" {
+		reasons = append(reasons, fmt.Sprintf("profile: %s", rule.profile))
 	}
 
-	reasons = append(reasons, fmt.Sprintf("matches show: %s", showRule.Name))
-
-	// Get effective rules (show-specific or defaults)
-	minQuality := showRule.MinQuality
-	if minQuality == "" {
-		minQuality = defaultRules.MinQuality
-	}
-	preferredCodec := showRule.PreferredCodec
-	if preferredCodec == "" {
-		preferredCodec = defaultRules.PreferredCodec
+	keyword, rejection := evaluateKeywords(item.Title, eff.MustContain, eff.MustNotContain)
+	if rejection != "" {
+		return rejected(rejection)
 	}
-	preferredGroups := showRule.PreferredGroups
-	if len(preferredGroups) == 0 {
-		preferredGroups = defaultRules.PreferredGroups
+	if keyword != "" {
+		reasons = append(reasons, keyword)
 	}
-	excludeGroups := showRule.ExcludeGroups
-	if len(excludeGroups) == 0 {
-		excludeGroups = defaultRules.ExcludeGroups
-	}
-	preferredHDR := showRule.PreferredHDR
-	if len(preferredHDR) == 0 {
-		preferredHDR = defaultRules.PreferredHDR
+	if rule.show != nil {
+		if rejection := checkEpisodeRange(item, rule.show); rejection != "" {
+			return rejected(rejection)
+		}
 	}
 
-	if !meetsQuality(item.Quality, minQuality) {
-		return false, fmt.Sprintf("quality %s below minimum %s", item.Quality, minQuality)
+	if !meetsQuality(item.Quality, eff.MinQuality) {
+		return rejected(fmt.Sprintf("quality %s below minimum %s", item.Quality, eff.MinQuality))
+	}
+	if rejection := CheckConstraints(item, eff.Constraints()); rejection != "" {
+		return rejected(rejection)
 	}
 	reasons = append(reasons, fmt.Sprintf("quality: %s", item.Quality))
+	if item.Revision > 0 {
+		reasons = append(reasons, fmt.Sprintf("revision: %d", item.Revision))
+	}
 
-	if preferredCodec != "" && strings.EqualFold(item.Codec, preferredCodec) {
+	if eff.PreferredCodec != "" && strings.EqualFold(item.Codec, eff.PreferredCodec) {
 		reasons = append(reasons, fmt.Sprintf("preferred codec: %s", item.Codec))
 	}
-	if len(item.HDR) > 0 && isPreferredHDR(item.HDR, preferredHDR) {
-		reasons = append(reasons, fmt.Sprintf("hdr: %s", strings.Join(matchedHDR(item.HDR, preferredHDR), "+")))
+	if len(item.HDR) > 0 && isPreferredHDR(item.HDR, eff.PreferredHDR) {
+		reasons = append(reasons, fmt.Sprintf("hdr: %s", strings.Join(matchedHDR(item.HDR, eff.PreferredHDR), "+")))
 	}
-	if isExcludedGroup(item.ReleaseGroup, excludeGroups) {
-		return false, fmt.Sprintf("release group %s is excluded", item.ReleaseGroup)
+	if isExcludedGroup(item.ReleaseGroup, eff.ExcludeGroups) {
+		return rejected(fmt.Sprintf("release group %s is excluded", item.ReleaseGroup))
 	}
-	if isPreferredGroup(item.ReleaseGroup, preferredGroups) {
+	if isPreferredGroup(item.ReleaseGroup, eff.PreferredGroups) {
 		reasons = append(reasons, fmt.Sprintf("preferred group: %s", item.ReleaseGroup))
 	}
 
-	return true, strings.Join(reasons, ", ")
-}
-
-// matchMovie applies movie-specific rules from ShowsConfig.Movies.
-func (m *Matcher) matchMovie(item models.FeedItem) (bool, string) {
-	reasons := []string{}
-
-	m.mu.RLock()
-	defaultRules := m.showsConfig.Defaults
-	m.mu.RUnlock()
-
-	var movieRule *models.MovieRule
-	for i := range m.showsConfig.Movies {
-		baseName, ruleYear := movieRuleNameParts(m.showsConfig.Movies[i].Name)
-		if matchShowName(item.ShowName, baseName) {
-			// If the rule name includes a year, require it to match so that
-			// "The Thing 1982" and "The Thing 2011" stay distinct.
-			if ruleYear != 0 && item.ReleaseYear != 0 && ruleYear != item.ReleaseYear {
-				continue
-			}
-			movieRule = &m.showsConfig.Movies[i]
-			break
-		}
-	}
-
-	if movieRule == nil {
-		return false, "movie not in watch list"
-	}
-
-	reasons = append(reasons, fmt.Sprintf("matches movie: %s", movieRule.Name))
-
-	minQuality := movieRule.MinQuality
-	if minQuality == "" {
-		minQuality = defaultRules.MinQuality
-	}
-	preferredCodec := movieRule.PreferredCodec
-	if preferredCodec == "" {
-		preferredCodec = defaultRules.PreferredCodec
-	}
-	preferredGroups := movieRule.PreferredGroups
-	if len(preferredGroups) == 0 {
-		preferredGroups = defaultRules.PreferredGroups
-	}
-	excludeGroups := movieRule.ExcludeGroups
-	if len(excludeGroups) == 0 {
-		excludeGroups = defaultRules.ExcludeGroups
-	}
-	preferredHDR := movieRule.PreferredHDR
-	if len(preferredHDR) == 0 {
-		preferredHDR = defaultRules.PreferredHDR
-	}
-
-	if !meetsQuality(item.Quality, minQuality) {
-		return false, fmt.Sprintf("quality %s below minimum %s", item.Quality, minQuality)
+	attrs := EvaluateAttributes(item, eff.AttributeRules)
+	if attrs.Rejection != "" {
+		return rejected(attrs.Rejection)
 	}
-	reasons = append(reasons, fmt.Sprintf("quality: %s", item.Quality))
+	reasons = append(reasons, attrs.Preferred...)
 
-	if preferredCodec != "" && strings.EqualFold(item.Codec, preferredCodec) {
-		reasons = append(reasons, fmt.Sprintf("preferred codec: %s", item.Codec))
+	score, formats := ScoreRelease(item, ReleasePreferences{
+		Codec:         eff.PreferredCodec,
+		Groups:        eff.PreferredGroups,
+		HDR:           eff.PreferredHDR,
+		AttributeHits: len(attrs.Preferred),
+	}, s.formats)
+	if len(formats) > 0 {
+		reasons = append(reasons, fmt.Sprintf("formats: %s", strings.Join(formats, "+")))
 	}
-	if len(item.HDR) > 0 && isPreferredHDR(item.HDR, preferredHDR) {
-		reasons = append(reasons, fmt.Sprintf("hdr: %s", strings.Join(matchedHDR(item.HDR, preferredHDR), "+")))
-	}
-	if isExcludedGroup(item.ReleaseGroup, excludeGroups) {
-		return false, fmt.Sprintf("release group %s is excluded", item.ReleaseGroup)
-	}
-	if isPreferredGroup(item.ReleaseGroup, preferredGroups) {
-		reasons = append(reasons, fmt.Sprintf("preferred group: %s", item.ReleaseGroup))
+	if eff.MinScore != nil && score < *eff.MinScore {
+		return rejected(fmt.Sprintf("release score %d below minimum %d", score, *eff.MinScore))
 	}
 
-	return true, strings.Join(reasons, ", ")
+	return MatchResult{Matched: true, Reason: strings.Join(reasons, ", "), Score: score, Formats: formats}
 }
 
 // matchLegacy uses the old env-var based rules
-func (m *Matcher) matchLegacy(item models.FeedItem) (bool, string) {
+func (s *snapshot) matchLegacy(item models.FeedItem) MatchResult {
 	reasons := []string{}
+	legacy := s.legacy
+	if legacy == nil {
+		return rejected("no match rules configured")
+	}
 
-	if !matchesShowName(item.ShowName, m.legacyRules.ShowNames) {
-		return false, "show name not in watch list"
+	if !matchesShowName(item.ShowName, legacy.ShowNames) {
+		return rejected("show name not in watch list")
 	}
 	reasons = append(reasons, fmt.Sprintf("matches show: %s", item.ShowName))
 
-	if !meetsQuality(item.Quality, m.legacyRules.MinQuality) {
-		return false, fmt.Sprintf("quality %s below minimum %s", item.Quality, m.legacyRules.MinQuality)
+	if !meetsQuality(item.Quality, legacy.MinQuality) {
+		return rejected(fmt.Sprintf("quality %s below minimum %s", item.Quality, legacy.MinQuality))
 	}
 	reasons = append(reasons, fmt.Sprintf("quality: %s", item.Quality))
+	if item.Revision > 0 {
+		reasons = append(reasons, fmt.Sprintf("revision: %d", item.Revision))
+	}
 
-	if m.legacyRules.PreferredCodec != "" && strings.EqualFold(item.Codec, m.legacyRules.PreferredCodec) {
+	if legacy.PreferredCodec != "" && strings.EqualFold(item.Codec, legacy.PreferredCodec) {
 		reasons = append(reasons, fmt.Sprintf("preferred codec: %s", item.Codec))
 	}
 
-	if isExcludedGroup(item.ReleaseGroup, m.legacyRules.ExcludeGroups) {
-		return false, fmt.Sprintf("release group %s is excluded", item.ReleaseGroup)
+	if isExcludedGroup(item.ReleaseGroup, legacy.ExcludeGroups) {
+		return rejected(fmt.Sprintf("release group %s is excluded", item.ReleaseGroup))
 	}
 
-	if isPreferredGroup(item.ReleaseGroup, m.legacyRules.PreferredGroups) {
+	if isPreferredGroup(item.ReleaseGroup, legacy.PreferredGroups) {
 		reasons = append(reasons, fmt.Sprintf("preferred group: %s", item.ReleaseGroup))
 	}
 
-	return true, strings.Join(reasons, ", ")
+	score, _ := ScoreRelease(item, ReleasePreferences{
+		Codec:  legacy.PreferredCodec,
+		Groups: legacy.PreferredGroups,
+	}, nil)
+
+	return MatchResult{Matched: true, Reason: strings.Join(reasons, ", "), Score: score}
 }
 
-// movieRuleNameParts splits a movie rule name into a base title and an
+// MovieRuleNameParts splits a movie rule name into a base title and an
 // optional release year. "Joker 2019" → ("Joker", 2019). This lets users
 // disambiguate same-named movies by year in shows.json while still matching
 // against the parsed ShowName (which never includes the year).
-func movieRuleNameParts(name string) (baseName string, year int) {
-	re := regexp.MustCompile(`^(.*?)\s+((19|20)\d{2})$`)
-	if m := re.FindStringSubmatch(strings.TrimSpace(name)); m != nil {
+func MovieRuleNameParts(name string) (baseName string, year int) {
+	if m := movieRuleYearRe.FindStringSubmatch(strings.TrimSpace(name)); m != nil {
 		yr, _ := strconv.Atoi(m[2])
 		return strings.TrimSpace(m[1]), yr
 	}
 	return strings.TrimSpace(name), 0
 }
 
-// matchShowName returns true when ruleName appears in itemShowName as a
-// complete word (word-boundary match, case-insensitive). This prevents
-// substrings like "NOVA" from matching within "Renovation".
+// matchShowName reports whether the parsed show name and a rule name refer to
+// the same title: sameShowName, or the release's name minus a trailing
+// country code ("The Office US") against a rule that names no country.
 func matchShowName(itemShowName, ruleName string) bool {
-	ruleName = strings.TrimSpace(ruleName)
-	if ruleName == "" {
+	if sameShowName(itemShowName, ruleName) {
+		return true
+	}
+	base, ok := stripTrailingCountry(NormalizeTitle(itemShowName))
+	return ok && sameShowName(base, ruleName)
+}
+
+// sameShowName compares names without the country-code fallback. Both sides
+// go through NormalizeTitle, so punctuation, "&", diacritics and acronym dots
+// do not matter. Otherwise the comparison is exact: substring/prefix matching
+// caused false positives like "The Great" matching "The Great Celebrity Bake
+// Off". A trailing year is optional on either side — "Shogun 2024" matches a
+// "Shōgun" rule, and a "Shōgun (2024)" rule matches a release without the
+// year.
+func sameShowName(itemShowName, ruleName string) bool {
+	item, rule := NormalizeTitle(itemShowName), NormalizeTitle(ruleName)
+	if item == "" || rule == "" {
 		return false
 	}
-	// Exact case-insensitive comparison: the parsed show name must equal the
-	// rule name. Substring/prefix matching caused false positives like
-	// "Saturday Night Live UK" matching a "Saturday Night Live" rule, or
-	// "The Great Celebrity Bake Off" matching a "The Great" rule.
-	return strings.EqualFold(strings.TrimSpace(itemShowName), ruleName)
+	if item == rule {
+		return true
+	}
+	if base, ok := stripTrailingYear(item); ok && base == rule {
+		return true
+	}
+	if base, ok := stripTrailingYear(rule); ok && base == item {
+		return true
+	}
+	return false
+}
+
+// FindShowRule returns the first rule that claims item: its name or one of
+// its aliases matches the parsed show name, or its title regex/glob matches
+// the raw title. The second value is the reason fragment naming what fired
+// ("alias: X", "regex: X", "glob: X"), or "" when the name itself matched.
+// It returns nil when no rule matches.
+//
+// A release whose name ends in a country code ("The Office US") falls back to
+// a rule without one ("The Office") only when no rule matches the full name,
+// so a rule naming the country ("The Office UK") always wins for its region.
+func FindShowRule(shows []models.ShowRule, item models.FeedItem) (*models.ShowRule, string) {
+	if rule, via := findShowRule(shows, item); rule != nil {
+		return rule, via
+	}
+	if base, ok := stripTrailingCountry(NormalizeTitle(item.ShowName)); ok {
+		item.ShowName = base
+		return findShowRule(shows, item)
+	}
+	return nil, ""
+}
+
+// findShowRule is FindShowRule without the country-code fallback.
+func findShowRule(shows []models.ShowRule, item models.FeedItem) (*models.ShowRule, string) {
+	for i := range shows {
+		if sameShowName(item.ShowName, shows[i].Name) {
+			return &shows[i], ""
+		}
+		for _, a := range shows[i].Aliases {
+			if sameShowName(item.ShowName, a) {
+				return &shows[i], "alias: " + a
+			}
+		}
+		if via, ok := matchRulePatterns(item.Title, shows[i].TitleRegex, shows[i].TitleGlob); ok {
+			return &shows[i], via
+		}
+	}
+	return nil, ""
+}
+
+// FindMovieRule is FindShowRule for movies. A year in the rule name or alias
+// ("The Thing 1982") must agree with the release year when both are known,
+// so same-named movies stay distinct.
+func FindMovieRule(movies []models.MovieRule, item models.FeedItem) (*models.MovieRule, string) {
+	for i := range movies {
+		names := append([]string{movies[i].Name}, movies[i].Aliases...)
+		for j, name := range names {
+			baseName, ruleYear := MovieRuleNameParts(name)
+			if !sameShowName(item.ShowName, baseName) {
+				continue
+			}
+			if ruleYear != 0 && item.ReleaseYear != 0 && ruleYear != item.ReleaseYear {
+				continue
+			}
+			if j == 0 {
+				return &movies[i], ""
+			}
+			return &movies[i], "alias: " + name
+		}
+		if via, ok := matchRulePatterns(item.Title, movies[i].TitleRegex, movies[i].TitleGlob); ok {
+			return &movies[i], via
+		}
+	}
+	return nil, ""
 }
 
 // matchesShowName checks a show name against a list of rule names using
-// word-boundary matching.
+// matchShowName.
 func matchesShowName(showName string, showNames []string) bool {
 	if len(showNames) == 0 {
 		return false
@@ -284,19 +379,20 @@ func matchesShowName(showName string, showNames []string) bool {
 	return false
 }
 
+// qualityRank orders the resolutions the parser recognises.
+var qualityRank = map[string]int{
+	"480P":  0,
+	"720P":  1,
+	"1080P": 2,
+	"2160P": 3,
+	"4K":    3,
+}
+
 func meetsQuality(quality, minQuality string) bool {
 	if minQuality == "" {
 		return true
 	}
 
-	qualityRank := map[string]int{
-		"480P":  0,
-		"720P":  1,
-		"1080P": 2,
-		"2160P": 3,
-		"4K":    3,
-	}
-
 	minRank, ok2 := qualityRank[strings.ToUpper(minQuality)]
 	if !ok2 {
 		// Unknown min_quality in config — don't filter.
@@ -365,16 +461,193 @@ func matchedHDR(itemHDR []string, preferred []string) []string {
 	return out
 }
 
+// AttributeResult is the outcome of checking a FeedItem's audio, bit depth,
+// edition and language against a rule's AttributeRules.
+type AttributeResult struct {
+	Preferred []string // match-reason fragments for satisfied preferences, e.g. "audio: atmos"
+	Rejection string   // non-empty when a required value is missing
+}
+
+// EvaluateAttributes checks item against rules. Required values are checked
+// first; a missing one produces a Rejection and no preferences are reported.
+func EvaluateAttributes(item models.FeedItem, rules models.AttributeRules) AttributeResult {
+	var res AttributeResult
+
+	if len(rules.RequiredAudio) > 0 && len(intersectFold(item.Audio, rules.RequiredAudio)) == 0 {
+		res.Rejection = fmt.Sprintf("audio %s lacks required %s", orUnknown(strings.Join(item.Audio, "+")), strings.Join(rules.RequiredAudio, "/"))
+		return res
+	}
+	if rules.RequiredBitDepth > 0 && item.BitDepth < rules.RequiredBitDepth {
+		res.Rejection = fmt.Sprintf("bit depth %s below required %d-bit", orUnknown(bitDepthLabel(item.BitDepth)), rules.RequiredBitDepth)
+		return res
+	}
+	if len(rules.RequiredEditions) > 0 && len(intersectFold([]string{item.Edition}, rules.RequiredEditions)) == 0 {
+		res.Rejection = fmt.Sprintf("edition %s lacks required %s", orUnknown(item.Edition), strings.Join(rules.RequiredEditions, "/"))
+		return res
+	}
+	if len(rules.RequiredLanguages) > 0 && len(matchedLanguages(item, rules.RequiredLanguages)) == 0 {
+		res.Rejection = fmt.Sprintf("language lacks required %s", strings.Join(rules.RequiredLanguages, "/"))
+		return res
+	}
+
+	if hit := intersectFold(item.Audio, rules.PreferredAudio); len(hit) > 0 {
+		res.Preferred = append(res.Preferred, fmt.Sprintf("audio: %s", strings.Join(hit, "+")))
+	}
+	if rules.PreferredBitDepth > 0 && item.BitDepth == rules.PreferredBitDepth {
+		res.Preferred = append(res.Preferred, fmt.Sprintf("bit depth: %s", bitDepthLabel(item.BitDepth)))
+	}
+	if item.Edition != "" && len(intersectFold([]string{item.Edition}, rules.PreferredEditions)) > 0 {
+		res.Preferred = append(res.Preferred, fmt.Sprintf("edition: %s", item.Edition))
+	}
+	if hit := matchedLanguages(item, rules.PreferredLanguages); len(hit) > 0 {
+		res.Preferred = append(res.Preferred, fmt.Sprintf("language: %s", strings.Join(hit, "+")))
+	}
+	return res
+}
+
+// matchedLanguages returns the values in wanted that item satisfies.
+// "original" is satisfied by a release that is not dubbed and is either
+// untagged or carries a multi/dual audio tag; "subbed" by a subbed release;
+// anything else by the matching language tag.
+func matchedLanguages(item models.FeedItem, wanted []string) []string {
+	var out []string
+	for _, w := range wanted {
+		var ok bool
+		switch strings.ToLower(w) {
+		case "original":
+			ok = !item.Dubbed && (len(item.Languages) == 0 ||
+				len(intersectFold(item.Languages, []string{"multi", "dual"})) > 0)
+		case "subbed":
+			ok = item.Subbed
+		default:
+			ok = len(intersectFold(item.Languages, []string{w})) > 0
+		}
+		if ok {
+			out = append(out, strings.ToLower(w))
+		}
+	}
+	return out
+}
+
+// intersectFold returns the values of have that appear in want
+// (case-insensitive), preserving their order in have.
+func intersectFold(have, want []string) []string {
+	var out []string
+	for _, h := range have {
+		for _, w := range want {
+			if h != "" && strings.EqualFold(h, w) {
+				out = append(out, h)
+				break
+			}
+		}
+	}
+	return out
+}
+
+func bitDepthLabel(bits int) string {
+	if bits == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d-bit", bits)
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+// exceedsQuality reports whether quality ranks above maxQuality. Unknown
+// values on either side never exceed.
+func exceedsQuality(quality, maxQuality string) bool {
+	maxRank, ok := qualityRank[strings.ToUpper(maxQuality)]
+	if !ok {
+		return false
+	}
+	itemRank, ok := qualityRank[strings.ToUpper(quality)]
+	return ok && itemRank > maxRank
+}
+
+// bytesPerGB converts rule sizes (GB) to bytes.
+const bytesPerGB = 1 << 30
+
+// CheckConstraints checks item against a rule's quality ceiling, size bounds
+// and source lists, returning a rejection reason or "" when item passes.
+// Sizes are compared per episode for multi-episode releases; season packs
+// and items without a reported size skip the size check. An item with no
+// recognisable source fails an allowed-sources list.
+func CheckConstraints(item models.FeedItem, c models.ReleaseConstraints) string {
+	if c.MaxQuality != "" && exceedsQuality(item.Quality, c.MaxQuality) {
+		return fmt.Sprintf("quality %s above maximum %s", item.Quality, c.MaxQuality)
+	}
+
+	if item.Size > 0 && !item.IsSeasonPack && (c.MinSize > 0 || c.MaxSize > 0) {
+		episodes := 1
+		if item.EpisodeEnd > item.Episode && item.Episode > 0 {
+			episodes = item.EpisodeEnd - item.Episode + 1
+		}
+		gb := float64(item.Size) / bytesPerGB / float64(episodes)
+		unit := "GB"
+		if episodes > 1 {
+			unit = "GB per episode"
+		}
+		if c.MinSize > 0 && gb < c.MinSize {
+			return fmt.Sprintf("size %.2f %s below minimum %g GB", gb, unit, c.MinSize)
+		}
+		if c.MaxSize > 0 && gb > c.MaxSize {
+			return fmt.Sprintf("size %.2f %s above maximum %g GB", gb, unit, c.MaxSize)
+		}
+	}
+
+	words := titleWords(item.Title)
+	for _, src := range c.ExcludedSources {
+		if hasSource(item, words, src) {
+			return fmt.Sprintf("source %s is excluded", strings.TrimSpace(src))
+		}
+	}
+	if len(c.AllowedSources) > 0 {
+		allowed := false
+		for _, src := range c.AllowedSources {
+			if hasSource(item, words, src) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Sprintf("source %s not in allowed sources (%s)", orUnknown(item.Source), strings.Join(c.AllowedSources, ", "))
+		}
+	}
+	return ""
+}
+
+// hasSource reports whether item was released from src: either the parsed
+// Source matches ignoring case and punctuation ("WEBDL" = "WEB-DL"), or the
+// title carries src as a whole-word tag — titles often list a service tag
+// (AMZN) before the rip type, and the parser keeps only the first.
+func hasSource(item models.FeedItem, words []string, src string) bool {
+	want := strings.Join(titleWords(src), "")
+	if want == "" {
+		return false
+	}
+	if strings.Join(titleWords(item.Source), "") == want {
+		return true
+	}
+	return containsKeyword(words, src)
+}
+
 // MatchAll filters a list of feed items and returns matches
 func (m *Matcher) MatchAll(items []models.FeedItem) []models.StagedTorrent {
 	staged := []models.StagedTorrent{}
 
 	for _, item := range items {
-		if matches, reason := m.Match(item); matches {
+		if res := m.Evaluate(item); res.Matched {
 			staged = append(staged, models.StagedTorrent{
-				FeedItem:    item,
-				MatchReason: reason,
-				Status:      "pending",
+				FeedItem:     item,
+				MatchReason:  res.Reason,
+				ReleaseScore: res.Score,
+				Status:       "pending",
+				Torrent:      item.Torrent,
 			})
 		}
 	}
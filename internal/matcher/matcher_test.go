@@ -1,7 +1,10 @@
 package matcher
 
 import (
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/killakam3084/rss-curator/pkg/models"
 )
@@ -39,9 +42,14 @@ func TestMatchShowNameWordBoundary(t *testing.T) {
 }
 
 func TestMatchShowNameDoesNotMatchPrefixOfLongerShowName(t *testing.T) {
-	// "Saturday Night Live" rule must NOT fire for "Saturday Night Live UK"
-	if matchShowName("Saturday Night Live UK", "Saturday Night Live") {
-		t.Fatal("expected 'Saturday Night Live' not to match 'Saturday Night Live UK'")
+	// A trailing country code falls back to a bare rule only when no rule
+	// names that country: "Saturday Night Live UK" goes to its own rule.
+	shows := []models.ShowRule{{Name: "Saturday Night Live"}, {Name: "Saturday Night Live UK"}}
+	if r, _ := FindShowRule(shows, models.FeedItem{ShowName: "Saturday Night Live UK"}); r == nil || r.Name != "Saturday Night Live UK" {
+		t.Fatalf("expected the UK rule to claim 'Saturday Night Live UK', got %+v", r)
+	}
+	if r, _ := FindShowRule(shows[:1], models.FeedItem{ShowName: "Saturday Night Live UK"}); r == nil || r.Name != "Saturday Night Live" {
+		t.Fatalf("expected the bare rule to claim 'Saturday Night Live UK' without a UK rule, got %+v", r)
 	}
 	// "The Great" rule must NOT fire for "The Great Celebrity Bake Off for SU2C"
 	if matchShowName("The Great Celebrity Bake Off for SU2C", "The Great") {
@@ -56,6 +64,53 @@ func TestMatchShowNameDoesNotMatchPrefixOfLongerShowName(t *testing.T) {
 	}
 }
 
+func TestNormalizeTitle(t *testing.T) {
+	cases := map[string]string{
+		"Marvel's Agents of S.H.I.E.L.D.": "marvels agents of shield",
+		"Marvels Agents of SHIELD":        "marvels agents of shield",
+		"Law & Order: SVU":                "law and order svu",
+		"Law and Order SVU":               "law and order svu",
+		"The Office (US)":                 "the office us",
+		"Shōgun (2024)":                   "shogun 2024",
+		"Pokémon: Horizons":               "pokemon horizons",
+		"  Mr.  Robot ":                   "mr robot",
+		"":                                "",
+	}
+	for in, want := range cases {
+		if got := NormalizeTitle(in); got != want {
+			t.Errorf("NormalizeTitle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMatchShowNameNormalization(t *testing.T) {
+	match := []struct{ item, rule string }{
+		{"Marvels Agents of SHIELD", "Marvel's Agents of S.H.I.E.L.D."},
+		{"The Office US", "The Office (US)"},
+		{"Shogun 2024", "Shōgun"},
+		{"Shogun", "Shōgun (2024)"},
+		{"Law and Order SVU", "Law & Order: SVU"},
+		{"breaking bad", "Breaking Bad"},
+		{"The Office US", "The Office"},
+	}
+	for _, tc := range match {
+		if !matchShowName(tc.item, tc.rule) {
+			t.Errorf("expected %q to match rule %q", tc.item, tc.rule)
+		}
+	}
+	noMatch := []struct{ item, rule string }{
+		{"The Office", "The Office US"},
+		{"The Office UK", "The Office US"},
+		{"Shogun 1980", "Shōgun (2024)"},
+		{"Law and Order", "Law & Order: SVU"},
+	}
+	for _, tc := range noMatch {
+		if matchShowName(tc.item, tc.rule) {
+			t.Errorf("expected %q not to match rule %q", tc.item, tc.rule)
+		}
+	}
+}
+
 func TestLegacyMatchNoConfiguredShowsRejects(t *testing.T) {
 	m := NewMatcher(nil, &models.MatchRule{
 		ShowNames:  nil,
@@ -324,7 +379,7 @@ func TestMatchAllEmpty(t *testing.T) {
 	}
 }
 
-// ── movieRuleNameParts ────────────────────────────────────────────────────────
+// ── MovieRuleNameParts ────────────────────────────────────────────────────────
 
 func TestMovieRuleNameParts(t *testing.T) {
 	cases := []struct {
@@ -341,9 +396,9 @@ func TestMovieRuleNameParts(t *testing.T) {
 		{"  Dune Part Two 2024  ", "Dune Part Two", 2024}, // trimmed
 	}
 	for _, tc := range cases {
-		gotName, gotYear := movieRuleNameParts(tc.input)
+		gotName, gotYear := MovieRuleNameParts(tc.input)
 		if gotName != tc.wantName || gotYear != tc.wantYear {
-			t.Errorf("movieRuleNameParts(%q) = (%q, %d), want (%q, %d)",
+			t.Errorf("MovieRuleNameParts(%q) = (%q, %d), want (%q, %d)",
 				tc.input, gotName, gotYear, tc.wantName, tc.wantYear)
 		}
 	}
@@ -546,3 +601,676 @@ func TestHDRSoftSignalMovieMatch(t *testing.T) {
 		t.Errorf("expected reason to contain 'hdr: dv', got: %q", reason)
 	}
 }
+
+func TestAttributePreferencesAppended(t *testing.T) {
+	cfg := &models.ShowsConfig{
+		Shows: []models.ShowRule{{
+			Name: "Shogun",
+			AttributeRules: models.AttributeRules{
+				PreferredAudio:     []string{"atmos"},
+				PreferredLanguages: []string{"original"},
+			},
+		}},
+		Defaults: models.DefaultRules{MinQuality: "1080P", AttributeRules: models.AttributeRules{PreferredBitDepth: 10}},
+	}
+	m := NewMatcher(cfg, nil)
+	ok, reason := m.Match(models.FeedItem{
+		ContentType: models.ContentTypeShow,
+		ShowName:    "Shogun",
+		Quality:     "2160P",
+		Audio:       []string{"atmos", "ddp"},
+		BitDepth:    10,
+	})
+	if !ok {
+		t.Fatalf("expected match, got false: %s", reason)
+	}
+	for _, want := range []string{"audio: atmos", "bit depth: 10-bit", "language: original"} {
+		if !contains(reason, want) {
+			t.Errorf("expected reason to contain %q, got: %q", want, reason)
+		}
+	}
+}
+
+func TestAttributeRequirementsReject(t *testing.T) {
+	cases := []struct {
+		name string
+		rule models.ShowRule
+		item models.FeedItem
+		want string
+	}{
+		{
+			name: "required audio missing",
+			rule: models.ShowRule{Name: "Shogun", AttributeRules: models.AttributeRules{RequiredAudio: []string{"atmos", "truehd"}}},
+			item: models.FeedItem{Audio: []string{"ddp"}},
+			want: "audio ddp lacks required atmos/truehd",
+		},
+		{
+			name: "required bit depth missing",
+			rule: models.ShowRule{Name: "Shogun", AttributeRules: models.AttributeRules{RequiredBitDepth: 10}},
+			item: models.FeedItem{},
+			want: "bit depth unknown below required 10-bit",
+		},
+		{
+			name: "dubbed release is not original",
+			rule: models.ShowRule{Name: "Shogun", AttributeRules: models.AttributeRules{RequiredLanguages: []string{"original"}}},
+			item: models.FeedItem{Languages: []string{"german"}, Dubbed: true},
+			want: "language lacks required original",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewMatcher(&models.ShowsConfig{Shows: []models.ShowRule{tc.rule}}, nil)
+			tc.item.ContentType = models.ContentTypeShow
+			tc.item.ShowName = "Shogun"
+			tc.item.Quality = "1080P"
+			ok, reason := m.Match(tc.item)
+			if ok {
+				t.Fatalf("expected rejection, got match: %s", reason)
+			}
+			if reason != tc.want {
+				t.Errorf("reason = %q, want %q", reason, tc.want)
+			}
+		})
+	}
+}
+
+// ── Aliases ───────────────────────────────────────────────────────────────────
+
+func TestShowAliasMatches(t *testing.T) {
+	cfg := makeShowsCfg("Law & Order: Special Victims Unit", "1080P")
+	cfg.Shows[0].Aliases = []string{"Law & Order: SVU"}
+	m := NewMatcher(cfg, nil)
+
+	ok, reason := m.Match(models.FeedItem{ShowName: "Law and Order SVU", Quality: "1080P"})
+	if !ok {
+		t.Fatalf("expected alias match, got: %q", reason)
+	}
+	if !contains(reason, "matches show: Law & Order: Special Victims Unit") || !contains(reason, "alias: Law & Order: SVU") {
+		t.Errorf("reason should name the rule and the alias: %q", reason)
+	}
+
+	ok, reason = m.Match(models.FeedItem{ShowName: "Law and Order Special Victims Unit", Quality: "1080P"})
+	if !ok || contains(reason, "alias:") {
+		t.Errorf("name match should not report an alias: ok=%v reason=%q", ok, reason)
+	}
+}
+
+func TestMovieAliasRespectsYear(t *testing.T) {
+	cfg := makeMovieCfg("Crouching Tiger, Hidden Dragon 2000", "1080P")
+	cfg.Movies[0].Aliases = []string{"Wo Hu Cang Long 2000"}
+	m := NewMatcher(cfg, nil)
+
+	item := models.FeedItem{ContentType: models.ContentTypeMovie, ShowName: "Wo Hu Cang Long", ReleaseYear: 2000, Quality: "1080P"}
+	if ok, reason := m.Match(item); !ok || !contains(reason, "alias: Wo Hu Cang Long 2000") {
+		t.Errorf("expected alias match, got ok=%v reason=%q", ok, reason)
+	}
+	item.ReleaseYear = 2016
+	if ok, _ := m.Match(item); ok {
+		t.Error("alias with a year must not match a different release year")
+	}
+}
+
+func TestTitlePatternMatches(t *testing.T) {
+	cfg := makeShowsCfg("Anime Simulcasts", "720P")
+	cfg.Shows[0].TitleRegex = `^\[SubsPlease\] `
+	cfg.Shows = append(cfg.Shows, models.ShowRule{Name: "Daily Show Clips", TitleGlob: "the.daily.show.*.web*"})
+	m := NewMatcher(cfg, nil)
+
+	ok, reason := m.Match(models.FeedItem{Title: "[SubsPlease] Frieren - 12 (1080p)", ShowName: "Frieren", Quality: "1080P"})
+	if !ok || !contains(reason, "matches show: Anime Simulcasts") || !contains(reason, `regex: ^\[SubsPlease\] `) {
+		t.Errorf("expected regex match, got ok=%v reason=%q", ok, reason)
+	}
+
+	ok, reason = m.Match(models.FeedItem{Title: "The.Daily.Show.2026.10.15.720p.WEB.h264-GRP", ShowName: "The Daily Show", Quality: "720P"})
+	if !ok || !contains(reason, "matches show: Daily Show Clips") || !contains(reason, "glob: the.daily.show.*.web*") {
+		t.Errorf("expected glob match, got ok=%v reason=%q", ok, reason)
+	}
+
+	if ok, _ := m.Match(models.FeedItem{Title: "[Erai-raws] Frieren - 12 [1080p]", ShowName: "Frieren", Quality: "1080P"}); ok {
+		t.Error("title matching neither name nor pattern should not match")
+	}
+}
+
+func TestKeywordFilters(t *testing.T) {
+	cfg := makeMovieCfg("Dune Part Two", "1080P")
+	cfg.Defaults.MustNotContain = []string{"CAM", "Hindi", "Dubbed"}
+	m := NewMatcher(cfg, nil)
+
+	item := func(title string) models.FeedItem {
+		return models.FeedItem{ContentType: models.ContentTypeMovie, Title: title, ShowName: "Dune Part Two", Quality: "1080P"}
+	}
+
+	if ok, reason := m.Match(item("Dune.Part.Two.2024.1080p.CAM.x264-GRP")); ok || reason != "title contains excluded keyword: CAM" {
+		t.Errorf("CAM release: ok=%v reason=%q", ok, reason)
+	}
+	if ok, reason := m.Match(item("Dune.Part.Two.2024.1080p.WEB-DL.Hindi.Dubbed-GRP")); ok || !contains(reason, "excluded keyword: Hindi") {
+		t.Errorf("Hindi release: ok=%v reason=%q", ok, reason)
+	}
+	// Keywords match whole words: "CAM" must not hit "Camera".
+	if ok, reason := m.Match(item("Dune.Part.Two.Camera.Test.2024.1080p.WEB-DL-GRP")); !ok {
+		t.Errorf("Camera should not trip the CAM filter: %q", reason)
+	}
+
+	// A rule's own lists replace the defaults; multi-word keywords span separators.
+	cfg.Movies[0].MustContain = []string{"web dl", "bluray"}
+	cfg.Movies[0].MustNotContain = []string{"hdts"}
+	m.SetShowsConfig(cfg)
+	if ok, reason := m.Match(item("Dune.Part.Two.2024.1080p.WEB-DL.DDP5.1-GRP")); !ok || !contains(reason, "must contain: web dl") {
+		t.Errorf("expected must_contain hit, got ok=%v reason=%q", ok, reason)
+	}
+	if ok, reason := m.Match(item("Dune.Part.Two.2024.1080p.HDTV.x264-GRP")); ok || reason != "title lacks required keyword (one of: web dl, bluray)" {
+		t.Errorf("missing required keyword: ok=%v reason=%q", ok, reason)
+	}
+}
+
+func TestValidateShowsConfig(t *testing.T) {
+	cfg := &models.ShowsConfig{Shows: []models.ShowRule{{Name: "Good", TitleRegex: `S\d+E\d+`, TitleGlob: "good.*"}}}
+	if err := ValidateShowsConfig(cfg); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+	cfg.Shows = append(cfg.Shows, models.ShowRule{Name: "Bad", TitleRegex: `(unclosed`})
+	if err := ValidateShowsConfig(cfg); err == nil || !contains(err.Error(), `show "Bad"`) {
+		t.Errorf("expected regex error naming the rule, got %v", err)
+	}
+	cfg.Shows = cfg.Shows[:1]
+	cfg.Movies = []models.MovieRule{{Name: "Glob", TitleGlob: "movie.[2024"}}
+	if err := ValidateShowsConfig(cfg); err == nil || !contains(err.Error(), "title_glob") {
+		t.Errorf("expected glob error, got %v", err)
+	}
+}
+
+func TestReleaseConstraints(t *testing.T) {
+	cfg := makeShowsCfg("Abbott Elementary", "720P")
+	cfg.Shows[0].MaxQuality = "1080p"
+	cfg.Shows[0].MaxSize = 2
+	cfg.Defaults.MinSize = 0.2
+	cfg.Defaults.AllowedSources = []string{"WEB-DL", "WEBRip"}
+	cfg.Defaults.ExcludedSources = []string{"HDTV"}
+	m := NewMatcher(cfg, nil)
+
+	const gb = 1 << 30
+	item := func(title, quality, source string, size int64) models.FeedItem {
+		return models.FeedItem{Title: title, ShowName: "Abbott Elementary", Season: 4, Episode: 1, Quality: quality, Source: source, Size: size}
+	}
+
+	cases := []struct {
+		name   string
+		item   models.FeedItem
+		reject string
+	}{
+		{"passes", item("Abbott.Elementary.S04E01.1080p.WEB-DL.x264-GRP", "1080P", "WEB-DL", gb), ""},
+		{"service tag before source", item("Abbott.Elementary.S04E01.1080p.AMZN.WEB-DL.x264-GRP", "1080P", "AMZN", gb), ""},
+		{"above max quality", item("Abbott.Elementary.S04E01.2160p.WEB-DL.x265-GRP", "2160P", "WEB-DL", gb), "quality 2160P above maximum 1080p"},
+		{"too large", item("Abbott.Elementary.S04E01.1080p.WEB-DL.x264-GRP", "1080P", "WEB-DL", 3*gb), "size 3.00 GB above maximum 2 GB"},
+		{"too small", item("Abbott.Elementary.S04E01.720p.WEB-DL.x264-GRP", "720P", "WEB-DL", gb/10), "size 0.10 GB below minimum 0.2 GB"},
+		{"excluded source", item("Abbott.Elementary.S04E01.720p.HDTV.x264-GRP", "720P", "HDTV", gb), "source HDTV is excluded"},
+		{"not allowed", item("Abbott.Elementary.S04E01.1080p.BluRay.x264-GRP", "1080P", "BluRay", gb), "source BluRay not in allowed sources (WEB-DL, WEBRip)"},
+		{"unknown source", item("Abbott.Elementary.S04E01.1080p.x264-GRP", "1080P", "", gb), "source unknown not in allowed sources (WEB-DL, WEBRip)"},
+	}
+	for _, tc := range cases {
+		ok, reason := m.Match(tc.item)
+		if tc.reject == "" {
+			if !ok {
+				t.Errorf("%s: expected match, got %q", tc.name, reason)
+			}
+			continue
+		}
+		if ok || reason != tc.reject {
+			t.Errorf("%s: got ok=%v reason=%q, want rejection %q", tc.name, ok, reason, tc.reject)
+		}
+	}
+}
+
+func TestReleaseConstraintsSizePerEpisode(t *testing.T) {
+	const gb = 1 << 30
+	c := models.ReleaseConstraints{MaxSize: 2}
+
+	double := models.FeedItem{Season: 1, Episode: 1, EpisodeEnd: 2, Size: 3 * gb}
+	if r := CheckConstraints(double, c); r != "" {
+		t.Errorf("1.5 GB per episode should pass a 2 GB cap: %q", r)
+	}
+	double.EpisodeEnd = 0
+	if r := CheckConstraints(double, c); r == "" {
+		t.Error("single 3 GB episode should exceed a 2 GB cap")
+	}
+	pack := models.FeedItem{Season: 1, IsSeasonPack: true, Size: 30 * gb}
+	if r := CheckConstraints(pack, c); r != "" {
+		t.Errorf("season packs are exempt from size bounds: %q", r)
+	}
+	unknown := models.FeedItem{Season: 1, Episode: 1}
+	if r := CheckConstraints(unknown, models.ReleaseConstraints{MinSize: 1}); r != "" {
+		t.Errorf("items without a size skip the check: %q", r)
+	}
+}
+
+func TestValidateShowsConfigConstraints(t *testing.T) {
+	cfg := &models.ShowsConfig{Movies: []models.MovieRule{{Name: "Heat", MinSize: 10, MaxSize: 4}}}
+	if err := ValidateShowsConfig(cfg); err == nil || !contains(err.Error(), "min_size") {
+		t.Errorf("expected inverted size error, got %v", err)
+	}
+	cfg.Movies[0] = models.MovieRule{Name: "Heat", MinQuality: "2160p", MaxQuality: "1080p"}
+	if err := ValidateShowsConfig(cfg); err == nil || !contains(err.Error(), "max_quality") {
+		t.Errorf("expected inverted quality error, got %v", err)
+	}
+}
+
+func TestEpisodeRange(t *testing.T) {
+	cfg := makeShowsCfg("The Simpsons", "720P")
+	cfg.Shows[0].FromSeason = 36
+	cfg.Shows[0].FromEpisode = 5
+	m := NewMatcher(cfg, nil)
+
+	ep := func(season, episode, end int) models.FeedItem {
+		return models.FeedItem{ShowName: "The Simpsons", Season: season, Episode: episode, EpisodeEnd: end, IsSeasonPack: episode == 0 && season > 0, Quality: "1080P"}
+	}
+	cases := []struct {
+		name   string
+		item   models.FeedItem
+		reject string
+	}{
+		{"later season", ep(37, 1, 0), ""},
+		{"start episode", ep(36, 5, 0), ""},
+		{"range reaching start", ep(36, 4, 5), ""},
+		{"old season", ep(12, 3, 0), "season 12 before from_season 36"},
+		{"before start episode", ep(36, 4, 0), "S36E04 before S36E05"},
+		{"pack of start season", ep(36, 0, 0), "season pack S36 starts before S36E05"},
+		{"special passes by default", models.FeedItem{ShowName: "The Simpsons", Season: 0, Episode: 3, Quality: "1080P"}, ""},
+		{"date-based passes", models.FeedItem{ShowName: "The Simpsons", AirDate: "2026-10-12", Quality: "1080P"}, ""},
+	}
+	for _, tc := range cases {
+		ok, reason := m.Match(tc.item)
+		if tc.reject == "" {
+			if !ok {
+				t.Errorf("%s: expected match, got %q", tc.name, reason)
+			}
+			continue
+		}
+		if ok || reason != tc.reject {
+			t.Errorf("%s: got ok=%v reason=%q, want %q", tc.name, ok, reason, tc.reject)
+		}
+	}
+
+	cfg.Shows[0] = models.ShowRule{Name: "The Simpsons", OnlySeasons: []int{1, 2}, SkipSpecials: true}
+	m.SetShowsConfig(cfg)
+	if ok, reason := m.Match(ep(3, 1, 0)); ok || reason != "season 3 not in only_seasons (1, 2)" {
+		t.Errorf("only_seasons: ok=%v reason=%q", ok, reason)
+	}
+	if ok, _ := m.Match(ep(2, 1, 0)); !ok {
+		t.Error("listed season should match")
+	}
+	if ok, reason := m.Match(models.FeedItem{ShowName: "The Simpsons", Season: 0, Episode: 3, Quality: "1080P"}); ok || reason != "special S00E03 skipped" {
+		t.Errorf("skip_specials: ok=%v reason=%q", ok, reason)
+	}
+}
+
+func TestQualityProfiles(t *testing.T) {
+	cfg := &models.ShowsConfig{
+		Shows: []models.ShowRule{
+			{Name: "Planet Earth", Profile: "4K-HDR"},
+			{Name: "Ghosts", Profile: "Sitcom", PreferredCodec: "x265"},
+			{Name: "Severance"},
+		},
+		Profiles: []models.QualityProfile{
+			{Name: "4K-HDR", DefaultRules: models.DefaultRules{MinQuality: "2160p", PreferredHDR: []string{"dv"}}},
+			{Name: "Sitcom", DefaultRules: models.DefaultRules{MaxQuality: "720p", PreferredCodec: "x264"}},
+		},
+		Defaults: models.DefaultRules{MinQuality: "720p", PreferredCodec: "x264", ExcludeGroups: []string{"YIFY"}},
+	}
+	m := NewMatcher(cfg, nil)
+
+	// Profile fields apply over the defaults...
+	if ok, reason := m.Match(models.FeedItem{ShowName: "Planet Earth", Quality: "1080P"}); ok || reason != "quality 1080P below minimum 2160p" {
+		t.Errorf("profile min_quality: ok=%v reason=%q", ok, reason)
+	}
+	ok, reason := m.Match(models.FeedItem{ShowName: "Planet Earth", Quality: "2160P", HDR: []string{"dv"}})
+	if !ok || !contains(reason, "profile: 4K-HDR") || !contains(reason, "hdr: dv") {
+		t.Errorf("expected profile match with hdr preference, got ok=%v reason=%q", ok, reason)
+	}
+	// ...defaults still fill what the profile leaves unset...
+	if ok, reason := m.Match(models.FeedItem{ShowName: "Planet Earth", Quality: "2160P", ReleaseGroup: "YIFY"}); ok || !contains(reason, "excluded") {
+		t.Errorf("defaults should fill unset profile fields: ok=%v reason=%q", ok, reason)
+	}
+	// ...and rule fields override the profile.
+	ok, reason = m.Match(models.FeedItem{ShowName: "Ghosts", Quality: "720P", Codec: "x265"})
+	if !ok || !contains(reason, "preferred codec: x265") {
+		t.Errorf("rule override: ok=%v reason=%q", ok, reason)
+	}
+	if ok, reason := m.Match(models.FeedItem{ShowName: "Ghosts", Quality: "1080P"}); ok || reason != "quality 1080P above maximum 720p" {
+		t.Errorf("profile max_quality: ok=%v reason=%q", ok, reason)
+	}
+	if ok, reason := m.Match(models.FeedItem{ShowName: "Severance", Quality: "1080P"}); !ok || contains(reason, "profile:") {
+		t.Errorf("rule without profile: ok=%v reason=%q", ok, reason)
+	}
+
+	if err := ValidateShowsConfig(cfg); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+	cfg.Movies = []models.MovieRule{{Name: "Heat", Profile: "Missing"}}
+	if err := ValidateShowsConfig(cfg); err == nil || !contains(err.Error(), `unknown profile "Missing"`) {
+		t.Errorf("expected unknown profile error, got %v", err)
+	}
+	cfg.Movies = nil
+	cfg.Profiles = append(cfg.Profiles, models.QualityProfile{Name: "sitcom"})
+	if err := ValidateShowsConfig(cfg); err == nil || !contains(err.Error(), "duplicate") {
+		t.Errorf("expected duplicate profile error, got %v", err)
+	}
+}
+
+func TestCustomFormatsAndMinScore(t *testing.T) {
+	minScore := 50
+	seeders := 3
+	cfg := &models.ShowsConfig{
+		Shows: []models.ShowRule{
+			{Name: "Shogun", PreferredCodec: "x265"},
+			{Name: "Slow Horses", MinScore: &minScore},
+		},
+		CustomFormats: []models.CustomFormat{
+			{Name: "Remux", Weight: 30, Conditions: []models.FormatCondition{{Field: "title", Values: []string{`\bremux\b`}}}},
+			{Name: "Lean 1080p", Weight: 15, Conditions: []models.FormatCondition{
+				{Field: "quality", Values: []string{"1080p"}},
+				{Field: "size", Max: 3},
+			}},
+			{Name: "Not AMZN", Weight: -5, Conditions: []models.FormatCondition{{Field: "source", Values: []string{"AMZN"}, Negate: true}}},
+			{Name: "Thin swarm", Weight: -10, Conditions: []models.FormatCondition{{Field: "seeders", Max: 5}}},
+		},
+	}
+	m := NewMatcher(cfg, nil)
+
+	item := models.FeedItem{
+		Title:    "Shogun.2024.S01E03.1080p.AMZN.WEB-DL.x265-GRP",
+		ShowName: "Shogun", Quality: "1080P", Codec: "x265", Source: "AMZN",
+		Size: 2 * 1024 * 1024 * 1024, Seeders: &seeders,
+	}
+	res := m.Evaluate(item)
+	// 1080p tier 40 + preferred codec 4 + Lean 1080p 15 + Thin swarm -10.
+	if !res.Matched || res.Score != 49 {
+		t.Fatalf("expected match with score 49, got %+v", res)
+	}
+	if !contains(res.Reason, "formats: Lean 1080p+Thin swarm") {
+		t.Errorf("reason %q does not list matched formats", res.Reason)
+	}
+	// Scoring is deterministic for the same item and config.
+	if again := m.Evaluate(item); again.Score != res.Score || again.Reason != res.Reason {
+		t.Errorf("non-deterministic result: %+v vs %+v", again, res)
+	}
+
+	// The per-rule minimum rejects low-scoring releases.
+	low := models.FeedItem{Title: "Slow.Horses.S04E01.720p.WEB-DL", ShowName: "Slow Horses", Quality: "720P"}
+	if ok, reason := m.Match(low); ok || reason != "release score 15 below minimum 50" {
+		t.Errorf("min_score: ok=%v reason=%q", ok, reason)
+	}
+	high := models.FeedItem{Title: "Slow.Horses.S04E01.1080p.BluRay.REMUX", ShowName: "Slow Horses", Quality: "1080P"}
+	if res := m.Evaluate(high); !res.Matched || res.Score != 65 {
+		t.Errorf("expected remux to clear min_score with 65, got %+v", res)
+	}
+
+	// MatchAll carries the score onto staged torrents.
+	staged := m.MatchAll([]models.FeedItem{item})
+	if len(staged) != 1 || staged[0].ReleaseScore != 49 {
+		t.Errorf("MatchAll release score: %+v", staged)
+	}
+
+	if err := ValidateShowsConfig(cfg); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+	cfg.CustomFormats = append(cfg.CustomFormats, models.CustomFormat{Name: "Bad", Conditions: []models.FormatCondition{{Field: "colour", Values: []string{"x"}}}})
+	if err := ValidateShowsConfig(cfg); err == nil || !contains(err.Error(), `unknown condition field "colour"`) {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	m := NewMatcher(&models.ShowsConfig{
+		Shows:    []models.ShowRule{{Name: "Dune Prophecy", Profile: "4K"}},
+		Movies:   []models.MovieRule{{Name: "Dune Part Two", PreferredCodec: "x264"}},
+		Profiles: []models.QualityProfile{{Name: "4K", DefaultRules: models.DefaultRules{MinQuality: "2160p"}}},
+		Defaults: models.DefaultRules{PreferredCodec: "x265", ExcludeGroups: []string{"YIFY"}},
+	}, nil)
+
+	exp := m.Explain(models.FeedItem{ShowName: "Dune Part Two", Quality: "1080P", Codec: "x265", ReleaseGroup: "YIFY", ContentType: models.ContentTypeMovie})
+	if exp.Result.Matched || exp.Result.Reason != "release group YIFY is excluded" {
+		t.Errorf("overall result = %+v", exp.Result)
+	}
+	if len(exp.Rules) != 2 {
+		t.Fatalf("expected a trace per rule, got %d", len(exp.Rules))
+	}
+	show, movie := exp.Rules[0], exp.Rules[1]
+	if show.NameMatched || show.Result != nil || show.Checks[0].Source != "profile" || show.Checks[0].Effective != "2160p" {
+		t.Errorf("show trace = %+v", show)
+	}
+	if !movie.NameMatched || movie.Result == nil || movie.Result.Matched {
+		t.Errorf("movie trace = %+v", movie)
+	}
+	byCheck := map[string]CheckTrace{}
+	for _, c := range movie.Checks {
+		byCheck[c.Check] = c
+	}
+	if c := byCheck["exclude_groups"]; c.Passed || c.Source != "defaults" {
+		t.Errorf("exclude_groups = %+v", c)
+	}
+	if c := byCheck["preferred_codec"]; c.Passed || c.Effective != "x264" || c.Source != "rule" {
+		t.Errorf("preferred_codec = %+v", c)
+	}
+}
+
+func TestRuleIndexAgreesWithLinearScan(t *testing.T) {
+	cfg := &models.ShowsConfig{
+		Shows: []models.ShowRule{
+			{Name: "Anime Simulcasts", TitleRegex: `^\[SubsPlease\] `},
+			{Name: "Shōgun (2024)"},
+			{Name: "Shogun"},
+			{Name: "The Office", Aliases: []string{"The Office US"}},
+			{Name: "Law & Order: SVU"},
+			{Name: "Doctor Who 2005"},
+			{Name: "Frieren", TitleGlob: "*frieren*"},
+			{Name: "Ghosts"},
+			{Name: "Ghosts US"},
+		},
+		Movies: []models.MovieRule{
+			{Name: "The Thing 1982"},
+			{Name: "The Thing 2011"},
+			{Name: "Dune", Aliases: []string{"Dune Part One"}},
+		},
+	}
+	m := NewMatcher(cfg, nil)
+
+	shows := []models.FeedItem{
+		{ShowName: "Shogun 2024"},
+		{ShowName: "Shogun"},
+		{ShowName: "Shogun 1980"},
+		{ShowName: "The Office US"},
+		{ShowName: "The Office UK"},
+		{ShowName: "Ghosts US"},
+		{ShowName: "Ghosts UK"},
+		{ShowName: "Law and Order SVU"},
+		{ShowName: "Doctor Who"},
+		{ShowName: "Doctor Who 1963"},
+		{ShowName: "Frieren", Title: "[SubsPlease] Sousou no Frieren - 12 (1080p)"},
+		{ShowName: "Sousou no Frieren", Title: "Sousou.no.Frieren.S01E12.1080p"},
+		{ShowName: "Severance", Title: "Severance.S02E01.1080p"},
+	}
+	for _, item := range shows {
+		want, wantVia := FindShowRule(cfg.Shows, item)
+		got, _ := m.FindRule(item)
+		_, gotVia := m.snap.Load().shows.find(item)
+		if (want == nil) != (got == nil) || (want != nil && (want.Name != got.Name || wantVia != gotVia)) {
+			t.Errorf("%+v: index found %v/%q, linear scan %v/%q", item, got, gotVia, want, wantVia)
+		}
+	}
+
+	movies := []models.FeedItem{
+		{ShowName: "The Thing", ReleaseYear: 2011},
+		{ShowName: "The Thing", ReleaseYear: 1982},
+		{ShowName: "The Thing"},
+		{ShowName: "Dune Part One", ReleaseYear: 2021},
+		{ShowName: "Heat", ReleaseYear: 1995},
+	}
+	for _, item := range movies {
+		item.ContentType = models.ContentTypeMovie
+		want, wantVia := FindMovieRule(cfg.Movies, item)
+		_, got := m.FindRule(item)
+		_, gotVia := m.snap.Load().movies.find(item)
+		if (want == nil) != (got == nil) || (want != nil && (want.Name != got.Name || wantVia != gotVia)) {
+			t.Errorf("%+v: index found %v/%q, linear scan %v/%q", item, got, gotVia, want, wantVia)
+		}
+	}
+}
+
+func TestSnapshotIsolation(t *testing.T) {
+	cfg := makeShowsCfg("Severance", "1080P")
+	m := NewMatcher(cfg, nil)
+
+	// Edits to the caller's config or to a ShowsConfig copy are invisible
+	// until published with SetShowsConfig.
+	cfg.Shows[0].Name = "Andor"
+	cp := m.ShowsConfig()
+	cp.Shows[0].MinQuality = "2160P"
+	if ok, reason := m.Match(models.FeedItem{ShowName: "Severance", Quality: "1080P"}); !ok {
+		t.Fatalf("snapshot changed without SetShowsConfig: %q", reason)
+	}
+	m.SetShowsConfig(cp)
+	if ok, _ := m.Match(models.FeedItem{ShowName: "Severance", Quality: "1080P"}); ok {
+		t.Error("published config not applied")
+	}
+
+	// Concurrent reloads and matches must not race (run with -race).
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				m.Match(models.FeedItem{ShowName: "Severance", Quality: "2160P"})
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				next := m.ShowsConfig()
+				next.Shows = append(next.Shows, models.ShowRule{Name: fmt.Sprintf("Show %d-%d", i, j)})
+				m.SetShowsConfig(next)
+				m.SetDefaults(models.DefaultRules{MinQuality: "720P"})
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestRuleSettingsRouting(t *testing.T) {
+	cfg := &models.ShowsConfig{
+		Shows: []models.ShowRule{
+			{Name: "Planet Earth", Profile: "4K", Tags: []string{"nature"}},
+			{Name: "Severance"},
+		},
+		Profiles: []models.QualityProfile{{Name: "4K", DefaultRules: models.DefaultRules{Category: "tv-4k"}}},
+		Defaults: models.DefaultRules{Category: "tv", SavePath: "/media/tv/{show}/Season {season:02}"},
+	}
+	m := NewMatcher(cfg, nil)
+
+	rules, ok := m.RuleSettings(models.FeedItem{ShowName: "Planet Earth"})
+	if !ok || rules.Category != "tv-4k" || len(rules.Tags) != 1 || rules.SavePath != cfg.Defaults.SavePath {
+		t.Errorf("rule → profile → defaults routing = %+v (ok=%v)", rules, ok)
+	}
+	if rules, _ := m.RuleSettings(models.FeedItem{ShowName: "Severance"}); rules.Category != "tv" {
+		t.Errorf("defaults routing = %+v", rules)
+	}
+	if _, ok := m.RuleSettings(models.FeedItem{ShowName: "Andor"}); ok {
+		t.Error("unmatched item should have no rule settings")
+	}
+
+	cfg.Shows[1].SavePath = "/media/tv/{series}"
+	if err := ValidateShowsConfig(cfg); err == nil || !contains(err.Error(), "invalid save_path") {
+		t.Errorf("expected save_path error, got %v", err)
+	}
+}
+
+func TestCheckUpgrade(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	queued := models.StagedTorrent{
+		ID:       1,
+		StagedAt: now.Add(-48 * time.Hour),
+		FeedItem: models.FeedItem{Quality: "1080p", HDR: []string{"hdr10"}},
+	}
+	no := false
+	cases := []struct {
+		name string
+		cand models.FeedItem
+		eff  models.DefaultRules
+		want bool
+	}{
+		{"higher tier", models.FeedItem{Quality: "2160p"}, models.DefaultRules{}, true},
+		{"lower tier", models.FeedItem{Quality: "720p", Revision: 2}, models.DefaultRules{}, false},
+		{"same release", models.FeedItem{Quality: "1080p", HDR: []string{"hdr10"}}, models.DefaultRules{}, false},
+		{"preferred HDR", models.FeedItem{Quality: "1080p", HDR: []string{"dv"}}, models.DefaultRules{PreferredHDR: []string{"dv"}}, true},
+		{"revision", models.FeedItem{Quality: "1080p", Revision: 1}, models.DefaultRules{}, true},
+		{"disabled", models.FeedItem{Quality: "2160p"}, models.DefaultRules{UpgradesAllowed: &no}, false},
+		{"cutoff met", models.FeedItem{Quality: "2160p"}, models.DefaultRules{UpgradeCutoff: "1080p"}, false},
+		{"cutoff met, revision", models.FeedItem{Quality: "1080p", Revision: 1}, models.DefaultRules{UpgradeCutoff: "1080p"}, true},
+		{"cutoff not met", models.FeedItem{Quality: "2160p"}, models.DefaultRules{UpgradeCutoff: "2160p"}, true},
+		{"window passed", models.FeedItem{Quality: "2160p"}, models.DefaultRules{UpgradeWindowDays: 1}, false},
+		{"within window", models.FeedItem{Quality: "2160p"}, models.DefaultRules{UpgradeWindowDays: 3}, true},
+	}
+	for _, tc := range cases {
+		reason, ok := CheckUpgrade(queued, tc.cand, tc.eff, now)
+		if ok != tc.want {
+			t.Errorf("%s: ok = %v (%s), want %v", tc.name, ok, reason, tc.want)
+		}
+	}
+
+	// An unparsed quality ranks below every known tier, including 480p.
+	unknownOld := models.StagedTorrent{StagedAt: now, FeedItem: models.FeedItem{}}
+	if reason, ok := CheckUpgrade(unknownOld, models.FeedItem{Quality: "480p"}, models.DefaultRules{}, now); !ok {
+		t.Errorf("480p over unknown quality: declined (%s), want upgrade", reason)
+	}
+	low := models.StagedTorrent{StagedAt: now, FeedItem: models.FeedItem{Quality: "480p"}}
+	if reason, ok := CheckUpgrade(low, models.FeedItem{Revision: 1}, models.DefaultRules{}, now); ok || reason != "unknown quality" {
+		t.Errorf("unknown-quality candidate over 480p: ok=%v reason=%q, want declined as unknown quality", ok, reason)
+	}
+
+	cfg := &models.ShowsConfig{
+		Shows:    []models.ShowRule{{Name: "Severance", UpgradeCutoff: "8k"}},
+		Defaults: models.DefaultRules{UpgradeCutoff: "2160p"},
+	}
+	if err := ValidateShowsConfig(cfg); err == nil || !contains(err.Error(), "upgrade_cutoff") {
+		t.Errorf("expected upgrade_cutoff error, got %v", err)
+	}
+}
+
+func TestMigrateLegacyRules(t *testing.T) {
+	legacy := models.MatchRule{
+		ShowNames:       []string{"Severance", " The Office (US) ", "severance", ""},
+		MinQuality:      "1080p",
+		PreferredCodec:  "x265",
+		ExcludeGroups:   []string{"YIFY"},
+		PreferredGroups: []string{"NTb"},
+	}
+
+	fresh, added := MigrateLegacyRules(nil, legacy)
+	if added != 2 || len(fresh.Shows) != 2 || fresh.Shows[1].Name != "The Office (US)" {
+		t.Fatalf("fresh shows = %+v (added %d), want Severance and The Office (US)", fresh.Shows, added)
+	}
+	if fresh.Movies == nil || fresh.Defaults.MinQuality != "1080p" || fresh.Defaults.ExcludeGroups[0] != "YIFY" {
+		t.Errorf("fresh config = %+v", fresh)
+	}
+
+	// Existing rules and defaults win; the base is left untouched.
+	base := &models.ShowsConfig{
+		Shows:    []models.ShowRule{{Name: "Lumon", Aliases: []string{"Severance"}}},
+		Defaults: models.DefaultRules{MinQuality: "2160p"},
+	}
+	merged, added := MigrateLegacyRules(base, legacy)
+	if added != 1 || len(merged.Shows) != 2 || merged.Shows[1].Name != "The Office (US)" {
+		t.Errorf("merged shows = %+v (added %d)", merged.Shows, added)
+	}
+	if merged.Defaults.MinQuality != "2160p" || merged.Defaults.PreferredCodec != "x265" {
+		t.Errorf("merged defaults = %+v", merged.Defaults)
+	}
+	if len(base.Shows) != 1 || base.Defaults.PreferredCodec != "" {
+		t.Error("base config was modified")
+	}
+
+	m := NewMatcher(merged, nil)
+	if ok, _ := m.Match(models.FeedItem{ShowName: "The Office US", Quality: "2160p"}); !ok {
+		t.Error("migrated rule does not match")
+	}
+}
@@ -0,0 +1,93 @@
+package matcher
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// diacriticFold maps accented Latin letters to their base letter so "Shōgun"
+// and "Pokémon" compare equal to the ASCII spellings used in release names.
+// Lowercase only: titles are lowercased before folding.
+var diacriticFold = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l", 'ľ': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ŕ': "r", 'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ß': "ss", 'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// trailingYearRe matches a normalized title ending in a release year.
+var trailingYearRe = regexp.MustCompile(`^(.+) (?:19|20)\d{2}$`)
+
+// movieRuleYearRe splits a raw movie rule name into title and year
+// ("Joker 2019"); see MovieRuleNameParts.
+var movieRuleYearRe = regexp.MustCompile(`^(.*?)\s+((19|20)\d{2})$`)
+
+// trailingCountryRe matches a normalized title ending in the country code
+// scene releases use to tell regional versions apart ("the office us").
+var trailingCountryRe = regexp.MustCompile(`^(.+) (?:us|uk|au|nz|ca)$`)
+
+// NormalizeTitle reduces a show or movie title to a comparison key: lowercase,
+// diacritics folded, "&" spelled "and", apostrophes dropped ("Marvel's" →
+// "marvels"), dotted acronyms joined ("S.H.I.E.L.D." → "shield") and all other
+// punctuation collapsed to single spaces, so "Law & Order: SVU",
+// "Law.and.Order.SVU" and "The Office (US)" / "The.Office.US" line up.
+func NormalizeTitle(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if f, ok := diacriticFold[r]; ok {
+			b.WriteString(f)
+			continue
+		}
+		switch {
+		case r == '&':
+			b.WriteString(" and ")
+		case r == '\'' || r == '’' || r == '`':
+			// dropped: "marvel's" → "marvels"
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte(' ')
+		}
+	}
+
+	// Join runs of single-letter tokens so a dotted acronym matches its
+	// undotted spelling.
+	words := strings.Fields(b.String())
+	out := make([]string, 0, len(words))
+	for i := 0; i < len(words); i++ {
+		if len([]rune(words[i])) != 1 || i+1 >= len(words) || len([]rune(words[i+1])) != 1 {
+			out = append(out, words[i])
+			continue
+		}
+		acronym := words[i]
+		for i+1 < len(words) && len([]rune(words[i+1])) == 1 {
+			i++
+			acronym += words[i]
+		}
+		out = append(out, acronym)
+	}
+	return strings.Join(out, " ")
+}
+
+// stripTrailingCountry returns a normalized title without its trailing
+// country code.
+func stripTrailingCountry(norm string) (string, bool) {
+	if m := trailingCountryRe.FindStringSubmatch(norm); m != nil {
+		return m[1], true
+	}
+	return norm, false
+}
+
+// stripTrailingYear returns a normalized title without its trailing year.
+func stripTrailingYear(norm string) (string, bool) {
+	if m := trailingYearRe.FindStringSubmatch(norm); m != nil {
+		return m[1], true
+	}
+	return norm, false
+}
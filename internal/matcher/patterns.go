@@ -0,0 +1,227 @@
+package matcher
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/killakam3084/rss-curator/internal/routing"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// titleRegexCache holds compiled TitleRegex patterns keyed by source so a
+// feed check does not recompile every rule for every item.
+var titleRegexCache sync.Map // string → *regexp.Regexp
+
+// compileTitleRegex compiles expr case-insensitively, memoizing successes.
+func compileTitleRegex(expr string) (*regexp.Regexp, error) {
+	if re, ok := titleRegexCache.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return nil, err
+	}
+	titleRegexCache.Store(expr, re)
+	return re, nil
+}
+
+// matchTitleRegex reports whether expr matches anywhere in title. Invalid
+// expressions never match (ValidateShowsConfig rejects them on save).
+func matchTitleRegex(title, expr string) bool {
+	if expr == "" {
+		return false
+	}
+	re, err := compileTitleRegex(expr)
+	return err == nil && re.MatchString(title)
+}
+
+// matchTitleGlob reports whether the whole title matches a shell glob
+// ("Show.Name.S01*"), case-insensitively.
+func matchTitleGlob(title, glob string) bool {
+	if glob == "" {
+		return false
+	}
+	ok, err := path.Match(strings.ToLower(glob), strings.ToLower(title))
+	return err == nil && ok
+}
+
+// titleWords splits s into lowercase letter/digit runs.
+func titleWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsKeyword reports whether the words of kw appear consecutively in
+// words, so "CAM" matches "Movie.2023.CAM.x264" but not "Camera", and
+// "web dl" matches "WEB-DL".
+func containsKeyword(words []string, kw string) bool {
+	want := titleWords(kw)
+	if len(want) == 0 {
+		return false
+	}
+	for i := 0; i+len(want) <= len(words); i++ {
+		match := true
+		for j := range want {
+			if words[i+j] != want[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateKeywords applies must-contain / must-not-contain lists to the raw
+// title. It returns the reason fragment for the required keyword that fired,
+// or a rejection naming the keyword responsible.
+func evaluateKeywords(title string, mustContain, mustNotContain []string) (reason, rejection string) {
+	words := titleWords(title)
+	for _, kw := range mustNotContain {
+		if containsKeyword(words, kw) {
+			return "", fmt.Sprintf("title contains excluded keyword: %s", strings.TrimSpace(kw))
+		}
+	}
+	if len(mustContain) == 0 {
+		return "", ""
+	}
+	for _, kw := range mustContain {
+		if containsKeyword(words, kw) {
+			return fmt.Sprintf("must contain: %s", strings.TrimSpace(kw)), ""
+		}
+	}
+	return "", fmt.Sprintf("title lacks required keyword (one of: %s)", strings.Join(mustContain, ", "))
+}
+
+// matchRulePatterns checks a rule's title regex and glob, returning the reason
+// fragment naming the pattern that fired.
+func matchRulePatterns(title, regex, glob string) (string, bool) {
+	if matchTitleRegex(title, regex) {
+		return "regex: " + regex, true
+	}
+	if matchTitleGlob(title, glob) {
+		return "glob: " + glob, true
+	}
+	return "", false
+}
+
+// ValidateShowsConfig reports the first rule whose title regex or glob does
+// not compile, whose size bounds or quality range are inverted, whose episode
+// bounds are malformed or whose profile does not exist, plus unnamed or
+// duplicate profiles, malformed custom formats, bad save_path templates,
+// malformed upgrade policies and unknown retire_after values, so a bad rule
+// is rejected on save instead of silently never matching.
+func ValidateShowsConfig(cfg *models.ShowsConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	check := func(label, regex, glob, minQuality string, c models.ReleaseConstraints) error {
+		if regex != "" {
+			if _, err := compileTitleRegex(regex); err != nil {
+				return fmt.Errorf("%s: invalid title_regex: %w", label, err)
+			}
+		}
+		if glob != "" {
+			if _, err := path.Match(glob, ""); err != nil {
+				return fmt.Errorf("%s: invalid title_glob: %w", label, err)
+			}
+		}
+		if c.MinSize < 0 || c.MaxSize < 0 {
+			return fmt.Errorf("%s: min_size and max_size must not be negative", label)
+		}
+		if c.MinSize > 0 && c.MaxSize > 0 && c.MinSize > c.MaxSize {
+			return fmt.Errorf("%s: min_size %g exceeds max_size %g", label, c.MinSize, c.MaxSize)
+		}
+		if minQuality != "" && c.MaxQuality != "" && exceedsQuality(minQuality, c.MaxQuality) {
+			return fmt.Errorf("%s: min_quality %s exceeds max_quality %s", label, minQuality, c.MaxQuality)
+		}
+		return nil
+	}
+	checkSettings := func(label string, d models.DefaultRules) error {
+		if err := routing.ValidateSavePath(d.SavePath); err != nil {
+			return fmt.Errorf("%s: invalid save_path: %w", label, err)
+		}
+		if _, ok := qualityRank[strings.ToUpper(d.UpgradeCutoff)]; d.UpgradeCutoff != "" && !ok {
+			return fmt.Errorf("%s: unknown upgrade_cutoff %q", label, d.UpgradeCutoff)
+		}
+		if d.UpgradeWindowDays < 0 {
+			return fmt.Errorf("%s: upgrade_window_days must not be negative", label)
+		}
+		return nil
+	}
+	if err := check("defaults", "", "", cfg.Defaults.MinQuality, cfg.Defaults.Constraints()); err != nil {
+		return err
+	}
+	if err := checkSettings("defaults", cfg.Defaults); err != nil {
+		return err
+	}
+	if err := validateCustomFormats(cfg.CustomFormats); err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(cfg.Profiles))
+	for _, p := range cfg.Profiles {
+		key := strings.ToLower(strings.TrimSpace(p.Name))
+		if key == "" {
+			return fmt.Errorf("profile: name is required")
+		}
+		if seen[key] {
+			return fmt.Errorf("profile %q: duplicate name", p.Name)
+		}
+		seen[key] = true
+		if err := check(fmt.Sprintf("profile %q", p.Name), "", "", p.MinQuality, p.Constraints()); err != nil {
+			return err
+		}
+		if err := checkSettings(fmt.Sprintf("profile %q", p.Name), p.DefaultRules); err != nil {
+			return err
+		}
+	}
+	profileExists := func(label, name string) error {
+		if name != "" && cfg.Profile(name) == nil {
+			return fmt.Errorf("%s: unknown profile %q", label, name)
+		}
+		return nil
+	}
+	for _, r := range cfg.Shows {
+		label := fmt.Sprintf("show %q", r.Name)
+		if err := check(label, r.TitleRegex, r.TitleGlob, r.MinQuality, r.Constraints()); err != nil {
+			return err
+		}
+		if err := profileExists(label, r.Profile); err != nil {
+			return err
+		}
+		if err := checkSettings(label, r.Settings()); err != nil {
+			return err
+		}
+		if r.FromSeason < 0 || r.FromEpisode < 0 {
+			return fmt.Errorf("%s: from_season and from_episode must not be negative", label)
+		}
+		if r.FromEpisode > 0 && r.FromSeason == 0 {
+			return fmt.Errorf("%s: from_episode requires from_season", label)
+		}
+	}
+	for _, r := range cfg.Movies {
+		label := fmt.Sprintf("movie %q", r.Name)
+		if err := check(label, r.TitleRegex, r.TitleGlob, r.MinQuality, r.Constraints()); err != nil {
+			return err
+		}
+		if err := profileExists(label, r.Profile); err != nil {
+			return err
+		}
+		if err := checkSettings(label, r.Settings()); err != nil {
+			return err
+		}
+		switch r.RetireAfter {
+		case "", models.RetireAfterQueued, models.RetireAfterCutoff:
+		default:
+			return fmt.Errorf("%s: retire_after must be %q or %q", label, models.RetireAfterQueued, models.RetireAfterCutoff)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package matcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// Release score weights. A quality tier is worth more than every built-in
+// preference combined, so only custom formats can lift a lower resolution
+// over a higher one.
+const (
+	scorePerQualityTier  = 20 // 480p=0, 720p=20, 1080p=40, 2160p=60
+	scorePreferredCodec  = 4
+	scorePreferredHDR    = 4
+	scorePreferredGroup  = 2
+	scorePerAttributeHit = 1
+)
+
+// Custom format condition fields.
+const (
+	formatFieldTitle      = "title"
+	formatFieldFreeleech  = "freeleech"
+	formatFieldSizeGB     = "size"
+	formatFieldRevision   = "revision"
+	formatFieldBitDepth   = "bit_depth"
+	formatFieldSeeders    = "seeders"
+	formatFieldQuality    = "quality"
+	formatFieldCodec      = "codec"
+	formatFieldSource     = "source"
+	formatFieldGroup      = "group"
+	formatFieldHDR        = "hdr"
+	formatFieldAudio      = "audio"
+	formatFieldEdition    = "edition"
+	formatFieldLanguage   = "language"
+	formatFieldNotDefined = ""
+)
+
+// ReleasePreferences are the effective rule preferences that feed the
+// built-in part of the release score.
+type ReleasePreferences struct {
+	Codec  string
+	Groups []string
+	HDR    []string
+	// AttributeHits is the number of satisfied audio/bit-depth/edition/
+	// language preferences (len(AttributeResult.Preferred)).
+	AttributeHits int
+}
+
+// ScoreRelease computes item's release score: the quality tier, the rule's
+// preferences and the weight of every matching custom format. It returns the
+// score and the names of the formats that matched, in config order. The
+// result depends only on its inputs.
+func ScoreRelease(item models.FeedItem, prefs ReleasePreferences, formats []models.CustomFormat) (int, []string) {
+	score := qualityRank[strings.ToUpper(item.Quality)] * scorePerQualityTier
+	if prefs.Codec != "" && strings.EqualFold(item.Codec, prefs.Codec) {
+		score += scorePreferredCodec
+	}
+	if len(item.HDR) > 0 && isPreferredHDR(item.HDR, prefs.HDR) {
+		score += scorePreferredHDR
+	}
+	if isPreferredGroup(item.ReleaseGroup, prefs.Groups) {
+		score += scorePreferredGroup
+	}
+	score += prefs.AttributeHits * scorePerAttributeHit
+
+	var hits []string
+	for _, f := range formats {
+		if formatMatches(item, f) {
+			score += f.Weight
+			hits = append(hits, f.Name)
+		}
+	}
+	return score, hits
+}
+
+// formatMatches reports whether item satisfies every condition of f. A format
+// without conditions never matches.
+func formatMatches(item models.FeedItem, f models.CustomFormat) bool {
+	if len(f.Conditions) == 0 {
+		return false
+	}
+	for _, c := range f.Conditions {
+		if conditionMatches(item, c) == c.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// conditionMatches evaluates c against item, ignoring c.Negate.
+func conditionMatches(item models.FeedItem, c models.FormatCondition) bool {
+	switch strings.ToLower(c.Field) {
+	case formatFieldTitle:
+		for _, expr := range c.Values {
+			if matchTitleRegex(item.Title, expr) {
+				return true
+			}
+		}
+		return false
+	case formatFieldQuality:
+		return anyFold([]string{item.Quality}, c.Values)
+	case formatFieldCodec:
+		return anyFold([]string{item.Codec}, c.Values)
+	case formatFieldSource:
+		words := titleWords(item.Title)
+		for _, v := range c.Values {
+			if hasSource(item, words, v) {
+				return true
+			}
+		}
+		return false
+	case formatFieldGroup:
+		return anyFold([]string{item.ReleaseGroup}, c.Values)
+	case formatFieldHDR:
+		return anyFold(item.HDR, c.Values)
+	case formatFieldAudio:
+		return anyFold(item.Audio, c.Values)
+	case formatFieldEdition:
+		return anyFold([]string{item.Edition}, c.Values)
+	case formatFieldLanguage:
+		return len(matchedLanguages(item, c.Values)) > 0
+	case formatFieldFreeleech:
+		return item.IsFreeleech()
+	case formatFieldSizeGB:
+		return item.Size > 0 && inRange(float64(item.Size)/bytesPerGB, c.Min, c.Max)
+	case formatFieldRevision:
+		return inRange(float64(item.Revision), c.Min, c.Max)
+	case formatFieldBitDepth:
+		return item.BitDepth > 0 && inRange(float64(item.BitDepth), c.Min, c.Max)
+	case formatFieldSeeders:
+		return item.Seeders != nil && inRange(float64(*item.Seeders), c.Min, c.Max)
+	}
+	return false
+}
+
+// anyFold reports whether any non-empty value in have equals one in want,
+// ignoring case.
+func anyFold(have, want []string) bool {
+	return len(intersectFold(have, want)) > 0
+}
+
+// inRange reports whether v lies within [min, max]; a zero bound is open.
+func inRange(v, min, max float64) bool {
+	return (min == 0 || v >= min) && (max == 0 || v <= max)
+}
+
+// validateCustomFormats reports the first unnamed, duplicate or malformed
+// custom format.
+func validateCustomFormats(formats []models.CustomFormat) error {
+	seen := make(map[string]bool, len(formats))
+	for _, f := range formats {
+		key := strings.ToLower(strings.TrimSpace(f.Name))
+		if key == "" {
+			return fmt.Errorf("custom format: name is required")
+		}
+		if seen[key] {
+			return fmt.Errorf("custom format %q: duplicate name", f.Name)
+		}
+		seen[key] = true
+		if len(f.Conditions) == 0 {
+			return fmt.Errorf("custom format %q: at least one condition is required", f.Name)
+		}
+		for _, c := range f.Conditions {
+			if err := validateCondition(c); err != nil {
+				return fmt.Errorf("custom format %q: %w", f.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateCondition(c models.FormatCondition) error {
+	field := strings.ToLower(c.Field)
+	switch field {
+	case formatFieldTitle:
+		if len(c.Values) == 0 {
+			return fmt.Errorf("title condition needs at least one pattern")
+		}
+		for _, expr := range c.Values {
+			if _, err := regexp.Compile("(?i)" + expr); err != nil {
+				return fmt.Errorf("invalid title pattern %q: %w", expr, err)
+			}
+		}
+	case formatFieldQuality, formatFieldCodec, formatFieldSource, formatFieldGroup,
+		formatFieldHDR, formatFieldAudio, formatFieldEdition, formatFieldLanguage:
+		if len(c.Values) == 0 {
+			return fmt.Errorf("%s condition needs at least one value", field)
+		}
+	case formatFieldSizeGB, formatFieldRevision, formatFieldBitDepth, formatFieldSeeders:
+		if c.Min < 0 || c.Max < 0 || (c.Max > 0 && c.Min > c.Max) {
+			return fmt.Errorf("%s condition has an invalid min/max range", field)
+		}
+	case formatFieldFreeleech:
+	case formatFieldNotDefined:
+		return fmt.Errorf("condition field is required")
+	default:
+		return fmt.Errorf("unknown condition field %q", c.Field)
+	}
+	return nil
+}
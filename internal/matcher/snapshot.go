@@ -0,0 +1,182 @@
+package matcher
+
+import (
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// snapshot is an immutable, compiled view of the matcher's configuration.
+// The Matcher swaps whole snapshots atomically, so a match in progress always
+// sees one consistent watchlist and never takes a lock.
+type snapshot struct {
+	cfg     *models.ShowsConfig // private copy; nil in legacy-rules mode
+	legacy  *models.MatchRule
+	shows   ruleIndex
+	movies  ruleIndex
+	formats []models.CustomFormat
+}
+
+// compiledRule is one show or movie rule with its effective settings (rule →
+// profile → defaults) resolved at compile time.
+type compiledRule struct {
+	kind    string // "show" or "movie"
+	name    string
+	profile string
+	regex   string
+	glob    string
+	show    *models.ShowRule  // nil for movie rules
+	movie   *models.MovieRule // nil for show rules
+	eff     models.DefaultRules
+}
+
+// nameKey is one indexed spelling of a rule name or alias.
+type nameKey struct {
+	rule  int    // index into ruleIndex.rules
+	order int    // 0 for the rule name, 1+i for alias i
+	via   string // reason fragment: "" for the name, "alias: X" otherwise
+	year  int    // movie year qualifier from the name; 0 = any
+	// stripped marks a key that is the rule name without its trailing year,
+	// which only matches an item name that carries no year of its own.
+	stripped bool
+}
+
+// ruleIndex finds the rule claiming an item in O(1) for name and alias
+// matches. Title-pattern rules cannot be indexed and are scanned, but only
+// those ordered before the best name hit.
+type ruleIndex struct {
+	rules    []compiledRule
+	names    map[string][]nameKey // NormalizeTitle key → candidates
+	patterns []int                // rules with a title_regex or title_glob, in order
+	// countryFallback retries an unmatched name without its trailing country
+	// code, as FindShowRule does; set for shows only.
+	countryFallback bool
+}
+
+// compile builds a snapshot from cfg (nil selects legacy mode). The
+// top-level slices are copied, so later edits to cfg by the caller never
+// reach a published snapshot.
+func compile(cfg *models.ShowsConfig, legacy *models.MatchRule) *snapshot {
+	s := &snapshot{legacy: legacy}
+	if cfg == nil {
+		return s
+	}
+	s.cfg = cloneShowsConfig(cfg)
+	s.formats = s.cfg.CustomFormats
+
+	s.shows.names = make(map[string][]nameKey)
+	s.shows.countryFallback = true
+	for i := range s.cfg.Shows {
+		r := &s.cfg.Shows[i]
+		s.shows.add(compiledRule{
+			kind: "show", name: r.Name, profile: r.Profile,
+			regex: r.TitleRegex, glob: r.TitleGlob, show: r,
+			eff: r.Settings().WithFallback(s.cfg.RuleDefaults(r.Profile)),
+		}, r.Aliases, false)
+	}
+	s.movies.names = make(map[string][]nameKey)
+	for i := range s.cfg.Movies {
+		r := &s.cfg.Movies[i]
+		s.movies.add(compiledRule{
+			kind: "movie", name: r.Name, profile: r.Profile,
+			regex: r.TitleRegex, glob: r.TitleGlob, movie: r,
+			eff: r.Settings().WithFallback(s.cfg.RuleDefaults(r.Profile)),
+		}, r.Aliases, true)
+	}
+	return s
+}
+
+// cloneShowsConfig copies cfg and its top-level slices.
+func cloneShowsConfig(cfg *models.ShowsConfig) *models.ShowsConfig {
+	cp := *cfg
+	cp.Shows = append([]models.ShowRule(nil), cfg.Shows...)
+	cp.Movies = append([]models.MovieRule(nil), cfg.Movies...)
+	cp.Profiles = append([]models.QualityProfile(nil), cfg.Profiles...)
+	cp.CustomFormats = append([]models.CustomFormat(nil), cfg.CustomFormats...)
+	cp.ArchivedShows = append([]models.ArchivedShow(nil), cfg.ArchivedShows...)
+	cp.ArchivedMovies = append([]models.ArchivedMovie(nil), cfg.ArchivedMovies...)
+	return &cp
+}
+
+// add appends rule and indexes its name and aliases. For movies a trailing
+// year in a name is a qualifier that must agree with the release year.
+func (ix *ruleIndex) add(rule compiledRule, aliases []string, movie bool) {
+	idx := len(ix.rules)
+	ix.rules = append(ix.rules, rule)
+	if rule.regex != "" || rule.glob != "" {
+		ix.patterns = append(ix.patterns, idx)
+	}
+
+	names := append([]string{rule.name}, aliases...)
+	for order, name := range names {
+		k := nameKey{rule: idx, order: order}
+		if order > 0 {
+			k.via = "alias: " + name
+		}
+		if movie {
+			name, k.year = MovieRuleNameParts(name)
+		}
+		norm := NormalizeTitle(name)
+		if norm == "" {
+			continue
+		}
+		ix.names[norm] = append(ix.names[norm], k)
+		if base, ok := stripTrailingYear(norm); ok {
+			k.stripped = true
+			ix.names[base] = append(ix.names[base], k)
+		}
+	}
+}
+
+// find returns the first rule, in watchlist order, that claims item — the
+// same rule FindShowRule/FindMovieRule would return — and the reason
+// fragment naming what fired.
+func (ix *ruleIndex) find(item models.FeedItem) (*compiledRule, string) {
+	best, bestOrder, bestVia := -1, 0, ""
+	consider := func(k nameKey) {
+		if k.year != 0 && item.ReleaseYear != 0 && k.year != item.ReleaseYear {
+			return
+		}
+		if best == -1 || k.rule < best || (k.rule == best && k.order < bestOrder) {
+			best, bestOrder, bestVia = k.rule, k.order, k.via
+		}
+	}
+
+	lookup := func(norm string) {
+		// Exact spelling, or the rule's name minus its year.
+		for _, k := range ix.names[norm] {
+			consider(k)
+		}
+		// The item's name minus its year against an exact rule name.
+		if base, ok := stripTrailingYear(norm); ok {
+			for _, k := range ix.names[base] {
+				if !k.stripped {
+					consider(k)
+				}
+			}
+		}
+	}
+	norm := NormalizeTitle(item.ShowName)
+	if norm != "" {
+		lookup(norm)
+	}
+
+	// A name or alias beats a title pattern on the same rule, so only
+	// pattern rules strictly before the best name hit can win.
+	for _, idx := range ix.patterns {
+		if best != -1 && idx >= best {
+			break
+		}
+		r := &ix.rules[idx]
+		if via, ok := matchRulePatterns(item.Title, r.regex, r.glob); ok {
+			return r, via
+		}
+	}
+	if best == -1 && ix.countryFallback {
+		if base, ok := stripTrailingCountry(norm); ok {
+			lookup(base)
+		}
+	}
+	if best == -1 {
+		return nil, ""
+	}
+	return &ix.rules[best], bestVia
+}
@@ -0,0 +1,77 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// CheckUpgrade decides whether cand should be staged as an upgrade of the
+// queued torrent for the same episode under the effective rule settings eff.
+// cand must be strictly better: a higher quality tier, then a preferred HDR
+// format the queued release lacks, then a higher revision, compared in that
+// order. A known quality outranks an unparsed one, so any recognised tier
+// upgrades a queued release of unknown quality while a candidate of unknown
+// quality is never a quality upgrade. Once the queued release meets
+// UpgradeCutoff only revision upgrades (PROPER/REPACK of the same tier) are
+// taken. It returns a reason fragment for the match reason, or ok=false with
+// why the upgrade was declined.
+func CheckUpgrade(queued models.StagedTorrent, cand models.FeedItem, eff models.DefaultRules, now time.Time) (reason string, ok bool) {
+	if !eff.AllowsUpgrades() {
+		return "upgrades disabled", false
+	}
+	if eff.UpgradeWindowDays > 0 {
+		window := time.Duration(eff.UpgradeWindowDays) * 24 * time.Hour
+		if now.Sub(queued.StagedAt) > window {
+			return fmt.Sprintf("upgrade window of %dd has passed", eff.UpgradeWindowDays), false
+		}
+	}
+
+	old := queued.FeedItem
+	oldTier, newTier := qualityTier(old.Quality), qualityTier(cand.Quality)
+	oldHDR := isPreferredHDR(old.HDR, eff.PreferredHDR)
+	newHDR := isPreferredHDR(cand.HDR, eff.PreferredHDR)
+	cutoffMet := eff.UpgradeCutoff != "" && MeetsCutoff(old.Quality, eff.UpgradeCutoff)
+
+	switch {
+	case newTier != oldTier:
+		if newTier < 0 {
+			return "unknown quality", false
+		}
+		if newTier < oldTier {
+			return "lower quality", false
+		}
+		if cutoffMet {
+			return fmt.Sprintf("queued %s meets cutoff %s", old.Quality, eff.UpgradeCutoff), false
+		}
+		return fmt.Sprintf("quality %s → %s", orUnknown(old.Quality), cand.Quality), true
+	case newHDR != oldHDR:
+		if !newHDR {
+			return "loses preferred HDR", false
+		}
+		if cutoffMet {
+			return fmt.Sprintf("queued %s meets cutoff %s", old.Quality, eff.UpgradeCutoff), false
+		}
+		return "preferred HDR: " + strings.Join(matchedHDR(cand.HDR, eff.PreferredHDR), ", "), true
+	case cand.Revision > old.Revision:
+		return fmt.Sprintf("revision %d → %d", old.Revision, cand.Revision), true
+	}
+	return "not better than queued release", false
+}
+
+// qualityTier returns the rank of quality, or -1 when it is empty or not a
+// tier the parser recognises.
+func qualityTier(quality string) int {
+	if rank, ok := qualityRank[strings.ToUpper(quality)]; ok {
+		return rank
+	}
+	return -1
+}
+
+// MeetsCutoff reports whether quality is at or above cutoff. An empty cutoff
+// is always met.
+func MeetsCutoff(quality, cutoff string) bool {
+	return meetsQuality(quality, cutoff)
+}
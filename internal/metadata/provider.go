@@ -45,3 +45,22 @@ func NewMetadataProvider() MetadataProvider {
 		return newTVMazeProvider(host)
 	}
 }
+
+// maxAlternateTitles bounds ShowMetadata.AlternateTitles; popular titles can
+// have dozens of regional names.
+const maxAlternateTitles = 20
+
+// appendTitle adds title to titles unless it is empty, equal to the primary
+// name or already present (case-insensitive), or the list is full.
+func appendTitle(titles []string, title, primary string) []string {
+	title = strings.TrimSpace(title)
+	if title == "" || strings.EqualFold(title, primary) || len(titles) >= maxAlternateTitles {
+		return titles
+	}
+	for _, t := range titles {
+		if strings.EqualFold(t, title) {
+			return titles
+		}
+	}
+	return append(titles, title)
+}
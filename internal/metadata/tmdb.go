@@ -96,7 +96,7 @@ type tmdbGenre struct {
 }
 
 func (p *tmdbProvider) fetchTVDetail(ctx context.Context, id int) (*ShowMetadata, error) {
-	detailURL := fmt.Sprintf("%s/3/tv/%d?language=en-US&append_to_response=credits,external_ids", p.host, id)
+	detailURL := fmt.Sprintf("%s/3/tv/%d?language=en-US&append_to_response=credits,external_ids,alternative_titles", p.host, id)
 
 	var detail struct {
 		ID           int         `json:"id"`
@@ -113,6 +113,10 @@ func (p *tmdbProvider) fetchTVDetail(ctx context.Context, id int) (*ShowMetadata
 		CreatedBy []struct {
 			Name string `json:"name"`
 		} `json:"created_by"`
+		LastEpisodeToAir *struct {
+			SeasonNumber  int `json:"season_number"`
+			EpisodeNumber int `json:"episode_number"`
+		} `json:"last_episode_to_air"`
 		Credits *struct {
 			Cast []struct {
 				Name  string `json:"name"`
@@ -122,6 +126,11 @@ func (p *tmdbProvider) fetchTVDetail(ctx context.Context, id int) (*ShowMetadata
 		ExternalIDs *struct {
 			IMDbID string `json:"imdb_id"`
 		} `json:"external_ids"`
+		AlternativeTitles *struct {
+			Results []struct {
+				Title string `json:"title"`
+			} `json:"results"`
+		} `json:"alternative_titles"`
 	}
 	if err := p.get(ctx, detailURL, &detail); err != nil {
 		return nil, fmt.Errorf("tmdb: tv detail %d: %w", id, err)
@@ -143,6 +152,15 @@ func (p *tmdbProvider) fetchTVDetail(ctx context.Context, id int) (*ShowMetadata
 	if detail.ExternalIDs != nil {
 		meta.IMDbID = detail.ExternalIDs.IMDbID
 	}
+	if detail.LastEpisodeToAir != nil {
+		meta.LastSeason = detail.LastEpisodeToAir.SeasonNumber
+		meta.LastEpisode = detail.LastEpisodeToAir.EpisodeNumber
+	}
+	if detail.AlternativeTitles != nil {
+		for _, t := range detail.AlternativeTitles.Results {
+			meta.AlternateTitles = appendTitle(meta.AlternateTitles, t.Title, detail.Name)
+		}
+	}
 	for _, g := range detail.Genres {
 		meta.Genres = append(meta.Genres, g.Name)
 	}
@@ -168,7 +186,7 @@ func (p *tmdbProvider) fetchTVDetail(ctx context.Context, id int) (*ShowMetadata
 }
 
 func (p *tmdbProvider) fetchMovieDetail(ctx context.Context, id int) (*ShowMetadata, error) {
-	detailURL := fmt.Sprintf("%s/3/movie/%d?language=en-US&append_to_response=credits", p.host, id)
+	detailURL := fmt.Sprintf("%s/3/movie/%d?language=en-US&append_to_response=credits,alternative_titles", p.host, id)
 
 	var detail struct {
 		ID                  int         `json:"id"`
@@ -193,6 +211,11 @@ func (p *tmdbProvider) fetchMovieDetail(ctx context.Context, id int) (*ShowMetad
 				Job  string `json:"job"`
 			} `json:"crew"`
 		} `json:"credits"`
+		AlternativeTitles *struct {
+			Titles []struct {
+				Title string `json:"title"`
+			} `json:"titles"`
+		} `json:"alternative_titles"`
 	}
 	if err := p.get(ctx, detailURL, &detail); err != nil {
 		return nil, fmt.Errorf("tmdb: movie detail %d: %w", id, err)
@@ -220,6 +243,11 @@ func (p *tmdbProvider) fetchMovieDetail(ctx context.Context, id int) (*ShowMetad
 		meta.Network = detail.ProductionCompanies[0].Name
 	}
 	meta.PremiereYear = parseYearFromDate(detail.ReleaseDate)
+	if detail.AlternativeTitles != nil {
+		for _, t := range detail.AlternativeTitles.Titles {
+			meta.AlternateTitles = appendTitle(meta.AlternateTitles, t.Title, detail.Title)
+		}
+	}
 	if detail.Credits != nil {
 		// Directors become "creators" for movies.
 		for _, c := range detail.Credits.Crew {
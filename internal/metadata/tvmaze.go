@@ -52,11 +52,18 @@ type tvmazeShow struct {
 				Name string `json:"name"`
 			} `json:"person"`
 		} `json:"cast"`
+		Akas []struct {
+			Name string `json:"name"`
+		} `json:"akas"`
+		PreviousEpisode *struct {
+			Season int `json:"season"`
+			Number int `json:"number"`
+		} `json:"previousepisode"`
 	} `json:"_embedded"`
 }
 
 func (p *tvmazeProvider) Fetch(ctx context.Context, showName string) (*ShowMetadata, error) {
-	endpoint := fmt.Sprintf("%s/singlesearch/shows?q=%s&embed=cast", p.host, url.QueryEscape(showName))
+	endpoint := fmt.Sprintf("%s/singlesearch/shows?q=%s&embed[]=cast&embed[]=akas&embed[]=previousepisode", p.host, url.QueryEscape(showName))
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
@@ -126,6 +133,19 @@ func (p *tvmazeProvider) Fetch(ctx context.Context, showName string) (*ShowMetad
 		}
 	}
 
+	// Alternate titles (AKAs) from the embedded akas array.
+	if show.Embedded != nil {
+		for _, aka := range show.Embedded.Akas {
+			meta.AlternateTitles = appendTitle(meta.AlternateTitles, aka.Name, show.Name)
+		}
+	}
+
+	// Most recently aired episode — the finale for an ended show.
+	if show.Embedded != nil && show.Embedded.PreviousEpisode != nil {
+		meta.LastSeason = show.Embedded.PreviousEpisode.Season
+		meta.LastEpisode = show.Embedded.PreviousEpisode.Number
+	}
+
 	// Fetch creators via secondary crew call (non-fatal).
 	meta.Creators = p.fetchCreators(ctx, show.ID)
 
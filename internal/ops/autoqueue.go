@@ -29,6 +29,10 @@ type AutoQueueConfig struct {
 	// the hold is force-released and the best available candidate is queued
 	// immediately. 0 disables the cap.
 	MaxHoldMins int
+	// Upgrades when true lets the job queue upgrade candidates (UpgradeOf !=
+	// 0) staged for already-queued episodes; otherwise they are left for
+	// review. A queued upgrade is linked from the torrent it supersedes.
+	Upgrades bool
 	// DryRun when true runs selection logic without writing to the store or
 	// qBittorrent. All decisions are still recorded in the summary.
 	DryRun bool
@@ -52,8 +56,11 @@ type AutoQueueDecision struct {
 	ScoreBreakdown string                `json:"score_breakdown"`
 	SkipReason     string                `json:"skip_reason,omitempty"`
 	Skipped        bool                  `json:"skipped"`
-	DryRun         bool                  `json:"dry_run"`
-	Err            string                `json:"error,omitempty"`
+	// SavePath is where the winner is (or, in dry-run, would be) saved when
+	// its rule routes downloads; empty means the client default.
+	SavePath string `json:"save_path,omitempty"`
+	DryRun   bool   `json:"dry_run"`
+	Err      string `json:"error,omitempty"`
 }
 
 // AutoQueueSummary is the result returned by RunAutoQueue.
@@ -65,20 +72,53 @@ type AutoQueueSummary struct {
 	Selections []AutoQueueDecision `json:"selections"` // per-group decision log
 }
 
-// episodeKey groups candidates by (show_name_lower, season, episode).
+// episodeKey groups candidates that are variants of the same release unit:
+// a single episode, a multi-episode range, a full-season pack or, for daily
+// shows, an air date. The kinds never collide — a S01 pack is not grouped
+// with S01E01, and S01E01-E02 is not grouped with S01E01.
 type episodeKey struct {
-	show    string
-	season  int
-	episode int
+	show       string
+	season     int
+	episode    int
+	episodeEnd int    // last episode of a multi-episode release; 0 otherwise
+	seasonPack bool   // full-season pack
+	airDate    string // YYYY-MM-DD for date-based shows
 }
 
-// qualityRankAQ maps quality tier strings to integer ranks for scoring.
-var qualityRankAQ = map[string]int{
-	"480P":  0,
-	"720P":  2,
-	"1080P": 5,
-	"2160P": 8,
-	"4K":    8,
+// episodeKeyFor derives the grouping key for fi. It returns false when the
+// title carried no season, episode or air date to group on.
+func episodeKeyFor(fi models.FeedItem) (episodeKey, bool) {
+	k := episodeKey{show: strings.ToLower(strings.TrimSpace(fi.ShowName))}
+	switch {
+	case fi.AirDate != "":
+		k.airDate = fi.AirDate
+	case fi.IsSeasonPack:
+		k.season = fi.Season
+		k.seasonPack = true
+	case fi.Season > 0 || fi.Episode > 0:
+		k.season = fi.Season
+		k.episode = fi.Episode
+		if fi.EpisodeEnd > fi.Episode {
+			k.episodeEnd = fi.EpisodeEnd
+		}
+	default:
+		return episodeKey{}, false
+	}
+	return k, true
+}
+
+// episodeLabel renders the release unit of fi for decision logs:
+// "S01E02", "S01E01-E03", "S02" for a season pack, or the air date.
+func episodeLabel(fi models.FeedItem) string {
+	switch {
+	case fi.AirDate != "":
+		return fi.AirDate
+	case fi.IsSeasonPack:
+		return fmt.Sprintf("S%02d", fi.Season)
+	case fi.EpisodeEnd > fi.Episode:
+		return fmt.Sprintf("S%02dE%02d-E%02d", fi.Season, fi.Episode, fi.EpisodeEnd)
+	}
+	return fmt.Sprintf("S%02dE%02d", fi.Season, fi.Episode)
 }
 
 // fileSizeBaselines maps quality tier to expected bytes (approximate medians).
@@ -97,18 +137,16 @@ var fileSizeBaselines = map[string]int64{
 //
 //	AIScore × 60          = 0–60
 //	GroupReputation × 20  = 0–20
-//	QualityTier           = 0–8  (480P=0, 720P=2, 1080P=5, 2160P/4K=8)
-//	CodecPreference       = 0–4  (preferred=4, present/no-pref=2, other=0)
-//	HDRPreference         = 0–4  (any preferred tag matched=4, no preference=0 neutral)
+//	ReleaseScore / 4      = 0–20 (quality tier, codec/HDR/group/attribute preferences;
+//	                        custom format weights can push it either way)
 //	FileSizeSignal        = 0–2  (within ±50% baseline=2, otherwise 0)
 //	RecencyBonus          = 0–2  (decay from 24h, zero at 30h+)
-//	RARPenalty            = -3   (.rar / RAR in title)
+//	RARPenalty            = -3   (RAR volumes in the inspected file list; .rar / RAR in title when uninspected)
+//	SwarmHealth           = -4–2 (indexer-reported seeders: 0=-4, 1–9=1, 10+=2; unreported=0)
+//	Freeleech             = 0–1  (indexer downloadvolumefactor == 0)
 func candidateScore(
 	t models.StagedTorrent,
 	groupStats map[string]float64,
-	showRule *models.ShowRule,
-	movieRule *models.MovieRule,
-	defaultRules models.DefaultRules,
 	now time.Time,
 ) (score float64, breakdown string) {
 	var parts []string
@@ -126,44 +164,12 @@ func candidateScore(
 	score += grpPts
 	parts = append(parts, fmt.Sprintf("grp=%.1f", grpPts))
 
-	// — Quality tier (0–8) —
-	qualPts := float64(qualityRankAQ[strings.ToUpper(t.FeedItem.Quality)])
-	score += qualPts
-	parts = append(parts, fmt.Sprintf("qual=%.0f", qualPts))
-
-	// — Codec preference (0–4) —
-	preferredCodec := defaultRules.PreferredCodec
-	if showRule != nil && showRule.PreferredCodec != "" {
-		preferredCodec = showRule.PreferredCodec
-	} else if movieRule != nil && movieRule.PreferredCodec != "" {
-		preferredCodec = movieRule.PreferredCodec
-	}
-	codecPts := 0.0
-	if t.FeedItem.Codec != "" {
-		if preferredCodec != "" && strings.EqualFold(t.FeedItem.Codec, preferredCodec) {
-			codecPts = 4
-		} else if preferredCodec == "" {
-			codecPts = 2 // codec present, no preference set
-		}
-	}
-	score += codecPts
-	parts = append(parts, fmt.Sprintf("codec=%.0f", codecPts))
-
-	// — HDR preference (0–4) —
-	var preferredHDR []string
-	if showRule != nil && len(showRule.PreferredHDR) > 0 {
-		preferredHDR = showRule.PreferredHDR
-	} else if movieRule != nil && len(movieRule.PreferredHDR) > 0 {
-		preferredHDR = movieRule.PreferredHDR
-	} else {
-		preferredHDR = defaultRules.PreferredHDR
-	}
-	hdrPts := 0.0
-	if len(preferredHDR) > 0 && isPreferredHDRAQ(t.FeedItem.HDR, preferredHDR) {
-		hdrPts = 4
-	}
-	score += hdrPts
-	parts = append(parts, fmt.Sprintf("hdr=%.0f", hdrPts))
+	// — Release score (0–20 for built-in preferences): the matcher's
+	// persisted score, so custom formats rank candidates here exactly as
+	// they do in dedup —
+	releasePts := float64(t.ReleaseScore) / 4
+	score += releasePts
+	parts = append(parts, fmt.Sprintf("release=%.1f", releasePts))
 
 	// — File size signal (0–2) —
 	sizePts := 0.0
@@ -190,27 +196,49 @@ func candidateScore(
 	score += recencyPts
 	parts = append(parts, fmt.Sprintf("recency=%.1f", recencyPts))
 
-	// — RAR penalty (-3) —
-	titleLower := strings.ToLower(t.FeedItem.Title)
-	if strings.Contains(titleLower, ".rar") || strings.Contains(titleLower, " rar") {
+	// — RAR penalty (-3): from the inspected file list when available,
+	// otherwise a title heuristic —
+	isRAR := t.Torrent.HasFlag(models.TorrentFlagRAR)
+	if t.Torrent == nil {
+		titleLower := strings.ToLower(t.FeedItem.Title)
+		isRAR = strings.Contains(titleLower, ".rar") || strings.Contains(titleLower, " rar")
+	}
+	if isRAR {
 		score -= 3
 		parts = append(parts, "rar=-3")
 	}
 
+	// — Swarm health (-4–2): only when the indexer reported seeders —
+	if t.FeedItem.Seeders != nil {
+		seedPts := 0.0
+		switch n := *t.FeedItem.Seeders; {
+		case n <= 0:
+			seedPts = -4
+		case n < 10:
+			seedPts = 1
+		default:
+			seedPts = 2
+		}
+		score += seedPts
+		parts = append(parts, fmt.Sprintf("seeders=%.0f", seedPts))
+	}
+
+	// — Freeleech bonus (0–1) —
+	if t.FeedItem.IsFreeleech() {
+		score++
+		parts = append(parts, "freeleech=1")
+	}
+
 	return score, strings.Join(parts, " ")
 }
 
-// isPreferredHDRAQ is a local copy of the matcher's isPreferredHDR function to
-// avoid an import cycle between ops and matcher.
-func isPreferredHDRAQ(itemHDR []string, preferred []string) bool {
-	for _, h := range itemHDR {
-		for _, p := range preferred {
-			if strings.EqualFold(h, p) {
-				return true
-			}
-		}
-	}
-	return false
+// suspiciousContents reports whether .torrent inspection flagged t as
+// carrying executables, Windows shortcuts or only a sample — signs of a fake
+// or malicious release that auto-queue must never pick.
+func suspiciousContents(t models.StagedTorrent) bool {
+	return t.Torrent.HasFlag(models.TorrentFlagExecutable) ||
+		t.Torrent.HasFlag(models.TorrentFlagLNK) ||
+		t.Torrent.HasFlag(models.TorrentFlagSampleOnly)
 }
 
 // autoQueueEnabled reports whether auto-queue is enabled for the matched rule.
@@ -226,34 +254,12 @@ func autoQueueEnabled(showRule *models.ShowRule, movieRule *models.MovieRule, gl
 	return globalEnabled
 }
 
-// lookupRule returns the ShowRule or MovieRule for the given torrent by
-// searching the watchlist. Returns nil for both if no matching rule is found.
-func lookupRule(cfg *models.ShowsConfig, t models.StagedTorrent) (*models.ShowRule, *models.MovieRule) {
-	if cfg == nil {
-		return nil, nil
-	}
-	name := strings.TrimSpace(t.FeedItem.ShowName)
-	if t.FeedItem.ContentType == models.ContentTypeMovie {
-		for i := range cfg.Movies {
-			if strings.EqualFold(cfg.Movies[i].Name, name) {
-				return nil, &cfg.Movies[i]
-			}
-		}
-		return nil, nil
-	}
-	for i := range cfg.Shows {
-		if strings.EqualFold(cfg.Shows[i].Name, name) {
-			return &cfg.Shows[i], nil
-		}
-	}
-	return nil, nil
-}
-
 // RunAutoQueue executes one auto-queue cycle: for each pending episode group
 // that has at least one AI-scored candidate meeting the configured thresholds,
 // it selects the highest composite-scored candidate and queues it to
-// qBittorrent. Items without a season+episode number are skipped (season packs
-// and movies without episode metadata are out of scope for this first pass).
+// qBittorrent. Items the parser could not key by episode, range, season pack or
+// air date are skipped, as are movies and, unless cfg.Upgrades is set,
+// upgrade candidates (UpgradeOf != 0) for episodes that were already queued.
 //
 // Losing candidates within a selected group remain in 'pending' status for
 // human review. Failed additions are marked 'failed' in the store.
@@ -285,26 +291,22 @@ func RunAutoQueue(ctx context.Context, cfg AutoQueueConfig, deps AutoQueueDeps)
 		groupStats = map[string]float64{}
 	}
 
-	// Load watchlist config for per-show AutoQueue and rule lookups.
-	watchlistCfg := deps.Matcher.ShowsConfig()
-
 	now := time.Now()
 
-	// Group by (show_lower, season, episode). Skip items without episode info
-	// and skip movies — their titles often contain audio channel strings like
-	// "5.1" or "7.1" that the feed parser can misread as season/episode numbers.
+	// Group by episodeKey. Skip items without episode, season-pack or air-date
+	// info and skip movies — their titles often contain audio channel strings
+	// like "5.1" or "7.1" that the feed parser can misread as season/episode numbers.
 	groups := make(map[episodeKey][]models.StagedTorrent)
 	for _, t := range pending {
 		if t.FeedItem.ContentType == "movie" {
 			continue // movies handled separately; skip to avoid false S/E parses
 		}
-		if t.FeedItem.Season == 0 && t.FeedItem.Episode == 0 {
-			continue // season pack / unrecognised — skip
+		if t.UpgradeOf != 0 && !cfg.Upgrades {
+			continue // upgrade of an already-queued episode — left for review
 		}
-		k := episodeKey{
-			show:    strings.ToLower(strings.TrimSpace(t.FeedItem.ShowName)),
-			season:  t.FeedItem.Season,
-			episode: t.FeedItem.Episode,
+		k, ok := episodeKeyFor(t.FeedItem)
+		if !ok {
+			continue // unrecognised — skip
 		}
 		groups[k] = append(groups[k], t)
 	}
@@ -313,16 +315,18 @@ func RunAutoQueue(ctx context.Context, cfg AutoQueueConfig, deps AutoQueueDeps)
 		if ctx.Err() != nil {
 			break
 		}
+		// A PROPER/REPACK replaces the original of the same release outright.
+		candidates = dropSupersededRevisions(candidates)
 		summary.Evaluated++
 
 		// Representative item for display.
 		rep := candidates[0]
-		epLabel := fmt.Sprintf("S%02dE%02d", rep.FeedItem.Season, rep.FeedItem.Episode)
+		epLabel := episodeLabel(rep.FeedItem)
 
 		// Check per-show auto-queue opt-out first — no point holding something
 		// we will never queue. Use candidates[0] for the rule lookup; all
 		// candidates in a group share the same show/movie.
-		showRule, movieRule := lookupRule(watchlistCfg, rep)
+		showRule, movieRule := deps.Matcher.FindRule(rep.FeedItem)
 		if !autoQueueEnabled(showRule, movieRule, true /* caller gates on global enabled */) {
 			summary.Skipped++
 			summary.Selections = append(summary.Selections, AutoQueueDecision{
@@ -379,6 +383,9 @@ func RunAutoQueue(ctx context.Context, cfg AutoQueueConfig, deps AutoQueueDeps)
 			if c.MatchConfidence >= 0 && c.MatchConfidence < cfg.MinConfidence {
 				continue
 			}
+			if suspiciousContents(c) {
+				continue // inspected payload looks fake or unsafe — human review only
+			}
 			eligible = append(eligible, c)
 		}
 		if len(eligible) == 0 {
@@ -394,15 +401,11 @@ func RunAutoQueue(ctx context.Context, cfg AutoQueueConfig, deps AutoQueueDeps)
 		}
 
 		// Score and pick winner.
-		defaultRules := models.DefaultRules{}
-		if watchlistCfg != nil {
-			defaultRules = watchlistCfg.Defaults
-		}
 		bestScore := -math.MaxFloat64
 		var winner models.StagedTorrent
 		var bestBreakdown string
 		for _, c := range eligible {
-			s, breakdown := candidateScore(c, groupStats, showRule, movieRule, defaultRules, now)
+			s, breakdown := candidateScore(c, groupStats, now)
 			if s > bestScore {
 				bestScore = s
 				winner = c
@@ -410,6 +413,13 @@ func RunAutoQueue(ctx context.Context, cfg AutoQueueConfig, deps AutoQueueDeps)
 			}
 		}
 
+		// Per-rule category, tags and save path.
+		addOpts, err := DownloadOptions(deps.Matcher, winner.FeedItem)
+		if err != nil {
+			log.Warn("auto_queue: save path template failed; using client defaults",
+				zap.String("title", winner.FeedItem.Title), zap.Error(err))
+		}
+
 		decision := AutoQueueDecision{
 			ShowName:       rep.FeedItem.ShowName,
 			Episode:        epLabel,
@@ -417,6 +427,7 @@ func RunAutoQueue(ctx context.Context, cfg AutoQueueConfig, deps AutoQueueDeps)
 			Score:          bestScore,
 			ScoreBreakdown: bestBreakdown,
 			DryRun:         cfg.DryRun,
+			SavePath:       addOpts["savepath"],
 		}
 
 		if cfg.DryRun {
@@ -450,7 +461,7 @@ func RunAutoQueue(ctx context.Context, cfg AutoQueueConfig, deps AutoQueueDeps)
 			continue
 		}
 
-		if err := deps.QB.AddTorrent(winner.FeedItem.Link, nil); err != nil {
+		if err := deps.QB.AddTorrentWithAuth(winner.FeedItem.Link, addOpts, FeedAuthFor(deps.Store, winner.FeedItem.FeedURL)); err != nil {
 			log.Error("auto_queue: AddTorrent failed",
 				zap.String("title", winner.FeedItem.Title), zap.Error(err))
 			_ = deps.Store.SetFailed(winner.ID, err.Error())
@@ -466,6 +477,12 @@ func RunAutoQueue(ctx context.Context, cfg AutoQueueConfig, deps AutoQueueDeps)
 				zap.Int("id", winner.ID), zap.Error(err))
 		}
 		_ = deps.Store.LogActivity(winner.ID, winner.FeedItem.Title, "auto_queue", winner.MatchReason)
+		if winner.UpgradeOf != 0 {
+			if err := deps.Store.SetSupersededBy(winner.UpgradeOf, winner.ID); err != nil {
+				log.Warn("auto_queue: could not link superseded torrent",
+					zap.Int("id", winner.UpgradeOf), zap.Int("upgrade", winner.ID), zap.Error(err))
+			}
+		}
 
 		summary.Queued++
 		summary.Selections = append(summary.Selections, decision)
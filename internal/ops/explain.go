@@ -0,0 +1,19 @@
+package ops
+
+import (
+	"github.com/killakam3084/rss-curator/internal/feed"
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// ExplainTitle parses title as a feed item of the given content type (a show
+// when empty) and traces it through every watchlist rule, the same way a
+// feed check would see it.
+func ExplainTitle(m *matcher.Matcher, title string, contentType models.ContentType) matcher.Explanation {
+	if contentType == "" {
+		contentType = models.ContentTypeShow
+	}
+	item := models.FeedItem{Title: title, ContentType: contentType}
+	feed.ParseTitleMetadata(&item)
+	return m.Explain(item)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -19,13 +20,25 @@ import (
 
 // FeedCheckConfig holds the feed-specific parameters for a check run.
 type FeedCheckConfig struct {
-	Feeds   []models.FeedConfig
+	Feeds []models.FeedConfig
+	// LoadFeeds, when non-nil, is called at the start of every run and its
+	// result replaces Feeds (see RegistryFeeds). On error the static Feeds
+	// slice is used.
+	LoadFeeds func() ([]models.FeedConfig, error)
+	// Sources are polled every run alongside Feeds (e.g. a watch directory);
+	// their items take the same match → score → stage path.
+	Sources []feed.FeedSource
 	Matcher *matcher.Matcher
 	RawTTL  time.Duration // TTL for raw feed items; defaults to 24h when zero.
 	// JobID, when non-zero, indicates the caller has already created the job
 	// record and emitted the initial "running" SSE event. RunFeedCheck will
 	// use this ID rather than allocating a new one.
 	JobID int
+	// Replay marks a run over captured responses (curator replay). Persisted
+	// feed state is neither consulted — no backoff, poll-interval or
+	// conditional-request skips — nor updated, so replaying into a live
+	// database leaves its feed health and validators untouched.
+	Replay bool
 }
 
 // FeedCheckDeps holds the shared service dependencies for RunFeedCheck.
@@ -36,16 +49,24 @@ type FeedCheckDeps struct {
 	ScorerProv ai.Provider       // may be nil
 	LogBuffer  *logbuffer.Buffer // may be nil
 	Logger     *zap.Logger       // may be nil; falls back to nop
+	// Parser fetches and parses each feed. When nil a fresh feed.NewParser()
+	// is used; set it to a capturing or replaying parser (see
+	// feed.Parser.WithCapture and feed.NewReplayParser).
+	Parser *feed.Parser
 	// BackfillEnabled is called each run to check whether the rescore-backfill
 	// step should execute. When nil, backfill is enabled (preserves old behaviour).
 	BackfillEnabled func() bool
+	// InspectTorrents is called each run to decide whether matched items have
+	// their .torrent downloaded and decoded before staging. When nil,
+	// inspection is disabled.
+	InspectTorrents func() bool
 	// AutoQueueEnabled, when non-nil and returning true, causes RunFeedCheck to
 	// trigger a RunAutoQueueJob after staging completes. The fn receives an
 	// AutoQueueConfig; when nil, no auto-queue step is triggered.
 	AutoQueueEnabled func() (bool, AutoQueueConfig, AutoQueueDeps)
 }
 
-// RunFeedCheck executes a full feed-check cycle: parse all feeds, match items,
+// RunFeedCheck executes a full feed-check cycle: fetch every feed source, match items,
 // score with AI when available, stage new torrents, and backfill scores for
 // previously-unscored items. Job lifecycle and SSE fan-out are handled
 // internally.
@@ -79,7 +100,18 @@ func RunFeedCheck(ctx context.Context, cfg FeedCheckConfig, deps FeedCheckDeps)
 		}
 	}
 
-	parser := feed.NewParser()
+	if cfg.LoadFeeds != nil {
+		if feeds, err := cfg.LoadFeeds(); err != nil {
+			log.Warn("could not load feed registry; using static feeds", zap.Error(err))
+		} else {
+			cfg.Feeds = feeds
+		}
+	}
+
+	parser := deps.Parser
+	if parser == nil {
+		parser = feed.NewParser()
+	}
 	// NOTE: enricher is NOT wired into the parser here — enrichment is applied
 	// post-match on the small deduplicated result set rather than on every raw
 	// feed item (which would issue O(total_items) LLM calls regardless of match).
@@ -88,78 +120,203 @@ func RunFeedCheck(ctx context.Context, cfg FeedCheckConfig, deps FeedCheckDeps)
 		totalFound   int
 		totalMatched int
 		totalScored  int
-		feedFailed   bool
+		notModified  int
+		feedsFailed  int
+		backedOff    int
+		skipped      int
+		feedResults  []models.FeedCheckResult
 		allMatches   []models.StagedTorrent
 	)
 	now := time.Now()
 
-	// Fetch and match all feeds in parallel; expensive network I/O and RSS
+	// Every configured feed URL becomes an RSSSource; extra sources follow.
+	// Poll intervals only apply to registry feeds.
+	type polledSource struct {
+		src              feed.FeedSource
+		pollIntervalMins int
+	}
+	sources := make([]polledSource, 0, len(cfg.Feeds)+len(cfg.Sources))
+	for _, fc := range cfg.Feeds {
+		sources = append(sources, polledSource{src: feed.NewRSSSource(parser, fc), pollIntervalMins: fc.PollIntervalMins})
+	}
+	for _, src := range cfg.Sources {
+		sources = append(sources, polledSource{src: src})
+	}
+
+	// Fetch and match all sources in parallel; expensive network I/O and RSS
 	// parsing run concurrently while SQLite writes remain serial below.
 	type feedResult struct {
-		rawItems []models.RawFeedItem
-		matches  []models.StagedTorrent
-		failed   bool
+		result       models.FeedCheckResult
+		rawItems     []models.RawFeedItem
+		matches      []models.StagedTorrent
+		validators   feed.Validators
+		latency      time.Duration
+		prevFailures int
 	}
 	var (
-		feedResultsMu sync.Mutex
-		feedResults   []feedResult
-		feedWg        sync.WaitGroup
+		fetchedMu sync.Mutex
+		fetched   []feedResult
+		feedWg    sync.WaitGroup
 	)
-	for _, fc := range cfg.Feeds {
+	for _, ps := range sources {
 		if ctx.Err() != nil {
 			break
 		}
-		fc := fc
+		ps := ps
+		id := ps.src.ID()
 		feedWg.Add(1)
 		go func() {
 			defer feedWg.Done()
-			res := feedResult{}
-			log.Info("fetching feed", zap.String("url", fc.URL), zap.String("type", string(fc.ContentType)))
-			items, err := parser.Parse(fc.URL, fc.ContentType)
+			res := feedResult{result: models.FeedCheckResult{URL: id}}
+			defer func() {
+				fetchedMu.Lock()
+				fetched = append(fetched, res)
+				fetchedMu.Unlock()
+			}()
+
+			var prev feed.Validators
+			// A replay serves its captured responses whatever the live state.
+			if !cfg.Replay {
+				if st, err := deps.Store.GetFeedState(id); err != nil {
+					log.Warn("could not load feed state", zap.String("url", id), zap.Error(err))
+				} else if st != nil {
+					if st.NextAttemptAt != nil && now.Before(*st.NextAttemptAt) {
+						log.Info("feed in backoff, skipping",
+							zap.String("url", id),
+							zap.Int("consecutive_failures", st.ConsecutiveFailures),
+							zap.Time("next_attempt_at", *st.NextAttemptAt),
+						)
+						res.result.Status = "backoff"
+						return
+					}
+					// Poll intervals run from the last successful fetch; failures
+					// are governed by the backoff above.
+					if ps.pollIntervalMins > 0 && st.LastSuccessAt != nil &&
+						now.Sub(*st.LastSuccessAt) < time.Duration(ps.pollIntervalMins)*time.Minute {
+						log.Debug("feed not due yet, skipping",
+							zap.String("url", id),
+							zap.Int("poll_interval_mins", ps.pollIntervalMins),
+						)
+						res.result.Status = "not_due"
+						return
+					}
+					prev = feed.Validators{ETag: st.ETag, LastModified: st.LastModified}
+					res.prevFailures = st.ConsecutiveFailures
+				}
+			}
+
+			log.Info("fetching feed", zap.String("url", id))
+			fetchStart := time.Now()
+			out, err := ps.src.Fetch(ctx, prev)
+			res.latency = time.Since(fetchStart)
+			res.result.LatencyMs = res.latency.Milliseconds()
 			if err != nil {
-				log.Error("failed to parse feed", zap.String("url", fc.URL), zap.Error(err))
-				res.failed = true
-				feedResultsMu.Lock()
-				feedResults = append(feedResults, res)
-				feedResultsMu.Unlock()
+				log.Error("failed to parse feed", zap.String("url", id), zap.Error(err))
+				res.result.Status = "failed"
+				res.result.Error = err.Error()
+				return
+			}
+			if out.NotModified {
+				log.Info("feed not modified", zap.String("url", id))
+				res.result.Status = "not_modified"
 				return
 			}
-			for _, item := range items {
+			res.result.Status = "ok"
+			res.validators = out.Validators
+			for _, item := range out.Items {
 				res.rawItems = append(res.rawItems, models.RawFeedItem{
 					FeedItem:  item,
 					PulledAt:  now,
 					ExpiresAt: now.Add(cfg.RawTTL),
 				})
 			}
-			res.matches = cfg.Matcher.MatchAll(items)
-			log.Info("matched items", zap.String("url", fc.URL), zap.Int("count", len(res.matches)))
-			feedResultsMu.Lock()
-			feedResults = append(feedResults, res)
-			feedResultsMu.Unlock()
+			res.matches = cfg.Matcher.MatchAll(out.Items)
+			res.result.ItemsFound = len(out.Items)
+			res.result.ItemsMatched = len(res.matches)
+			log.Info("matched items", zap.String("url", id), zap.Int("count", len(res.matches)))
 		}()
 	}
 	feedWg.Wait()
 
-	// Collect feed results serially: write raw items to storage and merge matches.
-	for _, res := range feedResults {
-		if res.failed {
-			feedFailed = true
-			continue
-		}
-		for _, raw := range res.rawItems {
-			if err := deps.Store.AddRawFeedItem(raw); err != nil {
-				log.Warn("failed to store raw feed item", zap.Error(err))
-			} else {
-				totalFound++
+	// Collect feed results serially: record feed health, write raw items to
+	// storage and merge matches.
+	for _, res := range fetched {
+		url := res.result.URL
+		switch res.result.Status {
+		case "not_due":
+			skipped++
+		case "backoff":
+			backedOff++
+		case "failed":
+			feedsFailed++
+			if cfg.Replay {
+				break
+			}
+			next := time.Now().Add(feedBackoff(res.prevFailures + 1))
+			if err := deps.Store.RecordFeedFailure(url, res.result.Error, res.latency, next); err != nil {
+				log.Warn("failed to record feed failure", zap.String("url", url), zap.Error(err))
+			}
+		case "not_modified":
+			notModified++
+			if cfg.Replay {
+				break
+			}
+			if err := deps.Store.RecordFeedSuccess(url, -1, res.latency); err != nil {
+				log.Warn("failed to record feed success", zap.String("url", url), zap.Error(err))
+			}
+		case "ok":
+			if !cfg.Replay {
+				if err := deps.Store.SetFeedValidators(url, res.validators.ETag, res.validators.LastModified); err != nil {
+					log.Warn("failed to store feed validators", zap.String("url", url), zap.Error(err))
+				}
+				if err := deps.Store.RecordFeedSuccess(url, res.result.ItemsFound, res.latency); err != nil {
+					log.Warn("failed to record feed success", zap.String("url", url), zap.Error(err))
+				}
 			}
+			for _, raw := range res.rawItems {
+				if err := deps.Store.AddRawFeedItem(raw); err != nil {
+					log.Warn("failed to store raw feed item", zap.Error(err))
+				} else {
+					totalFound++
+				}
+			}
+			allMatches = append(allMatches, res.matches...)
 		}
-		allMatches = append(allMatches, res.matches...)
+		feedResults = append(feedResults, res.result)
 	}
+	sort.Slice(feedResults, func(i, j int) bool { return feedResults[i].URL < feedResults[j].URL })
+
+	// Only fail the run when every feed that was actually fetched failed; a
+	// single dead indexer is reported per-feed without turning the job red.
+	attempted := len(feedResults) - backedOff - skipped
+	allFailed := feedsFailed > 0 && feedsFailed == attempted
 
 	// Deduplicate across all feeds: for the same show+season+episode keep the
 	// single best variant (by quality tier, then codec/group preference).
 	allMatches = deduplicateByEpisode(allMatches)
 
+	// A match for an episode that was already queued is staged as an upgrade
+	// linked to the queued torrent when the rule's upgrade policy takes it,
+	// and dropped otherwise.
+	var upgrades, alreadyQueued int
+	if queued, err := deps.Store.List("queued", "", ""); err != nil {
+		log.Warn("failed to list queued torrents for upgrade check", zap.Error(err))
+	} else {
+		allMatches, upgrades, alreadyQueued = markUpgrades(allMatches, queued, cfg.Matcher.RuleSettings, time.Now())
+		if upgrades > 0 || alreadyQueued > 0 {
+			log.Info("upgrade check complete", zap.Int("upgrades", upgrades), zap.Int("already_queued", alreadyQueued))
+		}
+	}
+
+	// Optional .torrent inspection: record the real size and file list and
+	// flag RAR, executable, shortcut and sample-only payloads before anything
+	// is staged or handed to auto-queue.
+	var inspected, flagged int
+	if deps.InspectTorrents != nil && deps.InspectTorrents() {
+		inspected, flagged = inspectTorrents(ctx, allMatches, deps.Store, log)
+		log.Info("torrent inspection complete", zap.Int("inspected", inspected), zap.Int("flagged", flagged))
+	}
+
 	// Enrich only the deduplicated match set — O(matched) LLM calls instead of
 	// O(total_found). Regex already populated ShowName for matching; enrichment
 	// fills in Codec/Source/ReleaseGroup for staged items only.
@@ -193,9 +350,20 @@ func RunFeedCheck(ctx context.Context, cfg FeedCheckConfig, deps FeedCheckDeps)
 	}
 
 	summary := models.FeedCheckSummary{
-		ItemsFound:   totalFound,
-		ItemsMatched: totalMatched,
-		ItemsScored:  totalScored,
+		ItemsFound:         totalFound,
+		ItemsMatched:       totalMatched,
+		ItemsScored:        totalScored,
+		ItemsInspected:     inspected,
+		ItemsFlagged:       flagged,
+		ItemsUpgrades:      upgrades,
+		ItemsAlreadyQueued: alreadyQueued,
+		FeedsNotModified:   notModified,
+		FeedsFailed:        feedsFailed,
+		FeedsBackedOff:     backedOff,
+		Feeds:              feedResults,
+	}
+	if feedsFailed > 0 {
+		summary.ErrorMessage = fmt.Sprintf("%d of %d feeds failed", feedsFailed, attempted)
 	}
 
 	if jobErr == nil {
@@ -213,9 +381,9 @@ func RunFeedCheck(ctx context.Context, cfg FeedCheckConfig, deps FeedCheckDeps)
 			finalJob.Status = "cancelled"
 			summary.ErrorMessage = "context cancelled"
 			_ = deps.Store.CancelJob(jobID, summary)
-		} else if feedFailed {
+		} else if allFailed {
 			finalJob.Status = "failed"
-			_ = deps.Store.FailJob(jobID, "one or more feeds failed to parse")
+			_ = deps.Store.FailJob(jobID, "all feeds failed to parse")
 		} else {
 			finalJob.Status = "completed"
 			_ = deps.Store.CompleteJob(jobID, summary)
@@ -262,8 +430,8 @@ func RunFeedCheck(ctx context.Context, cfg FeedCheckConfig, deps FeedCheckDeps)
 	}
 
 	var retErr error
-	if feedFailed {
-		retErr = fmt.Errorf("one or more feeds failed to parse")
+	if allFailed {
+		retErr = fmt.Errorf("all feeds failed to parse")
 	}
 
 	// Trigger auto-queue after staging completes when the caller has configured it.
@@ -276,49 +444,143 @@ func RunFeedCheck(ctx context.Context, cfg FeedCheckConfig, deps FeedCheckDeps)
 	return summary, retErr
 }
 
-// deduplicateByEpisode keeps the single best match per (show, season, episode)
-// when multiple variants of the same episode are staged in one feed-check run
-// (common when a broad category feed delivers many codec/quality variants at
-// once). Items without a season+episode are passed through unchanged.
-//
-// "Best" is ranked by: quality tier (2160p > 1080p > 720p) × 4, +2 if the
-// match reason signals a preferred codec, +1 for a preferred release group.
-func deduplicateByEpisode(matches []models.StagedTorrent) []models.StagedTorrent {
-	qualityRank := map[string]int{"720P": 1, "1080P": 2, "2160P": 3, "4K": 3}
+// releaseKey identifies one release of an episode independent of its
+// revision, so a group's REPACK lines up with the original it replaces.
+type releaseKey struct {
+	episode episodeKey
+	group   string
+	quality string
+	source  string
+}
+
+// dropSupersededRevisions removes items for which a higher revision of the
+// same release (same episode, group, quality and source) is also present.
+// Items without an episode key are kept as-is.
+func dropSupersededRevisions(items []models.StagedTorrent) []models.StagedTorrent {
+	keyFor := func(fi models.FeedItem) (releaseKey, bool) {
+		ek, ok := episodeKeyFor(fi)
+		return releaseKey{
+			episode: ek,
+			group:   strings.ToLower(fi.ReleaseGroup),
+			quality: strings.ToUpper(fi.Quality),
+			source:  strings.ToLower(fi.Source),
+		}, ok
+	}
+
+	top := make(map[releaseKey]int)
+	for _, t := range items {
+		if k, ok := keyFor(t.FeedItem); ok && t.FeedItem.Revision > top[k] {
+			top[k] = t.FeedItem.Revision
+		}
+	}
+	result := make([]models.StagedTorrent, 0, len(items))
+	for _, t := range items {
+		if k, ok := keyFor(t.FeedItem); ok && t.FeedItem.Revision < top[k] {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// markUpgrades checks matches against the queued torrents. A match for an
+// episode with a current queued release (one not already superseded) is kept
+// as an upgrade — UpgradeOf set and the reason extended — when
+// matcher.CheckUpgrade accepts it under the rule settings returned by
+// settings, and dropped otherwise. Matches for episodes never queued pass
+// through unchanged. It returns the kept matches and the number of upgrades
+// and dropped matches.
+func markUpgrades(
+	matches, queued []models.StagedTorrent,
+	settings func(models.FeedItem) (models.DefaultRules, bool),
+	now time.Time,
+) (kept []models.StagedTorrent, upgrades, dropped int) {
+	current := make(map[episodeKey]models.StagedTorrent)
+	for _, q := range queued {
+		if q.SupersededBy != 0 {
+			continue
+		}
+		k, ok := episodeKeyFor(q.FeedItem)
+		if !ok {
+			continue
+		}
+		if cur, seen := current[k]; !seen || q.ID > cur.ID {
+			current[k] = q
+		}
+	}
 
-	rank := func(t models.StagedTorrent) int {
-		r := qualityRank[strings.ToUpper(t.FeedItem.Quality)] * 4
-		if strings.Contains(t.MatchReason, "preferred codec") {
-			r += 2
+	kept = make([]models.StagedTorrent, 0, len(matches))
+	for _, m := range matches {
+		k, ok := episodeKeyFor(m.FeedItem)
+		if !ok {
+			kept = append(kept, m)
+			continue
 		}
-		if strings.Contains(t.MatchReason, "preferred group") {
-			r++
+		q, found := current[k]
+		if !found {
+			kept = append(kept, m)
+			continue
+		}
+		eff, _ := settings(m.FeedItem)
+		why, ok := matcher.CheckUpgrade(q, m.FeedItem, eff, now)
+		if !ok {
+			dropped++
+			continue
 		}
-		return r
+		m.UpgradeOf = q.ID
+		m.MatchReason += fmt.Sprintf(", upgrade of #%d (%s)", q.ID, why)
+		kept = append(kept, m)
+		upgrades++
 	}
+	return kept, upgrades, dropped
+}
 
-	type episodeKey struct {
-		show    string
-		season  int
-		episode int
+// Backoff bounds for repeatedly failing feeds. The delay doubles with each
+// consecutive failure starting from feedBackoffBase, capped at feedBackoffMax.
+const (
+	feedBackoffBase = 5 * time.Minute
+	feedBackoffMax  = 6 * time.Hour
+)
+
+// feedBackoff returns how long to wait before fetching a feed again after
+// failures consecutive failures.
+func feedBackoff(failures int) time.Duration {
+	if failures < 1 {
+		return 0
 	}
+	d := feedBackoffBase
+	for i := 1; i < failures; i++ {
+		d *= 2
+		if d >= feedBackoffMax {
+			return feedBackoffMax
+		}
+	}
+	return d
+}
 
+// deduplicateByEpisode keeps the single best match per episodeKey (episode,
+// multi-episode range, season pack or air date) when multiple variants of the
+// same release are staged in one feed-check run (common when a broad category
+// feed delivers many codec/quality variants at once). Items the parser could
+// not key are passed through unchanged.
+//
+// Originals superseded by a PROPER/REPACK of the same release are dropped
+// first. "Best" is then the highest ReleaseScore (see matcher.ScoreRelease);
+// ties go to the higher revision.
+func deduplicateByEpisode(matches []models.StagedTorrent) []models.StagedTorrent {
 	best := make(map[episodeKey]models.StagedTorrent)
 	var unkeyed []models.StagedTorrent
 
-	for _, m := range matches {
-		fi := m.FeedItem
-		if fi.Season == 0 && fi.Episode == 0 {
-			// Season pack or unrecognised pattern — pass through.
+	for _, m := range dropSupersededRevisions(matches) {
+		k, ok := episodeKeyFor(m.FeedItem)
+		if !ok {
+			// Unrecognised pattern — pass through.
 			unkeyed = append(unkeyed, m)
 			continue
 		}
-		k := episodeKey{
-			show:    strings.ToLower(strings.TrimSpace(fi.ShowName)),
-			season:  fi.Season,
-			episode: fi.Episode,
-		}
-		if existing, ok := best[k]; !ok || rank(m) > rank(existing) {
+		existing, ok := best[k]
+		if !ok || m.ReleaseScore > existing.ReleaseScore ||
+			(m.ReleaseScore == existing.ReleaseScore && m.FeedItem.Revision > existing.FeedItem.Revision) {
 			best[k] = m
 		}
 	}
@@ -0,0 +1,234 @@
+package ops
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/killakam3084/rss-curator/internal/feed"
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/internal/storage"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+func TestFeedBackoff(t *testing.T) {
+	cases := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 5 * time.Minute},
+		{2, 10 * time.Minute},
+		{4, 40 * time.Minute},
+		{7, 320 * time.Minute},
+		{8, 6 * time.Hour}, // 640m capped
+		{50, 6 * time.Hour},
+	}
+	for _, tc := range cases {
+		if got := feedBackoff(tc.failures); got != tc.want {
+			t.Errorf("feedBackoff(%d) = %v, want %v", tc.failures, got, tc.want)
+		}
+	}
+}
+
+func TestDeduplicateByEpisode_KeyKinds(t *testing.T) {
+	staged := func(title string, fi models.FeedItem) models.StagedTorrent {
+		fi.Title = title
+		fi.ShowName = "Show"
+		score, _ := matcher.ScoreRelease(fi, matcher.ReleasePreferences{}, nil)
+		return models.StagedTorrent{FeedItem: fi, ReleaseScore: score}
+	}
+	matches := []models.StagedTorrent{
+		staged("ep 720p", models.FeedItem{Season: 1, Episode: 1, Quality: "720p"}),
+		staged("ep 1080p", models.FeedItem{Season: 1, Episode: 1, Quality: "1080p"}),
+		staged("range", models.FeedItem{Season: 1, Episode: 1, EpisodeEnd: 2, Quality: "720p"}),
+		staged("pack 720p", models.FeedItem{Season: 1, IsSeasonPack: true, Quality: "720p"}),
+		staged("pack 2160p", models.FeedItem{Season: 1, IsSeasonPack: true, Quality: "2160p"}),
+		staged("daily a", models.FeedItem{AirDate: "2026-10-15", Quality: "720p"}),
+		staged("daily b", models.FeedItem{AirDate: "2026-10-15", Quality: "1080p"}),
+		staged("daily other", models.FeedItem{AirDate: "2026-10-14", Quality: "720p"}),
+		staged("unparsed", models.FeedItem{}),
+	}
+
+	got := map[string]bool{}
+	for _, m := range deduplicateByEpisode(matches) {
+		got[m.FeedItem.Title] = true
+	}
+	want := []string{"ep 1080p", "range", "pack 2160p", "daily b", "daily other", "unparsed"}
+	if len(got) != len(want) {
+		t.Errorf("got %d items %v, want %v", len(got), got, want)
+	}
+	for _, title := range want {
+		if !got[title] {
+			t.Errorf("missing %q in deduplicated result %v", title, got)
+		}
+	}
+}
+
+func TestDeduplicateByEpisode_PrefersHigherRevision(t *testing.T) {
+	ep := func(title, group string, rev int, reason string) models.StagedTorrent {
+		return models.StagedTorrent{
+			MatchReason: reason,
+			FeedItem: models.FeedItem{
+				Title: title, ShowName: "Show", Season: 1, Episode: 2,
+				Quality: "1080p", Source: "WEB-DL", ReleaseGroup: group, Revision: rev,
+			},
+		}
+	}
+
+	// The PROPER supersedes its own group's original even when that original
+	// carries a preferred-group bonus.
+	got := deduplicateByEpisode([]models.StagedTorrent{
+		ep("original", "GRP", 0, "preferred group: GRP"),
+		ep("proper", "GRP", 1, ""),
+	})
+	if len(got) != 1 || got[0].FeedItem.Title != "proper" {
+		t.Errorf("same release: got %v, want the proper", got)
+	}
+
+	// Across groups, equal rank is broken by revision.
+	got = deduplicateByEpisode([]models.StagedTorrent{
+		ep("original", "AAA", 0, ""),
+		ep("proper", "BBB", 1, ""),
+	})
+	if len(got) != 1 || got[0].FeedItem.Title != "proper" {
+		t.Errorf("tie: got %v, want the proper", got)
+	}
+}
+
+func TestDeduplicateByEpisode_UsesReleaseScore(t *testing.T) {
+	// A custom format can lift a 720p release above a 1080p one; dedup must
+	// follow the score rather than re-deriving its own ranking.
+	ep := func(title, quality string, score int) models.StagedTorrent {
+		return models.StagedTorrent{
+			ReleaseScore: score,
+			FeedItem:     models.FeedItem{Title: title, ShowName: "Show", Season: 2, Episode: 5, Quality: quality},
+		}
+	}
+	got := deduplicateByEpisode([]models.StagedTorrent{
+		ep("1080p", "1080p", 40),
+		ep("720p favoured", "720p", 70),
+	})
+	if len(got) != 1 || got[0].FeedItem.Title != "720p favoured" {
+		t.Errorf("got %v, want the higher-scored 720p release", got)
+	}
+}
+
+func TestMarkUpgrades(t *testing.T) {
+	now := time.Now()
+	queued := []models.StagedTorrent{
+		{ID: 7, StagedAt: now, FeedItem: models.FeedItem{ShowName: "Show", Season: 1, Episode: 2, Quality: "720p"}},
+		{ID: 8, StagedAt: now, FeedItem: models.FeedItem{ShowName: "Show", Season: 1, Episode: 4, Quality: "2160p"}},
+		{ID: 9, StagedAt: now, SupersededBy: 10, FeedItem: models.FeedItem{ShowName: "Show", Season: 1, Episode: 5, Quality: "720p"}},
+		{ID: 10, StagedAt: now, FeedItem: models.FeedItem{ShowName: "Show", Season: 1, Episode: 5, Quality: "1080p"}},
+	}
+	matches := []models.StagedTorrent{
+		{MatchReason: "matches show: Show", FeedItem: models.FeedItem{Title: "better tier", ShowName: "Show", Season: 1, Episode: 2, Quality: "1080p"}},
+		{FeedItem: models.FeedItem{Title: "same tier", ShowName: "Show", Season: 1, Episode: 2, Quality: "720p"}},
+		{FeedItem: models.FeedItem{Title: "never queued", ShowName: "Show", Season: 1, Episode: 3, Quality: "720p"}},
+		{FeedItem: models.FeedItem{Title: "proper", ShowName: "Show", Season: 1, Episode: 4, Quality: "2160p", Revision: 1}},
+		{FeedItem: models.FeedItem{Title: "against current", ShowName: "Show", Season: 1, Episode: 5, Quality: "1080p"}},
+	}
+	settings := func(models.FeedItem) (models.DefaultRules, bool) { return models.DefaultRules{}, true }
+
+	kept, upgrades, dropped := markUpgrades(matches, queued, settings, now)
+	if upgrades != 2 || dropped != 2 {
+		t.Errorf("upgrades=%d dropped=%d, want 2 and 2", upgrades, dropped)
+	}
+	got := map[string]models.StagedTorrent{}
+	for _, m := range kept {
+		got[m.FeedItem.Title] = m
+	}
+	if m := got["better tier"]; m.UpgradeOf != 7 || !strings.Contains(m.MatchReason, "upgrade of #7 (quality 720p → 1080p)") {
+		t.Errorf("better tier: UpgradeOf=%d reason=%q", m.UpgradeOf, m.MatchReason)
+	}
+	if m := got["proper"]; m.UpgradeOf != 8 {
+		t.Errorf("proper of queued episode: UpgradeOf = %d, want 8", m.UpgradeOf)
+	}
+	if m, ok := got["never queued"]; !ok || m.UpgradeOf != 0 {
+		t.Error("a never-queued episode should pass through unchanged")
+	}
+	if _, ok := got["same tier"]; ok {
+		t.Error("a release no better than the queued one was kept")
+	}
+	if _, ok := got["against current"]; ok {
+		t.Error("compared against the superseded release instead of its upgrade")
+	}
+}
+
+func TestRunFeedCheck_ReplayIgnoresFeedState(t *testing.T) {
+	store, err := storage.New(filepath.Join(t.TempDir(), "curator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	const url = "https://tracker.example/rss"
+	next := time.Now().Add(time.Hour)
+	if err := store.RecordFeedFailure(url, "boom", time.Second, next); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetFeedValidators(url, `"live-etag"`, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `<?xml version="1.0"?><rss version="2.0"><channel><title>t</title>
+<item><title>Severance.S02E01.1080p.WEB-DL.x265-NTb</title><link>https://tracker.example/dl/1.torrent</link><guid>1</guid></item>
+</channel></rss>`
+	capture := feed.Capture{URL: url, ContentType: models.ContentTypeShow, ETag: `"captured"`, Body: []byte(body)}
+	m := matcher.NewMatcher(&models.ShowsConfig{Shows: []models.ShowRule{{Name: "Severance"}}}, nil)
+
+	summary, err := RunFeedCheck(context.Background(), FeedCheckConfig{
+		Feeds:   []models.FeedConfig{{URL: url, ContentType: models.ContentTypeShow, PollIntervalMins: 60}},
+		Matcher: m,
+		Replay:  true,
+	}, FeedCheckDeps{Store: store, Parser: feed.NewReplayParser([]feed.Capture{capture})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Feeds) != 1 || summary.Feeds[0].Status != "ok" || summary.ItemsMatched != 1 {
+		t.Fatalf("replay summary = %+v, want the capture fetched despite backoff", summary)
+	}
+
+	st, err := store.GetFeedState(url)
+	if err != nil || st == nil {
+		t.Fatalf("GetFeedState: %v", err)
+	}
+	if st.ETag != `"live-etag"` || st.ConsecutiveFailures != 1 || st.NextAttemptAt == nil {
+		t.Errorf("replay changed live feed state: %+v", st)
+	}
+}
+
+func TestRunFeedCheck_FailureDoesNotDelayPoll(t *testing.T) {
+	store, err := storage.New(filepath.Join(t.TempDir(), "curator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// A failure whose backoff has already expired must not count as a
+	// recent poll.
+	const url = "https://tracker.example/rss"
+	if err := store.RecordFeedFailure(url, "boom", time.Second, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `<?xml version="1.0"?><rss version="2.0"><channel><title>t</title>
+<item><title>Severance.S02E01.1080p.WEB-DL.x265-NTb</title><link>https://tracker.example/dl/1.torrent</link><guid>1</guid></item>
+</channel></rss>`
+	capture := feed.Capture{URL: url, ContentType: models.ContentTypeShow, Body: []byte(body)}
+	m := matcher.NewMatcher(&models.ShowsConfig{Shows: []models.ShowRule{{Name: "Severance"}}}, nil)
+
+	summary, err := RunFeedCheck(context.Background(), FeedCheckConfig{
+		Feeds:   []models.FeedConfig{{URL: url, ContentType: models.ContentTypeShow, PollIntervalMins: 60}},
+		Matcher: m,
+	}, FeedCheckDeps{Store: store, Parser: feed.NewReplayParser([]feed.Capture{capture})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Feeds) != 1 || summary.Feeds[0].Status != "ok" {
+		t.Fatalf("summary = %+v, want the feed fetched after its backoff", summary)
+	}
+}
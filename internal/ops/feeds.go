@@ -0,0 +1,100 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/killakam3084/rss-curator/internal/storage"
+	"github.com/killakam3084/rss-curator/internal/watchdir"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// RegistryFeeds returns a FeedCheckConfig.LoadFeeds function that reads the
+// enabled feeds from the store's feed registry. It is called at the start of
+// every feed-check run so registry edits take effect without a restart.
+func RegistryFeeds(store storage.Store) func() ([]models.FeedConfig, error) {
+	return func() ([]models.FeedConfig, error) {
+		feeds, err := store.ListFeeds()
+		if err != nil {
+			return nil, err
+		}
+		out := make([]models.FeedConfig, 0, len(feeds))
+		for _, f := range feeds {
+			if f.Enabled {
+				out = append(out, f.FeedConfig())
+			}
+		}
+		return out, nil
+	}
+}
+
+// feedsSeededKey is the settings key recording that the feed registry has
+// been seeded, so feeds the user deletes are not seeded again.
+const feedsSeededKey = "feeds.seeded"
+
+// SeedFeedRegistry populates the feed registry from the env-configured feeds
+// (RSS_FEED_URL / RSS_MOVIE_FEED_URL) so existing installs keep working after
+// upgrading. It runs once: the first call records a marker in the settings
+// table, after which it is a no-op even if every feed is later deleted. A
+// registry that already holds feeds is marked without inserting. Returns the
+// number of feeds inserted.
+func SeedFeedRegistry(store storage.Store, feeds []models.FeedConfig) (int, error) {
+	if done, err := store.GetSetting(feedsSeededKey); err != nil {
+		return 0, fmt.Errorf("SeedFeedRegistry: %w", err)
+	} else if done == "true" {
+		return 0, nil
+	}
+	existing, err := store.ListFeeds()
+	if err != nil {
+		return 0, fmt.Errorf("SeedFeedRegistry: %w", err)
+	}
+	if len(existing) > 0 {
+		feeds = nil
+	}
+	seeded := 0
+	for _, fc := range feeds {
+		name := fc.Name
+		if name == "" {
+			name = string(fc.ContentType) + " feed"
+		}
+		if _, err := store.CreateFeed(models.ManagedFeed{
+			Name:             name,
+			URL:              fc.URL,
+			ContentType:      fc.ContentType,
+			Enabled:          true,
+			PollIntervalMins: fc.PollIntervalMins,
+			Headers:          fc.Headers,
+		}); err != nil {
+			return seeded, fmt.Errorf("SeedFeedRegistry: %w", err)
+		}
+		seeded++
+	}
+	if err := store.SetSetting(feedsSeededKey, "true"); err != nil {
+		return seeded, fmt.Errorf("SeedFeedRegistry: %w", err)
+	}
+	return seeded, nil
+}
+
+// FeedAuthFor returns the credentials of the registry feed whose URL is
+// feedURL, so a .torrent link can be downloaded with the same headers,
+// cookies and passkey used to fetch the feed. Returns the zero FeedAuth when
+// feedURL is empty, unregistered, or the registry cannot be read. Items from
+// a watch directory get that directory's processed/ folder as LocalDir, the
+// only place their file:// links may be read from.
+func FeedAuthFor(store storage.Store, feedURL string) models.FeedAuth {
+	if dir, ok := watchdir.ProcessedDir(feedURL); ok {
+		return models.FeedAuth{LocalDir: dir}
+	}
+	if feedURL == "" || store == nil {
+		return models.FeedAuth{}
+	}
+	feeds, err := store.ListFeeds()
+	if err != nil {
+		return models.FeedAuth{}
+	}
+	for _, f := range feeds {
+		if f.URL == feedURL {
+			return f.FeedConfig().Auth()
+		}
+	}
+	return models.FeedAuth{}
+}
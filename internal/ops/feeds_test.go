@@ -0,0 +1,34 @@
+package ops
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/killakam3084/rss-curator/internal/storage"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+func TestSeedFeedRegistry_RunsOnce(t *testing.T) {
+	store, err := storage.New(filepath.Join(t.TempDir(), "curator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	env := []models.FeedConfig{{URL: "https://tracker.example/rss", ContentType: models.ContentTypeShow}}
+	if n, err := SeedFeedRegistry(store, env); err != nil || n != 1 {
+		t.Fatalf("first seed = %d, %v; want 1", n, err)
+	}
+	feeds, _ := store.ListFeeds()
+	for _, f := range feeds {
+		if err := store.DeleteFeed(f.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := SeedFeedRegistry(store, env); err != nil || n != 0 {
+		t.Errorf("seed after deleting every feed = %d, %v; want 0", n, err)
+	}
+	if feeds, _ := store.ListFeeds(); len(feeds) != 0 {
+		t.Errorf("deleted feeds came back: %+v", feeds)
+	}
+}
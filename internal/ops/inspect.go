@@ -0,0 +1,83 @@
+package ops
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/killakam3084/rss-curator/internal/storage"
+	"github.com/killakam3084/rss-curator/internal/torrentfile"
+	"github.com/killakam3084/rss-curator/pkg/models"
+	"go.uber.org/zap"
+)
+
+// inspectConcurrency bounds parallel .torrent downloads during a feed check.
+const inspectConcurrency = 4
+
+// inspectTimeout bounds a single .torrent download.
+const inspectTimeout = 30 * time.Second
+
+// inspectTorrents downloads and decodes the .torrent behind every http(s)
+// match, setting StagedTorrent.Torrent in place. The feed's credentials are
+// applied to the download. When the indexer did not report an info-hash the
+// decoded one is copied onto the FeedItem. Download or decode failures are
+// logged and leave the item uninspected; magnet links, items whose source
+// already decoded the .torrent and links or info-hashes that are already
+// staged (Add would ignore them) are skipped, so trackers are not hit again.
+// Returns how many items were inspected and how many of those were flagged.
+func inspectTorrents(ctx context.Context, matches []models.StagedTorrent, store storage.Store, log *zap.Logger) (inspected, flagged int) {
+	sem := make(chan struct{}, inspectConcurrency)
+	var wg sync.WaitGroup
+	for i := range matches {
+		link := matches[i].FeedItem.Link
+		if matches[i].Torrent != nil || (!strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://")) {
+			continue
+		}
+		if staged, err := store.IsStaged(link, matches[i].FeedItem.InfoHash); err != nil {
+			log.Warn("torrent inspection: staged check failed", zap.String("title", matches[i].FeedItem.Title), zap.Error(err))
+		} else if staged {
+			continue
+		}
+		auth := FeedAuthFor(store, matches[i].FeedItem.FeedURL)
+		wg.Add(1)
+		go func(t *models.StagedTorrent, auth models.FeedAuth) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			fetchCtx, cancel := context.WithTimeout(ctx, inspectTimeout)
+			defer cancel()
+			data, err := torrentfile.Fetch(fetchCtx, t.FeedItem.Link, auth)
+			if err != nil {
+				log.Warn("torrent inspection: download failed", zap.String("title", t.FeedItem.Title), zap.Error(err))
+				return
+			}
+			meta, err := torrentfile.Inspect(data)
+			if err != nil {
+				log.Warn("torrent inspection: decode failed", zap.String("title", t.FeedItem.Title), zap.Error(err))
+				return
+			}
+			t.Torrent = &meta
+			if t.FeedItem.InfoHash == "" {
+				t.FeedItem.InfoHash = meta.InfoHash
+			}
+		}(&matches[i], auth)
+	}
+	wg.Wait()
+
+	for _, m := range matches {
+		if m.Torrent == nil {
+			continue
+		}
+		inspected++
+		if len(m.Torrent.Flags) > 0 {
+			flagged++
+			log.Warn("torrent inspection: contents flagged",
+				zap.String("title", m.FeedItem.Title),
+				zap.Strings("flags", m.Torrent.Flags),
+			)
+		}
+	}
+	return inspected, flagged
+}
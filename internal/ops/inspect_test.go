@@ -0,0 +1,52 @@
+package ops
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/killakam3084/rss-curator/internal/storage"
+	"github.com/killakam3084/rss-curator/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestInspectTorrents_SkipsKnownItems(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	store, err := storage.New(filepath.Join(t.TempDir(), "curator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+	for _, fi := range []models.FeedItem{
+		{Title: "staged link", Link: srv.URL + "/a.torrent"},
+		{Title: "staged hash", Link: "magnet:?xt=urn:btih:" + hash, InfoHash: hash},
+	} {
+		if err := store.Add(models.StagedTorrent{FeedItem: fi, Status: "pending"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches := []models.StagedTorrent{
+		{FeedItem: models.FeedItem{Title: "staged link", Link: srv.URL + "/a.torrent"}},
+		{FeedItem: models.FeedItem{Title: "staged hash", Link: srv.URL + "/b.torrent", InfoHash: hash}},
+		{FeedItem: models.FeedItem{Title: "decoded", Link: srv.URL + "/c.torrent"}, Torrent: &models.TorrentMeta{InfoHash: hash}},
+		{FeedItem: models.FeedItem{Title: "new", Link: srv.URL + "/d.torrent"}},
+	}
+	inspected, _ := inspectTorrents(context.Background(), matches, store, zap.NewNop())
+	if n := hits.Load(); n != 1 {
+		t.Errorf("tracker hit %d times, want 1 (only the new item)", n)
+	}
+	if inspected != 1 || matches[2].Torrent == nil {
+		t.Errorf("inspected = %d, want the source-decoded item kept", inspected)
+	}
+}
@@ -0,0 +1,159 @@
+package ops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// MigrateRulesConfig configures MigrateRules.
+type MigrateRulesConfig struct {
+	Legacy    models.MatchRule
+	ShowsPath string
+	// DryRun computes the result and diff without writing anything.
+	DryRun bool
+}
+
+// MigrateRulesResult describes one MigrateRules run.
+type MigrateRulesResult struct {
+	Config     *models.ShowsConfig
+	ShowsAdded int
+	// Created is true when no watchlist existed at ShowsPath.
+	Created bool
+	// Diff is a line diff of the watchlist JSON before and after the
+	// migration; empty when nothing changes.
+	Diff    string
+	Written bool
+}
+
+// MigrateRules converts the legacy env-var match rules into watchlist rules,
+// merging them into the watchlist at ShowsPath (or starting a new one), and
+// writes the result unless DryRun is set. Running it again is a no-op.
+func MigrateRules(c MigrateRulesConfig) (MigrateRulesResult, error) {
+	var res MigrateRulesResult
+
+	var current *models.ShowsConfig
+	data, err := os.ReadFile(c.ShowsPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		res.Created = true
+	case err != nil:
+		return res, fmt.Errorf("migrate_rules: read %s: %w", c.ShowsPath, err)
+	default:
+		current = &models.ShowsConfig{}
+		if err := json.Unmarshal(data, current); err != nil {
+			return res, fmt.Errorf("migrate_rules: parse %s: %w", c.ShowsPath, err)
+		}
+	}
+
+	res.Config, res.ShowsAdded = matcher.MigrateLegacyRules(current, c.Legacy)
+	if err := matcher.ValidateShowsConfig(res.Config); err != nil {
+		return res, fmt.Errorf("migrate_rules: %w", err)
+	}
+
+	var before []byte
+	if current != nil {
+		if before, err = json.MarshalIndent(current, "", "  "); err != nil {
+			return res, fmt.Errorf("migrate_rules: marshal config: %w", err)
+		}
+	}
+	after, err := json.MarshalIndent(res.Config, "", "  ")
+	if err != nil {
+		return res, fmt.Errorf("migrate_rules: marshal config: %w", err)
+	}
+	res.Diff = lineDiff(string(before), string(after), 2)
+
+	if c.DryRun || res.Diff == "" {
+		return res, nil
+	}
+	if err := os.WriteFile(c.ShowsPath, after, 0o644); err != nil {
+		return res, fmt.Errorf("migrate_rules: write %s: %w", c.ShowsPath, err)
+	}
+	res.Written = true
+	return res, nil
+}
+
+// lineDiff returns the lines that differ between a and b prefixed with "-"
+// or "+", surrounded by up to context unchanged lines prefixed with " ".
+// Separate hunks are divided by "...". It returns "" when a equals b.
+func lineDiff(a, b string, context int) string {
+	if a == b {
+		return ""
+	}
+	al, bl := splitLines(a), splitLines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of al[i:]
+	// and bl[j:].
+	lcs := make([][]int, len(al)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bl)+1)
+	}
+	for i := len(al) - 1; i >= 0; i-- {
+		for j := len(bl) - 1; j >= 0; j-- {
+			if al[i] == bl[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type op struct {
+		kind byte
+		line string
+	}
+	var steps []op
+	i, j := 0, 0
+	for i < len(al) || j < len(bl) {
+		switch {
+		case i < len(al) && j < len(bl) && al[i] == bl[j]:
+			steps = append(steps, op{' ', al[i]})
+			i++
+			j++
+		case i < len(al) && (j == len(bl) || lcs[i+1][j] >= lcs[i][j+1]):
+			steps = append(steps, op{'-', al[i]})
+			i++
+		default:
+			steps = append(steps, op{'+', bl[j]})
+			j++
+		}
+	}
+
+	// Keep changed lines plus their context.
+	keep := make([]bool, len(steps))
+	for k, o := range steps {
+		if o.kind == ' ' {
+			continue
+		}
+		for c := max(0, k-context); c <= min(len(steps)-1, k+context); c++ {
+			keep[c] = true
+		}
+	}
+	var sb strings.Builder
+	for k, o := range steps {
+		if !keep[k] {
+			continue
+		}
+		if k > 0 && !keep[k-1] && sb.Len() > 0 {
+			sb.WriteString("...\n")
+		}
+		sb.WriteByte(o.kind)
+		sb.WriteString(o.line)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// splitLines splits s into lines, returning none for an empty string.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
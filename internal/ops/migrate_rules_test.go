@@ -0,0 +1,68 @@
+package ops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+func TestMigrateRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlist.json")
+	legacy := models.MatchRule{ShowNames: []string{"Severance", "Foundation"}, MinQuality: "1080p"}
+
+	res, err := MigrateRules(MigrateRulesConfig{Legacy: legacy, ShowsPath: path, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Created || res.Written || res.ShowsAdded != 2 || !strings.Contains(res.Diff, `+      "name": "Foundation"`) {
+		t.Errorf("dry run = %+v", res)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("dry run wrote the watchlist")
+	}
+
+	res, err = MigrateRules(MigrateRulesConfig{Legacy: legacy, ShowsPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Written {
+		t.Fatal("migration did not write the watchlist")
+	}
+	if res.Config == nil || len(res.Config.Shows) != 2 {
+		t.Errorf("migrated config = %+v, want 2 show rules", res.Config)
+	}
+
+	// A second run over the written watchlist changes nothing.
+	res, err = MigrateRules(MigrateRulesConfig{Legacy: legacy, ShowsPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Written || res.Diff != "" || res.ShowsAdded != 0 {
+		t.Errorf("second run = %+v, want no-op", res)
+	}
+
+	// New legacy names merge into the existing file as a small diff.
+	legacy.ShowNames = append(legacy.ShowNames, "Silo")
+	res, err = MigrateRules(MigrateRulesConfig{Legacy: legacy, ShowsPath: path, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Created || res.ShowsAdded != 1 || strings.Contains(res.Diff, "-") || !strings.Contains(res.Diff, `"Silo"`) {
+		t.Errorf("merge dry run = %+v\n%s", res, res.Diff)
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\n"
+	b := "a\nB\nc\nd\ne\nf\ng\nh\n"
+	want := " a\n-b\n+B\n c\n d\n...\n f\n g\n+h\n"
+	if got := lineDiff(a, b, 2); got != want {
+		t.Errorf("lineDiff =\n%s\nwant\n%s", got, want)
+	}
+	if lineDiff(a, a, 2) != "" {
+		t.Error("identical input produced a diff")
+	}
+}
@@ -0,0 +1,35 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/internal/storage"
+)
+
+// BackfillReleaseScores scores pending torrents that carry no release score —
+// items staged before scores were persisted, which would otherwise rank
+// below every new candidate in dedup and auto-queue until rematched. Each is
+// evaluated against the current rules; status and match reason are left
+// alone. Returns how many scores were written.
+func BackfillReleaseScores(store storage.Store, m *matcher.Matcher) (int, error) {
+	pending, err := store.List("pending", "", "")
+	if err != nil {
+		return 0, fmt.Errorf("release score backfill: list pending: %w", err)
+	}
+	n := 0
+	for _, t := range pending {
+		if t.ReleaseScore != 0 {
+			continue
+		}
+		res := m.Evaluate(t.FeedItem)
+		if !res.Matched || res.Score == 0 {
+			continue
+		}
+		if err := store.SetReleaseScore(t.ID, res.Score); err != nil {
+			return n, fmt.Errorf("release score backfill: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
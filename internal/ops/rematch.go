@@ -120,7 +120,8 @@ func RunRematch(ctx context.Context, opts RematchOptions, deps RematchDeps) (Rem
 			}
 		}
 
-		matches, reason := deps.Matcher.Match(item)
+		res := deps.Matcher.Evaluate(item)
+		matches, reason := res.Matched, res.Reason
 		newStatus := t.Status
 		newReason := reason
 		if matches {
@@ -135,7 +136,7 @@ func RunRematch(ctx context.Context, opts RematchOptions, deps RematchDeps) (Rem
 			}
 		}
 
-		if err := deps.Store.UpdateAfterRematch(t.ID, item, newReason, newStatus); err != nil {
+		if err := deps.Store.UpdateAfterRematch(t.ID, item, newReason, newStatus, res.Score); err != nil {
 			log.Error("failed to persist rematch update", zap.Int("id", t.ID), zap.Error(err))
 			lastErr = err
 			result.Skipped++
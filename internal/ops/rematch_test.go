@@ -0,0 +1,121 @@
+package ops
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/internal/storage"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+func TestRunRematch_HonoursEpisodeBounds(t *testing.T) {
+	store, err := storage.New(filepath.Join(t.TempDir(), "curator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	for _, title := range []string{
+		"Slow.Horses.S01E03.1080p.WEB-DL.x264-GRP",
+		"Slow.Horses.S00E01.1080p.WEB-DL.x264-GRP",
+		"Slow.Horses.S04E02.1080p.WEB-DL.x264-GRP",
+	} {
+		if err := store.Add(models.StagedTorrent{
+			FeedItem:    models.FeedItem{Title: title, Link: "http://tracker.test/" + title, GUID: title},
+			MatchReason: "matches show: Slow Horses",
+			Status:      "pending",
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pending, err := store.List("pending", "", "")
+	if err != nil || len(pending) != 3 {
+		t.Fatalf("List = %d items, err %v", len(pending), err)
+	}
+	ids := make([]int, len(pending))
+	for i, p := range pending {
+		ids[i] = p.ID
+	}
+
+	m := matcher.NewMatcher(&models.ShowsConfig{Shows: []models.ShowRule{{
+		Name: "Slow Horses", FromSeason: 4, SkipSpecials: true,
+	}}}, nil)
+	res, err := RunRematch(context.Background(), RematchOptions{IDs: ids}, RematchDeps{Store: store, Matcher: m})
+	if err != nil {
+		t.Fatalf("RunRematch: %v", err)
+	}
+	if res.Rematched != 1 || res.NoLongerMatches != 2 {
+		t.Fatalf("result = %+v, want 1 rematched and 2 no longer matching", res)
+	}
+
+	want := map[string]string{
+		"Slow.Horses.S01E03.1080p.WEB-DL.x264-GRP": "season 1 before from_season 4",
+		"Slow.Horses.S00E01.1080p.WEB-DL.x264-GRP": "special S00E01 skipped",
+		"Slow.Horses.S04E02.1080p.WEB-DL.x264-GRP": "",
+	}
+	for _, u := range res.Updated {
+		reason := want[u.FeedItem.Title]
+		if reason == "" {
+			if u.Status != "pending" {
+				t.Errorf("%s: status %q, want pending", u.FeedItem.Title, u.Status)
+			}
+			continue
+		}
+		if u.Status != "rejected" || !strings.Contains(u.MatchReason, reason) {
+			t.Errorf("%s: status %q reason %q, want rejected with %q", u.FeedItem.Title, u.Status, u.MatchReason, reason)
+		}
+	}
+}
+
+func TestBackfillReleaseScores_ScoresUnscoredPending(t *testing.T) {
+	store, err := storage.New(filepath.Join(t.TempDir(), "curator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	for _, tc := range []struct {
+		title string
+		score int
+	}{
+		{"Slow.Horses.S04E01.1080p.WEB-DL.x264-GRP", 0},
+		{"Slow.Horses.S04E02.720p.WEB-DL.x264-GRP", 7},
+	} {
+		if err := store.Add(models.StagedTorrent{
+			FeedItem:     models.FeedItem{Title: tc.title, Link: "http://tracker.test/" + tc.title, GUID: tc.title, ShowName: "Slow Horses", Quality: "1080P"},
+			MatchReason:  "matches show: Slow Horses",
+			Status:       "pending",
+			ReleaseScore: tc.score,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := matcher.NewMatcher(&models.ShowsConfig{Shows: []models.ShowRule{{Name: "Slow Horses"}}}, nil)
+	n, err := BackfillReleaseScores(store, m)
+	if err != nil {
+		t.Fatalf("BackfillReleaseScores: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("backfilled %d, want 1", n)
+	}
+	pending, err := store.List("pending", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range pending {
+		switch p.FeedItem.Title {
+		case "Slow.Horses.S04E01.1080p.WEB-DL.x264-GRP":
+			if p.ReleaseScore <= 0 {
+				t.Errorf("unscored item still has score %d", p.ReleaseScore)
+			}
+		default:
+			if p.ReleaseScore != 7 {
+				t.Errorf("scored item changed to %d, want 7", p.ReleaseScore)
+			}
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package ops
+
+import (
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/internal/routing"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// DownloadOptions returns the qBittorrent add options (category, tags,
+// expanded save path) from the watchlist rule that claims item, or an empty
+// map when no rule applies so the client's global settings are used.
+func DownloadOptions(m *matcher.Matcher, item models.FeedItem) (map[string]string, error) {
+	if m == nil {
+		return map[string]string{}, nil
+	}
+	rules, ok := m.RuleSettings(item)
+	if !ok {
+		return map[string]string{}, nil
+	}
+	return routing.Options(item, rules)
+}
@@ -2,14 +2,13 @@ package ops
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 
 	"github.com/killakam3084/rss-curator/internal/feed"
 	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/internal/metadata"
 	"github.com/killakam3084/rss-curator/internal/storage"
 	"github.com/killakam3084/rss-curator/pkg/models"
 	"go.uber.org/zap"
@@ -21,11 +20,15 @@ type WatchlistEnrichDeps struct {
 	Matcher   *matcher.Matcher
 	ShowsPath string
 	Logger    *zap.Logger
+	// MetaLookup, when non-nil, supplies provider alternate titles that are
+	// merged into each rule's aliases.
+	MetaLookup *metadata.Lookup
 }
 
 // RunWatchlistEnrich backfills empty ShowRule/MovieRule fields (preferred_codec,
-// preferred_groups, preferred_hdr) from approval history. Fields that are
-// already populated are never overwritten.
+// preferred_groups, preferred_hdr) from approval history and merges provider
+// alternate titles into rule aliases. Fields that are already populated are
+// never overwritten; aliases are only ever added.
 func RunWatchlistEnrich(ctx context.Context, deps WatchlistEnrichDeps) (models.WatchlistEnrichSummary, error) {
 	log := deps.Logger
 	if log == nil {
@@ -111,25 +114,41 @@ func runWatchlistEnrich(ctx context.Context, deps WatchlistEnrichDeps, log *zap.
 
 	// Fill empty ShowRule fields.
 	for i, rule := range cfg.Shows {
+		if ctx.Err() != nil {
+			break
+		}
+		changed := false
+		if deps.MetaLookup != nil {
+			if meta := deps.MetaLookup.Resolve(ctx, rule.Name); meta != nil {
+				var added int
+				cfg.Shows[i].Aliases, added = mergeAliases(rule.Aliases, rule.Name, meta.AlternateTitles)
+				summary.AliasesAdded += added
+				changed = added > 0
+			}
+		}
 		key := enrichNormalizeName(rule.Name)
 		nd, ok := data[key]
 		if !ok || nd.contentType != models.ContentTypeShow {
+			if changed {
+				summary.ShowsUpdated++
+			}
 			continue
 		}
-		changed := false
-		if rule.PreferredCodec == "" {
+		// Fields the rule's profile supplies are inherited, not backfilled.
+		inherited := profileRules(cfg, rule.Profile)
+		if rule.PreferredCodec == "" && inherited.PreferredCodec == "" {
 			if codec := enrichModeCodec(nd.codecCounts); codec != "" {
 				cfg.Shows[i].PreferredCodec = codec
 				changed = true
 			}
 		}
-		if len(rule.PreferredGroups) == 0 {
+		if len(rule.PreferredGroups) == 0 && len(inherited.PreferredGroups) == 0 {
 			if groups := enrichSortedKeys(nd.groupsSeen); len(groups) > 0 {
 				cfg.Shows[i].PreferredGroups = groups
 				changed = true
 			}
 		}
-		if len(rule.PreferredHDR) == 0 {
+		if len(rule.PreferredHDR) == 0 && len(inherited.PreferredHDR) == 0 {
 			if hdrs := enrichSortedKeys(nd.hdrSeen); len(hdrs) > 0 {
 				cfg.Shows[i].PreferredHDR = hdrs
 				changed = true
@@ -142,25 +161,42 @@ func runWatchlistEnrich(ctx context.Context, deps WatchlistEnrichDeps, log *zap.
 
 	// Fill empty MovieRule fields.
 	for i, rule := range cfg.Movies {
+		if ctx.Err() != nil {
+			break
+		}
+		changed := false
+		if deps.MetaLookup != nil {
+			baseName, _ := matcher.MovieRuleNameParts(rule.Name)
+			if meta := deps.MetaLookup.ResolveMovie(ctx, baseName); meta != nil {
+				var added int
+				cfg.Movies[i].Aliases, added = mergeAliases(rule.Aliases, baseName, meta.AlternateTitles)
+				summary.AliasesAdded += added
+				changed = added > 0
+			}
+		}
 		key := enrichNormalizeName(rule.Name)
 		nd, ok := data[key]
 		if !ok || nd.contentType != models.ContentTypeMovie {
+			if changed {
+				summary.MoviesUpdated++
+			}
 			continue
 		}
-		changed := false
-		if rule.PreferredCodec == "" {
+		// Fields the rule's profile supplies are inherited, not backfilled.
+		inherited := profileRules(cfg, rule.Profile)
+		if rule.PreferredCodec == "" && inherited.PreferredCodec == "" {
 			if codec := enrichModeCodec(nd.codecCounts); codec != "" {
 				cfg.Movies[i].PreferredCodec = codec
 				changed = true
 			}
 		}
-		if len(rule.PreferredGroups) == 0 {
+		if len(rule.PreferredGroups) == 0 && len(inherited.PreferredGroups) == 0 {
 			if groups := enrichSortedKeys(nd.groupsSeen); len(groups) > 0 {
 				cfg.Movies[i].PreferredGroups = groups
 				changed = true
 			}
 		}
-		if len(rule.PreferredHDR) == 0 {
+		if len(rule.PreferredHDR) == 0 && len(inherited.PreferredHDR) == 0 {
 			if hdrs := enrichSortedKeys(nd.hdrSeen); len(hdrs) > 0 {
 				cfg.Movies[i].PreferredHDR = hdrs
 				changed = true
@@ -173,17 +209,13 @@ func runWatchlistEnrich(ctx context.Context, deps WatchlistEnrichDeps, log *zap.
 
 	// Persist and hot-reload only when something actually changed.
 	if summary.ShowsUpdated+summary.MoviesUpdated > 0 {
-		out, err := json.MarshalIndent(cfg, "", "  ")
-		if err != nil {
-			return summary, fmt.Errorf("watchlist_enrich: marshal config: %w", err)
-		}
-		if err := os.WriteFile(deps.ShowsPath, out, 0o644); err != nil {
-			return summary, fmt.Errorf("watchlist_enrich: write shows.json: %w", err)
+		if err := writeWatchlist(deps.ShowsPath, deps.Matcher, cfg); err != nil {
+			return summary, fmt.Errorf("watchlist_enrich: %w", err)
 		}
-		deps.Matcher.SetShowsConfig(cfg)
 		log.Info("watchlist_enrich completed",
 			zap.Int("shows_updated", summary.ShowsUpdated),
 			zap.Int("movies_updated", summary.MoviesUpdated),
+			zap.Int("aliases_added", summary.AliasesAdded),
 		)
 	} else {
 		log.Info("watchlist_enrich: no changes needed")
@@ -192,6 +224,16 @@ func runWatchlistEnrich(ctx context.Context, deps WatchlistEnrichDeps, log *zap.
 	return summary, nil
 }
 
+// profileRules returns the fields a rule inherits from its named profile, or
+// zero rules when it names none (Defaults are not inherited here: enrichment
+// exists to give rules values of their own where only the defaults applied).
+func profileRules(cfg *models.ShowsConfig, name string) models.DefaultRules {
+	if p := cfg.Profile(name); p != nil {
+		return p.DefaultRules
+	}
+	return models.DefaultRules{}
+}
+
 // enrichNameFromMatchReason parses a match reason like
 // "matches show: Dark, quality: 1080P" or "matches movie: Oppenheimer, ..."
 // and returns the content name and its ContentType.
@@ -216,6 +258,38 @@ func enrichNormalizeName(name string) string {
 	return strings.ToLower(strings.TrimSpace(name))
 }
 
+// mergeAliases appends provider alternate titles to a rule's aliases and
+// reports how many were added. Titles that normalize to the rule name or an
+// existing alias are skipped, as are non-Latin titles, which never appear in
+// release names.
+func mergeAliases(aliases []string, name string, titles []string) ([]string, int) {
+	seen := map[string]bool{matcher.NormalizeTitle(name): true}
+	for _, a := range aliases {
+		seen[matcher.NormalizeTitle(a)] = true
+	}
+	added := 0
+	for _, t := range titles {
+		norm := matcher.NormalizeTitle(t)
+		if norm == "" || seen[norm] || !isASCII(norm) {
+			continue
+		}
+		seen[norm] = true
+		aliases = append(aliases, strings.TrimSpace(t))
+		added++
+	}
+	return aliases, added
+}
+
+// isASCII reports whether s contains only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
 // enrichModeCodec returns the codec with the highest approval count.
 // Ties broken alphabetically for determinism.
 func enrichModeCodec(counts map[string]int) string {
@@ -63,3 +63,20 @@ func TestEnrichNormalizeName(t *testing.T) {
 		t.Errorf("normalize = %q, want 'dark'", got)
 	}
 }
+
+func TestMergeAliases(t *testing.T) {
+	got, added := mergeAliases(
+		[]string{"Law & Order: SVU"},
+		"Law & Order: Special Victims Unit",
+		[]string{"Law and Order SVU", "Law & Order: Special Victims Unit", "New York Unité Spéciale", "ロー&オーダー", "NY Unit"},
+	)
+	want := []string{"Law & Order: SVU", "New York Unité Spéciale", "NY Unit"}
+	if added != 2 || len(got) != len(want) {
+		t.Fatalf("mergeAliases = %v (added %d), want %v (added 2)", got, added, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("alias[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
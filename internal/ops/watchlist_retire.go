@@ -0,0 +1,228 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/internal/metadata"
+	"github.com/killakam3084/rss-curator/internal/storage"
+	"github.com/killakam3084/rss-curator/pkg/models"
+	"go.uber.org/zap"
+)
+
+// WatchlistRetireDeps holds the shared dependencies for RunWatchlistRetire.
+type WatchlistRetireDeps struct {
+	Store     storage.Store
+	Matcher   *matcher.Matcher
+	ShowsPath string
+	Logger    *zap.Logger
+	// MetaLookup supplies show status and finale; without it shows are never
+	// retired, only movie rules with retire_after.
+	MetaLookup *metadata.Lookup
+}
+
+// RunWatchlistRetire moves finished rules to the watchlist archive: movie
+// rules whose retire_after condition is met by a queued torrent, and shows
+// the metadata provider reports as Ended whose final episode has been
+// queued. Each retirement is recorded in the activity log as "retire".
+func RunWatchlistRetire(ctx context.Context, deps WatchlistRetireDeps) (models.WatchlistRetireSummary, error) {
+	log := deps.Logger
+	if log == nil {
+		log = zap.NewNop()
+	}
+
+	jobID, jobErr := deps.Store.CreateJob("watchlist_retire")
+	if jobErr != nil {
+		log.Warn("could not create watchlist_retire job", zap.Error(jobErr))
+	}
+
+	summary, err := runWatchlistRetire(ctx, deps, log, time.Now())
+	if err != nil {
+		if jobErr == nil {
+			_ = deps.Store.FailJob(jobID, err.Error())
+		}
+		return summary, err
+	}
+	if jobErr == nil {
+		_ = deps.Store.CompleteJob(jobID, summary)
+	}
+	return summary, nil
+}
+
+// retirement is one rule leaving the watchlist, with the queued torrent that
+// completed it.
+type retirement struct {
+	torrent models.StagedTorrent
+	reason  string
+}
+
+func runWatchlistRetire(ctx context.Context, deps WatchlistRetireDeps, log *zap.Logger, now time.Time) (models.WatchlistRetireSummary, error) {
+	var summary models.WatchlistRetireSummary
+
+	cfg := deps.Matcher.ShowsConfig()
+	if cfg == nil {
+		return summary, nil
+	}
+	queued, err := deps.Store.List("queued", "", "")
+	if err != nil {
+		return summary, fmt.Errorf("watchlist_retire: list queued: %w", err)
+	}
+
+	// Group queued torrents by the rule that claims them.
+	byShow := make(map[string][]models.StagedTorrent)
+	byMovie := make(map[string][]models.StagedTorrent)
+	for _, t := range queued {
+		show, movie := deps.Matcher.FindRule(t.FeedItem)
+		switch {
+		case show != nil:
+			byShow[strings.ToLower(show.Name)] = append(byShow[strings.ToLower(show.Name)], t)
+		case movie != nil:
+			byMovie[strings.ToLower(movie.Name)] = append(byMovie[strings.ToLower(movie.Name)], t)
+		}
+	}
+
+	// Decide from the snapshot; metadata lookups can be slow, so the
+	// watchlist is re-read before writing and only these rules are moved.
+	retiredMovies := make(map[string]retirement)
+	for _, rule := range cfg.Movies {
+		eff := rule.Settings().WithFallback(cfg.RuleDefaults(rule.Profile))
+		if r, ok := movieRetirement(rule, eff, byMovie[strings.ToLower(rule.Name)]); ok {
+			retiredMovies[strings.ToLower(rule.Name)] = r
+		}
+	}
+
+	retiredShows := make(map[string]retirement)
+	for _, rule := range cfg.Shows {
+		// Only shows with a queued torrent can be retired; skip the lookup
+		// for the rest.
+		key := strings.ToLower(rule.Name)
+		if ctx.Err() != nil || deps.MetaLookup == nil || len(byShow[key]) == 0 {
+			continue
+		}
+		meta := deps.MetaLookup.Resolve(ctx, rule.Name)
+		if r, ok := showRetirement(rule.Name, meta, byShow[key]); ok {
+			retiredShows[key] = r
+		}
+	}
+
+	if len(retiredMovies)+len(retiredShows) == 0 {
+		log.Info("watchlist_retire: nothing to retire")
+		return summary, nil
+	}
+
+	cfg = deps.Matcher.ShowsConfig()
+	if cfg == nil {
+		return summary, nil
+	}
+	var logs []retirement
+	keptMovies := cfg.Movies[:0:0]
+	for _, rule := range cfg.Movies {
+		if r, ok := retiredMovies[strings.ToLower(rule.Name)]; ok {
+			cfg.ArchivedMovies = append(cfg.ArchivedMovies, models.ArchivedMovie{
+				MovieRule: rule, ArchivedAt: now, ArchiveReason: r.reason,
+			})
+			logs = append(logs, r)
+			summary.MoviesRetired++
+			continue
+		}
+		keptMovies = append(keptMovies, rule)
+	}
+	keptShows := cfg.Shows[:0:0]
+	for _, rule := range cfg.Shows {
+		if r, ok := retiredShows[strings.ToLower(rule.Name)]; ok {
+			cfg.ArchivedShows = append(cfg.ArchivedShows, models.ArchivedShow{
+				ShowRule: rule, ArchivedAt: now, ArchiveReason: r.reason,
+			})
+			logs = append(logs, r)
+			summary.ShowsRetired++
+			continue
+		}
+		keptShows = append(keptShows, rule)
+	}
+	if len(logs) == 0 {
+		log.Info("watchlist_retire: retired rules were removed concurrently")
+		return summary, nil
+	}
+	cfg.Shows, cfg.Movies = keptShows, keptMovies
+	if err := writeWatchlist(deps.ShowsPath, deps.Matcher, cfg); err != nil {
+		return summary, fmt.Errorf("watchlist_retire: %w", err)
+	}
+	for _, r := range logs {
+		if err := deps.Store.LogActivity(r.torrent.ID, r.torrent.FeedItem.Title, "retire", r.reason); err != nil {
+			log.Warn("watchlist_retire: could not log activity", zap.Error(err))
+		}
+	}
+	log.Info("watchlist_retire completed",
+		zap.Int("shows_retired", summary.ShowsRetired),
+		zap.Int("movies_retired", summary.MoviesRetired),
+	)
+	return summary, nil
+}
+
+// movieRetirement reports whether a movie rule's retire_after condition is
+// met by one of its queued torrents.
+func movieRetirement(rule models.MovieRule, eff models.DefaultRules, queued []models.StagedTorrent) (retirement, bool) {
+	if rule.RetireAfter == "" {
+		return retirement{}, false
+	}
+	for _, t := range queued {
+		if rule.RetireAfter == models.RetireAfterCutoff && !matcher.MeetsCutoff(t.FeedItem.Quality, eff.UpgradeCutoff) {
+			continue
+		}
+		reason := fmt.Sprintf("retired movie %s: queued #%d", rule.Name, t.ID)
+		if t.FeedItem.Quality != "" {
+			reason += " at " + t.FeedItem.Quality
+		}
+		if rule.RetireAfter == models.RetireAfterCutoff && eff.UpgradeCutoff != "" {
+			reason += ", cutoff " + eff.UpgradeCutoff
+		}
+		return retirement{t, reason}, true
+	}
+	return retirement{}, false
+}
+
+// showRetirement reports whether meta marks show name as Ended and one of its
+// queued torrents covers the final episode.
+func showRetirement(name string, meta *metadata.ShowMetadata, queued []models.StagedTorrent) (retirement, bool) {
+	if meta == nil || !strings.EqualFold(meta.Status, "Ended") || meta.LastSeason == 0 {
+		return retirement{}, false
+	}
+	for _, t := range queued {
+		if coversEpisode(t.FeedItem, meta.LastSeason, meta.LastEpisode) {
+			return retirement{t, fmt.Sprintf("retired show %s: ended, final episode S%02dE%02d queued #%d",
+				meta.ShowName, meta.LastSeason, meta.LastEpisode, t.ID)}, true
+		}
+	}
+	return retirement{}, false
+}
+
+// coversEpisode reports whether fi contains season/episode: the episode
+// itself, a multi-episode range spanning it, or a pack of its season.
+func coversEpisode(fi models.FeedItem, season, episode int) bool {
+	if fi.Season != season || fi.AirDate != "" {
+		return false
+	}
+	if fi.IsSeasonPack {
+		return true
+	}
+	return fi.Episode == episode || (fi.Episode < episode && episode <= fi.EpisodeEnd)
+}
+
+// writeWatchlist persists cfg to path as pretty-printed JSON and publishes it
+// to the matcher.
+func writeWatchlist(path string, m *matcher.Matcher, cfg *models.ShowsConfig) error {
+	out, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		return fmt.Errorf("write shows.json: %w", err)
+	}
+	m.SetShowsConfig(cfg)
+	return nil
+}
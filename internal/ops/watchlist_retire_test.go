@@ -0,0 +1,175 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/killakam3084/rss-curator/internal/matcher"
+	"github.com/killakam3084/rss-curator/internal/metadata"
+	"github.com/killakam3084/rss-curator/internal/storage"
+	"github.com/killakam3084/rss-curator/pkg/models"
+	"go.uber.org/zap"
+)
+
+func TestRunWatchlistRetire_Movies(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.New(filepath.Join(dir, "curator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	movie := func(title, quality string) models.FeedItem {
+		return models.FeedItem{
+			Title: title, Link: "http://tracker.test/" + title, GUID: title,
+			ContentType: models.ContentTypeMovie, ShowName: title, Quality: quality,
+		}
+	}
+	for _, fi := range []models.FeedItem{movie("Heat", "1080p"), movie("Arrival", "1080p"), movie("Sicario", "1080p")} {
+		if err := store.Add(models.StagedTorrent{FeedItem: fi, MatchReason: "matches movie: " + fi.ShowName}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, _ := store.List("", "", "")
+	for _, st := range all {
+		_ = store.UpdateStatus(st.ID, "queued")
+	}
+
+	cfg := &models.ShowsConfig{
+		Movies: []models.MovieRule{
+			{Name: "Heat", RetireAfter: models.RetireAfterQueued},
+			{Name: "Arrival", RetireAfter: models.RetireAfterCutoff, UpgradeCutoff: "2160p"},
+			{Name: "Sicario"},
+		},
+	}
+	m := matcher.NewMatcher(cfg, nil)
+	showsPath := filepath.Join(dir, "watchlist.json")
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	summary, err := runWatchlistRetire(context.Background(), WatchlistRetireDeps{
+		Store: store, Matcher: m, ShowsPath: showsPath,
+	}, zap.NewNop(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.MoviesRetired != 1 {
+		t.Fatalf("MoviesRetired = %d, want 1", summary.MoviesRetired)
+	}
+
+	live := m.ShowsConfig()
+	if len(live.Movies) != 2 || live.Movies[0].Name != "Arrival" || live.Movies[1].Name != "Sicario" {
+		t.Errorf("remaining movies = %+v, want Arrival (below cutoff) and Sicario", live.Movies)
+	}
+	if len(live.ArchivedMovies) != 1 || live.ArchivedMovies[0].Name != "Heat" || !live.ArchivedMovies[0].ArchivedAt.Equal(now) {
+		t.Errorf("archive = %+v, want Heat", live.ArchivedMovies)
+	}
+	if ok, _ := m.Match(movie("Heat", "2160p")); ok {
+		t.Error("an archived rule still matches")
+	}
+
+	raw, err := os.ReadFile(showsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var onDisk models.ShowsConfig
+	if err := json.Unmarshal(raw, &onDisk); err != nil || len(onDisk.ArchivedMovies) != 1 {
+		t.Errorf("watchlist on disk: %d archived movies, err %v", len(onDisk.ArchivedMovies), err)
+	}
+
+	acts, _ := store.GetActivity(10, 0, "retire")
+	if len(acts) != 1 || acts[0].MatchReason != "retired movie Heat: queued #1 at 1080p" {
+		t.Errorf("activity = %+v", acts)
+	}
+}
+
+func TestShowRetirement(t *testing.T) {
+	queued := []models.StagedTorrent{
+		{ID: 4, FeedItem: models.FeedItem{Season: 3, Episode: 8, EpisodeEnd: 10}},
+	}
+	ended := &metadata.ShowMetadata{Status: "Ended", LastSeason: 3, LastEpisode: 10}
+	if r, ok := showRetirement("Dark", ended, queued); !ok || r.torrent.ID != 4 {
+		t.Errorf("finale inside a queued range should retire the show: %+v %v", r, ok)
+	}
+	running := &metadata.ShowMetadata{Status: "Running", LastSeason: 3, LastEpisode: 10}
+	if _, ok := showRetirement("Dark", running, queued); ok {
+		t.Error("a running show was retired")
+	}
+	if _, ok := showRetirement("Dark", &metadata.ShowMetadata{Status: "Ended", LastSeason: 4, LastEpisode: 1}, queued); ok {
+		t.Error("an ended show was retired before its finale was queued")
+	}
+	if !coversEpisode(models.FeedItem{Season: 4, IsSeasonPack: true}, 4, 1) {
+		t.Error("a season pack covers its season's finale")
+	}
+}
+
+// editingProvider reports every show as ended after S01E08 and, on its first
+// fetch, adds a rule to the live watchlist the way a concurrent API edit would.
+type editingProvider struct {
+	m       *matcher.Matcher
+	fetched []string
+}
+
+func (p *editingProvider) Name() string { return "test" }
+
+func (p *editingProvider) Fetch(_ context.Context, name string) (*metadata.ShowMetadata, error) {
+	p.fetched = append(p.fetched, name)
+	if len(p.fetched) == 1 {
+		cfg := p.m.ShowsConfig()
+		cfg.Shows = append(cfg.Shows, models.ShowRule{Name: "Severance"})
+		p.m.SetShowsConfig(cfg)
+	}
+	return &metadata.ShowMetadata{ShowName: name, Status: "Ended", LastSeason: 1, LastEpisode: 8}, nil
+}
+
+func TestRunWatchlistRetire_Shows(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.New(filepath.Join(dir, "curator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	fi := models.FeedItem{
+		Title: "Dark.S01E08.1080p.WEB-DL-GRP", Link: "http://tracker.test/dark", GUID: "dark",
+		ShowName: "Dark", Season: 1, Episode: 8,
+	}
+	if err := store.Add(models.StagedTorrent{FeedItem: fi, MatchReason: "matches show: Dark"}); err != nil {
+		t.Fatal(err)
+	}
+	all, _ := store.List("", "", "")
+	_ = store.UpdateStatus(all[0].ID, "queued")
+
+	m := matcher.NewMatcher(&models.ShowsConfig{
+		Shows: []models.ShowRule{{Name: "Dark"}, {Name: "Andor"}},
+	}, nil)
+	provider := &editingProvider{m: m}
+	summary, err := runWatchlistRetire(context.Background(), WatchlistRetireDeps{
+		Store: store, Matcher: m, ShowsPath: filepath.Join(dir, "watchlist.json"),
+		MetaLookup: metadata.NewLookup(provider, nil),
+	}, zap.NewNop(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.ShowsRetired != 1 {
+		t.Fatalf("ShowsRetired = %d, want 1", summary.ShowsRetired)
+	}
+	if len(provider.fetched) != 1 || provider.fetched[0] != "Dark" {
+		t.Errorf("fetched %v, want only Dark (Andor has nothing queued)", provider.fetched)
+	}
+
+	live := m.ShowsConfig()
+	var names []string
+	for _, r := range live.Shows {
+		names = append(names, r.Name)
+	}
+	if len(names) != 2 || names[0] != "Andor" || names[1] != "Severance" {
+		t.Errorf("remaining shows = %v, want Andor and the concurrently added Severance", names)
+	}
+	if len(live.ArchivedShows) != 1 || live.ArchivedShows[0].Name != "Dark" {
+		t.Errorf("archive = %+v, want Dark", live.ArchivedShows)
+	}
+}
@@ -0,0 +1,131 @@
+// Package routing turns a rule's download routing (qBittorrent category, tags
+// and save-path template) into add-torrent options for one feed item.
+package routing
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// placeholderRe matches "{name}" or "{name:NN}" in a save-path template.
+var placeholderRe = regexp.MustCompile(`\{([a-z_]+)(?::([0-9]+))?\}`)
+
+// numericFields are the placeholders that accept a zero-pad width.
+var numericFields = map[string]bool{"season": true, "episode": true, "year": true}
+
+// placeholderValue returns the value of a template field for item.
+func placeholderValue(item models.FeedItem, field string) (string, int, bool) {
+	switch field {
+	case "show", "title":
+		return item.ShowName, 0, true
+	case "year":
+		return "", item.ReleaseYear, true
+	case "season":
+		return "", item.Season, true
+	case "episode":
+		return "", item.Episode, true
+	case "quality":
+		return item.Quality, 0, true
+	case "codec":
+		return item.Codec, 0, true
+	case "source":
+		return item.Source, 0, true
+	case "group":
+		return item.ReleaseGroup, 0, true
+	case "content_type":
+		return string(item.ContentType), 0, true
+	}
+	return "", 0, false
+}
+
+// ValidateSavePath reports an unknown placeholder, a width on a text field
+// or an unbalanced brace in tmpl.
+func ValidateSavePath(tmpl string) error {
+	for _, m := range placeholderRe.FindAllStringSubmatch(tmpl, -1) {
+		if _, _, ok := placeholderValue(models.FeedItem{}, m[1]); !ok {
+			return fmt.Errorf("unknown placeholder {%s}", m[1])
+		}
+		if m[2] != "" && !numericFields[m[1]] {
+			return fmt.Errorf("placeholder {%s} does not take a width", m[1])
+		}
+	}
+	rest := placeholderRe.ReplaceAllString(tmpl, "")
+	if strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("unbalanced or malformed placeholder in %q", tmpl)
+	}
+	return nil
+}
+
+// ExpandSavePath fills tmpl from item. "{season:02}" zero-pads to two
+// digits. Values are made safe as single path segments, and a segment left
+// with empty brackets by a missing value ("Heat ()") is tidied ("Heat").
+func ExpandSavePath(tmpl string, item models.FeedItem) (string, error) {
+	if err := ValidateSavePath(tmpl); err != nil {
+		return "", err
+	}
+	out := placeholderRe.ReplaceAllStringFunc(tmpl, func(ph string) string {
+		m := placeholderRe.FindStringSubmatch(ph)
+		text, n, _ := placeholderValue(item, m[1])
+		if !numericFields[m[1]] {
+			return sanitizeSegment(text)
+		}
+		if n == 0 && m[1] == "year" {
+			return ""
+		}
+		width, _ := strconv.Atoi(m[2])
+		return fmt.Sprintf("%0*d", width, n)
+	})
+
+	segments := strings.Split(out, "/")
+	for i, seg := range segments {
+		segments[i] = tidySegment(seg)
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// segmentReplacer drops characters that are unsafe in file names on common
+// filesystems and turns path separators inside a value into dashes.
+var segmentReplacer = strings.NewReplacer(
+	"/", "-", "\\", "-", ":", "", "*", "", "?", "", "\"", "", "<", "", ">", "", "|", "",
+)
+
+func sanitizeSegment(s string) string {
+	return strings.TrimSpace(segmentReplacer.Replace(s))
+}
+
+var emptyBracketsRe = regexp.MustCompile(`\s*(\(\s*\)|\[\s*\])`)
+
+// tidySegment removes empty "()"/"[]" pairs, collapses runs of spaces and
+// trims trailing dots and spaces from one path segment.
+func tidySegment(seg string) string {
+	seg = emptyBracketsRe.ReplaceAllString(seg, "")
+	seg = strings.Join(strings.Fields(seg), " ")
+	return strings.TrimRight(seg, ". ")
+}
+
+// Options returns the qBittorrent add options for item under the effective
+// rule settings. Unset fields are omitted so the client's global category
+// and save path still apply. A save path also turns off automatic torrent
+// management for the torrent, which would otherwise ignore it.
+func Options(item models.FeedItem, rules models.DefaultRules) (map[string]string, error) {
+	opts := map[string]string{}
+	if rules.Category != "" {
+		opts["category"] = rules.Category
+	}
+	if len(rules.Tags) > 0 {
+		opts["tags"] = strings.Join(rules.Tags, ",")
+	}
+	if rules.SavePath != "" {
+		path, err := ExpandSavePath(rules.SavePath, item)
+		if err != nil {
+			return opts, err
+		}
+		opts["savepath"] = path
+		opts["autoTMM"] = "false"
+	}
+	return opts, nil
+}
@@ -0,0 +1,59 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+func TestExpandSavePath(t *testing.T) {
+	episode := models.FeedItem{ShowName: "Law & Order: SVU", Season: 3, Episode: 7, Quality: "1080P", ContentType: models.ContentTypeShow}
+	movie := models.FeedItem{ShowName: "Heat", ReleaseYear: 1995, ContentType: models.ContentTypeMovie}
+	cases := []struct {
+		tmpl string
+		item models.FeedItem
+		want string
+	}{
+		{"/media/tv/{show}/Season {season:02}", episode, "/media/tv/Law & Order SVU/Season 03"},
+		{"/media/tv/{show}/S{season:02}E{episode:02} [{quality}]", episode, "/media/tv/Law & Order SVU/S03E07 [1080P]"},
+		{"/media/movies/{title} ({year})", movie, "/media/movies/Heat (1995)"},
+		{"/media/movies/{title} ({year})", models.FeedItem{ShowName: "Heat"}, "/media/movies/Heat"},
+		{"/downloads/{content_type}/{group}", models.FeedItem{ContentType: models.ContentTypeShow, ReleaseGroup: "AC/DC"}, "/downloads/show/AC-DC"},
+	}
+	for _, tc := range cases {
+		got, err := ExpandSavePath(tc.tmpl, tc.item)
+		if err != nil || got != tc.want {
+			t.Errorf("ExpandSavePath(%q) = %q, %v; want %q", tc.tmpl, got, err, tc.want)
+		}
+	}
+}
+
+func TestValidateSavePath(t *testing.T) {
+	for _, ok := range []string{"", "/media/tv/{show}", "/m/{season:02}/{episode:3}"} {
+		if err := ValidateSavePath(ok); err != nil {
+			t.Errorf("ValidateSavePath(%q): unexpected error %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"/media/{series}", "/media/{show:02}", "/media/{show", "/media/{Show}"} {
+		if err := ValidateSavePath(bad); err == nil {
+			t.Errorf("ValidateSavePath(%q): expected error", bad)
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	item := models.FeedItem{ShowName: "Andor", Season: 2}
+	opts, err := Options(item, models.DefaultRules{Category: "tv-4k", Tags: []string{"curator", "4k"}, SavePath: "/tv/{show}/Season {season}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"category": "tv-4k", "tags": "curator,4k", "savepath": "/tv/Andor/Season 2", "autoTMM": "false"}
+	for k, v := range want {
+		if opts[k] != v {
+			t.Errorf("opts[%q] = %q, want %q", k, opts[k], v)
+		}
+	}
+	if opts, _ := Options(item, models.DefaultRules{}); len(opts) != 0 {
+		t.Errorf("no routing should leave client defaults alone, got %v", opts)
+	}
+}
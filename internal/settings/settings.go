@@ -29,6 +29,10 @@ type SchedulerSettings struct {
 	FeedCheckIntervalSecs  int  `json:"feed_check_interval_secs"`
 	FeedCheckEnabled       bool `json:"feed_check_enabled"`
 	RescoreBackfillEnabled bool `json:"rescore_backfill_enabled"`
+	// InspectTorrents makes the feed check download each matched .torrent and
+	// record its info-hash, real size and file list before staging, flagging
+	// RAR, executable, shortcut and sample-only payloads. Default false.
+	InspectTorrents bool `json:"inspect_torrents"`
 }
 
 // AlertSettings controls the alert poller and progress reporting.
@@ -44,6 +48,15 @@ type MatchSettings struct {
 	PreferredCodec  string   `json:"preferred_codec"`
 	ExcludeGroups   []string `json:"exclude_groups"`
 	PreferredGroups []string `json:"preferred_groups"`
+	// Release constraints: MaxQuality caps resolution, MinSize/MaxSize bound
+	// the size in GB per episode or movie (0 = no bound), and the source
+	// lists require or exclude source tags such as WEB-DL or HDTV. Empty
+	// values leave any watchlist defaults in place.
+	MaxQuality      string   `json:"max_quality"`
+	MinSize         float64  `json:"min_size"`
+	MaxSize         float64  `json:"max_size"`
+	AllowedSources  []string `json:"allowed_sources"`
+	ExcludedSources []string `json:"excluded_sources"`
 }
 
 // AuthSettings controls HTTP basic-auth credentials.
@@ -77,6 +90,10 @@ type AutoQueueSettings struct {
 	// prevents indefinite deferral for episodes with slow or multi-source
 	// release patterns. 0 disables the cap. Default 480 (8 h).
 	MaxHoldMins int `json:"max_hold_mins"`
+	// Upgrades lets the job queue upgrades staged for already-queued episodes
+	// under the watchlist upgrade policy. Default false (upgrades wait for
+	// review).
+	Upgrades bool `json:"upgrades"`
 	// DryRun when true makes the scheduler and on-demand runs execute selection
 	// logic without writing to the store or qBittorrent. Decisions are still
 	// recorded in the job summary. The /api/auto-queue/preview endpoint always
@@ -95,6 +112,7 @@ type EnvDefaults struct {
 	PreferredGroups       []string
 	AuthUsername          string
 	AuthPassword          string
+	InspectTorrents       bool
 }
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -105,12 +123,18 @@ const (
 	keyFeedCheckIntervalSecs   = "scheduler.feed_check_interval_secs"
 	keyFeedCheckEnabled        = "scheduler.feed_check_enabled"
 	keyRescoreBackfillEnabled  = "scheduler.rescore_backfill_enabled"
+	keyInspectTorrents         = "scheduler.inspect_torrents"
 	keyAlertPollerIntervalSecs = "alerts.alert_poller_interval_secs"
 	keyProgressInterval        = "alerts.progress_interval"
 	keyMinQuality              = "match.min_quality"
 	keyPreferredCodec          = "match.preferred_codec"
 	keyExcludeGroups           = "match.exclude_groups"
 	keyPreferredGroups         = "match.preferred_groups"
+	keyMaxQuality              = "match.max_quality"
+	keyMinSize                 = "match.min_size"
+	keyMaxSize                 = "match.max_size"
+	keyAllowedSources          = "match.allowed_sources"
+	keyExcludedSources         = "match.excluded_sources"
 	keyAuthUsername            = "auth.username"
 	keyAuthPassword            = "auth.password"
 	keyAutoQueueEnabled        = "auto_queue.enabled"
@@ -120,6 +144,7 @@ const (
 	keyAutoQueueHoldMins       = "auto_queue.hold_mins"
 	keyAutoQueueMaxHoldMins    = "auto_queue.max_hold_mins"
 	keyAutoQueueDryRun         = "auto_queue.dry_run"
+	keyAutoQueueUpgrades       = "auto_queue.upgrades"
 )
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -132,6 +157,7 @@ func hardcodedDefaults() AppSettings {
 			FeedCheckIntervalSecs:  3600,
 			FeedCheckEnabled:       true,
 			RescoreBackfillEnabled: false,
+			InspectTorrents:        false,
 		},
 		Alerts: AlertSettings{
 			AlertPollerIntervalSecs: 15,
@@ -142,6 +168,8 @@ func hardcodedDefaults() AppSettings {
 			PreferredCodec:  "x265",
 			ExcludeGroups:   []string{},
 			PreferredGroups: []string{},
+			AllowedSources:  []string{},
+			ExcludedSources: []string{},
 		},
 		Auth: AuthSettings{
 			Username: "curator",
@@ -154,6 +182,7 @@ func hardcodedDefaults() AppSettings {
 			IntervalSecs:  600,
 			HoldMins:      30,
 			MaxHoldMins:   480,
+			Upgrades:      false,
 			DryRun:        false,
 		},
 	}
@@ -244,6 +273,12 @@ func validate(s AppSettings) error {
 	if s.Alerts.ProgressInterval <= 0 {
 		return fmt.Errorf("settings: alerts.progress_interval must be > 0")
 	}
+	if s.Match.MinSize < 0 || s.Match.MaxSize < 0 {
+		return fmt.Errorf("settings: match.min_size and match.max_size must be >= 0")
+	}
+	if s.Match.MinSize > 0 && s.Match.MaxSize > 0 && s.Match.MinSize > s.Match.MaxSize {
+		return fmt.Errorf("settings: match.min_size must not exceed match.max_size")
+	}
 	return nil
 }
 
@@ -252,17 +287,25 @@ func (m *Manager) persist(s AppSettings) error {
 	type kv struct{ key, val string }
 	excJSON, _ := json.Marshal(s.Match.ExcludeGroups)
 	prefJSON, _ := json.Marshal(s.Match.PreferredGroups)
+	allowedJSON, _ := json.Marshal(s.Match.AllowedSources)
+	excludedJSON, _ := json.Marshal(s.Match.ExcludedSources)
 
 	pairs := []kv{
 		{keyFeedCheckIntervalSecs, fmt.Sprintf("%d", s.Scheduler.FeedCheckIntervalSecs)},
 		{keyFeedCheckEnabled, boolStr(s.Scheduler.FeedCheckEnabled)},
 		{keyRescoreBackfillEnabled, boolStr(s.Scheduler.RescoreBackfillEnabled)},
+		{keyInspectTorrents, boolStr(s.Scheduler.InspectTorrents)},
 		{keyAlertPollerIntervalSecs, fmt.Sprintf("%d", s.Alerts.AlertPollerIntervalSecs)},
 		{keyProgressInterval, fmt.Sprintf("%d", s.Alerts.ProgressInterval)},
 		{keyMinQuality, s.Match.MinQuality},
 		{keyPreferredCodec, s.Match.PreferredCodec},
 		{keyExcludeGroups, string(excJSON)},
 		{keyPreferredGroups, string(prefJSON)},
+		{keyMaxQuality, s.Match.MaxQuality},
+		{keyMinSize, fmt.Sprintf("%g", s.Match.MinSize)},
+		{keyMaxSize, fmt.Sprintf("%g", s.Match.MaxSize)},
+		{keyAllowedSources, string(allowedJSON)},
+		{keyExcludedSources, string(excludedJSON)},
 		{keyAuthUsername, s.Auth.Username},
 		{keyAuthPassword, s.Auth.Password},
 		{keyAutoQueueEnabled, boolStr(s.AutoQueue.Enabled)},
@@ -272,6 +315,7 @@ func (m *Manager) persist(s AppSettings) error {
 		{keyAutoQueueHoldMins, fmt.Sprintf("%d", s.AutoQueue.HoldMins)},
 		{keyAutoQueueMaxHoldMins, fmt.Sprintf("%d", s.AutoQueue.MaxHoldMins)},
 		{keyAutoQueueDryRun, boolStr(s.AutoQueue.DryRun)},
+		{keyAutoQueueUpgrades, boolStr(s.AutoQueue.Upgrades)},
 	}
 	for _, p := range pairs {
 		if err := m.store.SetSetting(p.key, p.val); err != nil {
@@ -306,6 +350,9 @@ func applyEnvDefaults(s *AppSettings, env EnvDefaults) {
 	if env.AuthPassword != "" {
 		s.Auth.Password = env.AuthPassword
 	}
+	if env.InspectTorrents {
+		s.Scheduler.InspectTorrents = true
+	}
 }
 
 func applyStoredValues(s *AppSettings, stored map[string]string) {
@@ -320,6 +367,9 @@ func applyStoredValues(s *AppSettings, stored map[string]string) {
 	if v, ok := stored[keyRescoreBackfillEnabled]; ok {
 		s.Scheduler.RescoreBackfillEnabled = v == "true"
 	}
+	if v, ok := stored[keyInspectTorrents]; ok {
+		s.Scheduler.InspectTorrents = v == "true"
+	}
 	if v, ok := stored[keyAlertPollerIntervalSecs]; ok {
 		if n := parseInt(v); n > 0 {
 			s.Alerts.AlertPollerIntervalSecs = n
@@ -348,6 +398,31 @@ func applyStoredValues(s *AppSettings, stored map[string]string) {
 			s.Match.PreferredGroups = arr
 		}
 	}
+	if v, ok := stored[keyMaxQuality]; ok {
+		s.Match.MaxQuality = v
+	}
+	if v, ok := stored[keyMinSize]; ok {
+		if f := parseFloat(v); f >= 0 {
+			s.Match.MinSize = f
+		}
+	}
+	if v, ok := stored[keyMaxSize]; ok {
+		if f := parseFloat(v); f >= 0 {
+			s.Match.MaxSize = f
+		}
+	}
+	if v, ok := stored[keyAllowedSources]; ok {
+		var arr []string
+		if json.Unmarshal([]byte(v), &arr) == nil {
+			s.Match.AllowedSources = arr
+		}
+	}
+	if v, ok := stored[keyExcludedSources]; ok {
+		var arr []string
+		if json.Unmarshal([]byte(v), &arr) == nil {
+			s.Match.ExcludedSources = arr
+		}
+	}
 	if v, ok := stored[keyAuthUsername]; ok && v != "" {
 		s.Auth.Username = v
 	}
@@ -385,6 +460,9 @@ func applyStoredValues(s *AppSettings, stored map[string]string) {
 	if v, ok := stored[keyAutoQueueDryRun]; ok {
 		s.AutoQueue.DryRun = v == "true"
 	}
+	if v, ok := stored[keyAutoQueueUpgrades]; ok {
+		s.AutoQueue.Upgrades = v == "true"
+	}
 }
 
 func parseInt(s string) int {
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+func TestFeedState_RoundTrip(t *testing.T) {
+	store, tmpDir := setupTestDB(t)
+	defer cleanupTestDB(store, tmpDir)
+
+	const url = "http://example.com/rss"
+
+	st, err := store.GetFeedState(url)
+	if err != nil {
+		t.Fatalf("GetFeedState: %v", err)
+	}
+	if st != nil {
+		t.Fatalf("expected nil state for unknown feed, got %+v", st)
+	}
+
+	if err := store.SetFeedValidators(url, `"abc"`, "Mon, 01 Jan 2024 12:00:00 GMT"); err != nil {
+		t.Fatalf("SetFeedValidators: %v", err)
+	}
+	if err := store.SetFeedValidators(url, `"def"`, ""); err != nil {
+		t.Fatalf("SetFeedValidators (update): %v", err)
+	}
+
+	st, err = store.GetFeedState(url)
+	if err != nil {
+		t.Fatalf("GetFeedState: %v", err)
+	}
+	if st == nil {
+		t.Fatal("expected state after SetFeedValidators")
+	}
+	if st.ETag != `"def"` || st.LastModified != "" {
+		t.Errorf("state = %+v, want ETag \"def\" and empty Last-Modified after upsert", st)
+	}
+}
+
+func TestFeedState_Health(t *testing.T) {
+	store, tmpDir := setupTestDB(t)
+	defer cleanupTestDB(store, tmpDir)
+
+	const url = "http://example.com/rss"
+	next := time.Now().Add(10 * time.Minute)
+
+	if err := store.RecordFeedFailure(url, "boom", 200*time.Millisecond, next); err != nil {
+		t.Fatalf("RecordFeedFailure: %v", err)
+	}
+	if err := store.RecordFeedFailure(url, "boom again", 400*time.Millisecond, next); err != nil {
+		t.Fatalf("RecordFeedFailure: %v", err)
+	}
+
+	st, err := store.GetFeedState(url)
+	if err != nil || st == nil {
+		t.Fatalf("GetFeedState: st=%v err=%v", st, err)
+	}
+	if st.ConsecutiveFailures != 2 {
+		t.Errorf("ConsecutiveFailures = %d, want 2", st.ConsecutiveFailures)
+	}
+	if st.LastError != "boom again" || st.LastErrorAt == nil {
+		t.Errorf("LastError = %q LastErrorAt = %v, want latest error recorded", st.LastError, st.LastErrorAt)
+	}
+	if st.NextAttemptAt == nil {
+		t.Error("expected NextAttemptAt to be set while failing")
+	}
+	// 200 seeds the average, then 200*0.8 + 400*0.2 = 240.
+	if st.AvgLatencyMs < 239 || st.AvgLatencyMs > 241 {
+		t.Errorf("AvgLatencyMs = %.1f, want ~240", st.AvgLatencyMs)
+	}
+
+	if err := store.RecordFeedSuccess(url, 42, 100*time.Millisecond); err != nil {
+		t.Fatalf("RecordFeedSuccess: %v", err)
+	}
+	states, err := store.ListFeedStates()
+	if err != nil {
+		t.Fatalf("ListFeedStates: %v", err)
+	}
+	if len(states) != 1 {
+		t.Fatalf("expected 1 feed state, got %d", len(states))
+	}
+	st2 := states[0]
+	if st2.ConsecutiveFailures != 0 || st2.NextAttemptAt != nil {
+		t.Errorf("after success: failures=%d next=%v, want streak and backoff cleared", st2.ConsecutiveFailures, st2.NextAttemptAt)
+	}
+	if st2.LastItemCount != 42 || st2.LastSuccessAt == nil {
+		t.Errorf("after success: items=%d lastSuccess=%v, want 42 and set", st2.LastItemCount, st2.LastSuccessAt)
+	}
+	if st2.LastError != "boom again" {
+		t.Errorf("LastError = %q, want previous error retained for diagnosis", st2.LastError)
+	}
+
+	// A 304 Not Modified keeps the last item count.
+	if err := store.RecordFeedSuccess(url, -1, 100*time.Millisecond); err != nil {
+		t.Fatalf("RecordFeedSuccess (not modified): %v", err)
+	}
+	if st3, _ := store.GetFeedState(url); st3 == nil || st3.LastItemCount != 42 {
+		t.Errorf("after not modified: state %+v, want item count 42 kept", st3)
+	}
+}
+
+func TestFeedRegistry_CRUD(t *testing.T) {
+	store, tmpDir := setupTestDB(t)
+	defer cleanupTestDB(store, tmpDir)
+
+	id, err := store.CreateFeed(models.ManagedFeed{
+		Name:             "Tracker TV",
+		URL:              "https://tracker.example/rss",
+		ContentType:      models.ContentTypeShow,
+		Enabled:          true,
+		PollIntervalMins: 30,
+		Headers:          map[string]string{"X-Api-Key": "secret"},
+		Cookies:          map[string]string{"uid": "42"},
+		Passkey:          "pk",
+	})
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+	if _, err := store.CreateFeed(models.ManagedFeed{URL: "https://tracker.example/rss"}); err == nil {
+		t.Error("expected duplicate URL to be rejected")
+	}
+
+	f, err := store.GetFeed(id)
+	if err != nil || f == nil {
+		t.Fatalf("GetFeed: f=%v err=%v", f, err)
+	}
+	if f.Name != "Tracker TV" || !f.Enabled || f.PollIntervalMins != 30 || f.Headers["X-Api-Key"] != "secret" ||
+		f.Cookies["uid"] != "42" || f.Passkey != "pk" {
+		t.Errorf("unexpected feed: %+v", f)
+	}
+
+	f.Enabled = false
+	f.ContentType = models.ContentTypeMovie
+	if err := store.UpdateFeed(*f); err != nil {
+		t.Fatalf("UpdateFeed: %v", err)
+	}
+	feeds, err := store.ListFeeds()
+	if err != nil {
+		t.Fatalf("ListFeeds: %v", err)
+	}
+	if len(feeds) != 1 || feeds[0].Enabled || feeds[0].ContentType != models.ContentTypeMovie {
+		t.Errorf("unexpected list after update: %+v", feeds)
+	}
+
+	if err := store.DeleteFeed(id); err != nil {
+		t.Fatalf("DeleteFeed: %v", err)
+	}
+	if err := store.DeleteFeed(id); err != sql.ErrNoRows {
+		t.Errorf("DeleteFeed on missing id: err = %v, want sql.ErrNoRows", err)
+	}
+	if err := store.UpdateFeed(models.ManagedFeed{ID: id, URL: "https://x.example"}); err != sql.ErrNoRows {
+		t.Errorf("UpdateFeed on missing id: err = %v, want sql.ErrNoRows", err)
+	}
+}
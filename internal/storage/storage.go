@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -44,9 +45,16 @@ type Store interface {
 	Get(id int) (*models.StagedTorrent, error)
 	List(status, query, contentType string) ([]models.StagedTorrent, error)
 	Add(torrent models.StagedTorrent) error
+	// IsStaged reports whether Add would ignore a torrent with this link or
+	// info-hash because it is already stored.
+	IsStaged(link, infoHash string) (bool, error)
 	UpdateStatus(id int, status string) error
 	// SetFailed marks a torrent status='failed' and stores the error reason.
 	SetFailed(id int, reason string) error
+	// SetSupersededBy records that upgradeID was queued to replace id.
+	SetSupersededBy(id, upgradeID int) error
+	// SetReleaseScore stores a recomputed release score.
+	SetReleaseScore(id, score int) error
 	LogActivity(torrentID int, title, action, matchReason string) error
 	GetActivity(limit int, offset int, action string) ([]models.Activity, error)
 	GetActivityCount(action string) (int, error)
@@ -59,7 +67,7 @@ type Store interface {
 	GetRawFeedItems(limit int) ([]models.RawFeedItem, error)
 	CleanupExpiredRawFeedItems() error
 	UpdateAIScore(id int, score float64, reason string, confidence float64, confidenceReason string) error
-	UpdateAfterRematch(id int, item models.FeedItem, matchReason, status string) error
+	UpdateAfterRematch(id int, item models.FeedItem, matchReason, status string, releaseScore int) error
 	// Jobs
 	CreateJob(jobType string) (int, error)
 	CompleteJob(id int, summary any) error
@@ -70,6 +78,35 @@ type Store interface {
 	// MarkStaleJobsFailed marks any job still in "running" status as "failed".
 	// Call at startup to recover from an unclean shutdown.
 	MarkStaleJobsFailed(reason string) (int64, error)
+	// Feed state
+	// GetFeedState returns the persisted fetch state for a feed URL, or nil
+	// when the feed has never been fetched successfully.
+	GetFeedState(url string) (*models.FeedState, error)
+	// SetFeedValidators upserts the ETag/Last-Modified validators for a feed URL.
+	SetFeedValidators(url, etag, lastModified string) error
+	// ListFeedStates returns the fetch state of every feed ever recorded,
+	// ordered by URL.
+	ListFeedStates() ([]models.FeedState, error)
+	// RecordFeedSuccess resets the failure streak and clears any backoff for a
+	// feed, recording the item count and folding latency into the average. A
+	// negative count (304 Not Modified) keeps the previous one.
+	RecordFeedSuccess(url string, items int, latency time.Duration) error
+	// RecordFeedFailure increments the failure streak, stores the error and
+	// sets the time before which the feed should not be fetched again.
+	RecordFeedFailure(url, errMsg string, latency time.Duration, nextAttempt time.Time) error
+	// Feed registry
+	// ListFeeds returns every registered feed ordered by id.
+	ListFeeds() ([]models.ManagedFeed, error)
+	// GetFeed returns a registered feed by id, or nil when not found.
+	GetFeed(id int) (*models.ManagedFeed, error)
+	// CreateFeed inserts a feed and returns its id. Fails when the URL is
+	// already registered.
+	CreateFeed(f models.ManagedFeed) (int, error)
+	// UpdateFeed overwrites the editable fields of feed f.ID. Returns
+	// sql.ErrNoRows when the id does not exist.
+	UpdateFeed(f models.ManagedFeed) error
+	// DeleteFeed removes a feed by id. Returns sql.ErrNoRows when not found.
+	DeleteFeed(id int) error
 	// Settings
 	GetSetting(key string) (string, error)
 	SetSetting(key, value string) error
@@ -263,19 +300,78 @@ func (s *Storage) migrate() error {
 		// Migration 14: index on activity_log action to speed up group reputation
 		// and auto_queue window stat queries.
 		`CREATE INDEX IF NOT EXISTS idx_activity_torrent_id ON activity_log(torrent_id)`,
+		// Migration 15: per-feed fetch state — HTTP cache validators used to
+		// issue conditional GETs so unchanged feeds are not re-downloaded.
+		`CREATE TABLE IF NOT EXISTS feed_state (
+			url           TEXT PRIMARY KEY,
+			etag          TEXT NOT NULL DEFAULT '',
+			last_modified TEXT NOT NULL DEFAULT '',
+			updated_at    DATETIME NOT NULL
+		)`,
+		// Migration 16: per-feed health — success/failure history, moving
+		// average latency and the backoff deadline for failing feeds.
+		`ALTER TABLE feed_state ADD COLUMN last_success_at DATETIME`,
+		`ALTER TABLE feed_state ADD COLUMN last_error_at DATETIME`,
+		`ALTER TABLE feed_state ADD COLUMN last_error TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE feed_state ADD COLUMN consecutive_failures INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE feed_state ADD COLUMN last_item_count INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE feed_state ADD COLUMN avg_latency_ms REAL NOT NULL DEFAULT 0`,
+		`ALTER TABLE feed_state ADD COLUMN next_attempt_at DATETIME`,
+		// Migration 17: managed feed registry — feeds editable at runtime via
+		// /api/feeds instead of RSS_FEED_URL / RSS_MOVIE_FEED_URL env vars.
+		`CREATE TABLE IF NOT EXISTS feeds (
+			id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+			name               TEXT NOT NULL DEFAULT '',
+			url                TEXT NOT NULL UNIQUE,
+			content_type       TEXT NOT NULL DEFAULT 'show',
+			enabled            INTEGER NOT NULL DEFAULT 1,
+			poll_interval_mins INTEGER NOT NULL DEFAULT 0,
+			headers_json       TEXT NOT NULL DEFAULT '{}',
+			created_at         DATETIME NOT NULL,
+			updated_at         DATETIME NOT NULL
+		)`,
+		// Migration 18: private-tracker credentials on registry feeds, applied
+		// to the feed fetch and to .torrent downloads.
+		`ALTER TABLE feeds ADD COLUMN cookies_json TEXT NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE feeds ADD COLUMN passkey TEXT NOT NULL DEFAULT ''`,
+		// Migration 19: upgrade_of — id of the queued torrent a higher-revision
+		// (PROPER/REPACK) release was staged to replace; 0 for ordinary items.
+		`ALTER TABLE staged_torrents ADD COLUMN upgrade_of INTEGER NOT NULL DEFAULT 0`,
+		// Migration 20: torrent_meta — JSON-encoded models.TorrentMeta from the
+		// optional .torrent inspection step; empty when not inspected.
+		`ALTER TABLE staged_torrents ADD COLUMN torrent_meta TEXT NOT NULL DEFAULT ''`,
+		// Migration 21: info_hash — normalized (lowercase hex) BitTorrent
+		// info-hash from a magnet link, Torznab attribute or .torrent
+		// inspection. Add treats a live row with the same hash as a duplicate
+		// even when it came from a different feed/link.
+		`ALTER TABLE staged_torrents ADD COLUMN info_hash TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX IF NOT EXISTS idx_staged_info_hash ON staged_torrents(info_hash)`,
+		`UPDATE staged_torrents SET info_hash = lower(json_extract(feed_item, '$.info_hash'))
+			WHERE info_hash = '' AND json_extract(feed_item, '$.info_hash') IS NOT NULL`,
+		// Migration 22: release_score — the matcher's deterministic release
+		// score (quality tier, rule preferences and custom formats) used to
+		// rank duplicate releases and auto-queue candidates.
+		`ALTER TABLE staged_torrents ADD COLUMN release_score INTEGER NOT NULL DEFAULT 0`,
+		// Migration 23: superseded_by — id of the upgrade queued to replace
+		// this torrent; the reverse link of upgrade_of.
+		`ALTER TABLE staged_torrents ADD COLUMN superseded_by INTEGER NOT NULL DEFAULT 0`,
 	}
 
 	for _, migration := range migrations {
 		if _, err := s.db.Exec(migration); err != nil {
-			// Log but don't fail - migration may have already been applied
-			fmt.Printf("Migration note: %v\n", err)
+			// Log but don't fail - migration may have already been applied.
+			// Notes go to stderr so commands with machine-readable stdout
+			// (explain --json) stay parseable.
+			fmt.Fprintf(os.Stderr, "Migration note: %v\n", err)
 		}
 	}
 
 	return nil
 }
 
-// Add adds a new staged torrent (or ignores if Link already exists)
+// Add adds a new staged torrent. It is a no-op when the Link already exists
+// or when a non-failed row carries the same info-hash (the same release
+// announced by another feed or indexer).
 func (s *Storage) Add(torrent models.StagedTorrent) error {
 	feedItemJSON, err := json.Marshal(torrent.FeedItem)
 	if err != nil {
@@ -289,14 +385,50 @@ func (s *Storage) Add(torrent models.StagedTorrent) error {
 		contentType = "show"
 	}
 
+	torrentMetaJSON := ""
+	if torrent.Torrent != nil {
+		b, err := json.Marshal(torrent.Torrent)
+		if err != nil {
+			return fmt.Errorf("failed to marshal torrent meta: %w", err)
+		}
+		torrentMetaJSON = string(b)
+	}
+
+	infoHash := torrent.FeedItem.InfoHash
+	if infoHash == "" && torrent.Torrent != nil {
+		infoHash = torrent.Torrent.InfoHash
+	}
+	infoHash = strings.ToLower(infoHash)
+
+	// Failed rows don't block: the same release from a working indexer link
+	// should still be staged.
 	_, err = s.db.Exec(`
-		INSERT OR IGNORE INTO staged_torrents (link, feed_item, match_reason, staged_at, status, ai_score, ai_reason, ai_scored, match_confidence, match_confidence_reason, content_type)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, torrent.FeedItem.Link, feedItemJSON, torrent.MatchReason, torrent.StagedAt, torrent.Status, torrent.AIScore, torrent.AIReason, torrent.AIScored, torrent.MatchConfidence, torrent.MatchConfidenceReason, contentType)
+		INSERT OR IGNORE INTO staged_torrents (link, feed_item, match_reason, staged_at, status, ai_score, ai_reason, ai_scored, match_confidence, match_confidence_reason, content_type, upgrade_of, torrent_meta, info_hash, release_score)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		WHERE ? = '' OR NOT EXISTS (
+			SELECT 1 FROM staged_torrents WHERE info_hash = ? AND status != 'failed'
+		)
+	`, torrent.FeedItem.Link, feedItemJSON, torrent.MatchReason, torrent.StagedAt, torrent.Status, torrent.AIScore, torrent.AIReason, torrent.AIScored, torrent.MatchConfidence, torrent.MatchConfidenceReason, contentType, torrent.UpgradeOf, torrentMetaJSON, infoHash, torrent.ReleaseScore,
+		infoHash, infoHash)
 
 	return err
 }
 
+// IsStaged reports whether a row with link, or a non-failed row with
+// infoHash, already exists — the same rule Add uses to ignore duplicates.
+func (s *Storage) IsStaged(link, infoHash string) (bool, error) {
+	infoHash = strings.ToLower(infoHash)
+	var n int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM staged_torrents
+		WHERE link = ? OR (? != '' AND info_hash = ? AND status != 'failed')
+	`, link, infoHash, infoHash).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to check staged torrent: %w", err)
+	}
+	return n > 0, nil
+}
+
 // List returns torrents optionally filtered by status, title substring, and/or content type.
 // Empty strings match all values for their respective filters.
 func (s *Storage) List(status, query, contentType string) ([]models.StagedTorrent, error) {
@@ -319,7 +451,7 @@ func (s *Storage) List(status, query, contentType string) ([]models.StagedTorren
 		args = append(args, contentType)
 	}
 
-	sqlStr := `SELECT id, link, feed_item, match_reason, staged_at, status, approved_at, ai_score, ai_reason, ai_scored, match_confidence, match_confidence_reason, content_type, fail_reason
+	sqlStr := `SELECT id, link, feed_item, match_reason, staged_at, status, approved_at, ai_score, ai_reason, ai_scored, match_confidence, match_confidence_reason, content_type, fail_reason, upgrade_of, torrent_meta, release_score, superseded_by
 		FROM staged_torrents`
 	if len(conds) > 0 {
 		sqlStr += " WHERE "
@@ -346,8 +478,9 @@ func (s *Storage) List(status, query, contentType string) ([]models.StagedTorren
 		var link string
 		var approvedAt sql.NullTime
 		var contentTypeDB string
+		var torrentMetaJSON string
 
-		err := rows.Scan(&t.ID, &link, &feedItemJSON, &t.MatchReason, &t.StagedAt, &t.Status, &approvedAt, &t.AIScore, &t.AIReason, &t.AIScored, &t.MatchConfidence, &t.MatchConfidenceReason, &contentTypeDB, &t.FailReason)
+		err := rows.Scan(&t.ID, &link, &feedItemJSON, &t.MatchReason, &t.StagedAt, &t.Status, &approvedAt, &t.AIScore, &t.AIReason, &t.AIScored, &t.MatchConfidence, &t.MatchConfidenceReason, &contentTypeDB, &t.FailReason, &t.UpgradeOf, &torrentMetaJSON, &t.ReleaseScore, &t.SupersededBy)
 		if err != nil {
 			return nil, err
 		}
@@ -363,6 +496,7 @@ func (s *Storage) List(status, query, contentType string) ([]models.StagedTorren
 		if approvedAt.Valid {
 			t.ApprovedAt = &approvedAt.Time
 		}
+		t.Torrent = decodeTorrentMeta(torrentMetaJSON)
 
 		torrents = append(torrents, t)
 	}
@@ -378,11 +512,12 @@ func (s *Storage) Get(id int) (*models.StagedTorrent, error) {
 	var approvedAt sql.NullTime
 
 	var contentTypeDB string
+	var torrentMetaJSON string
 	err := s.db.QueryRow(`
-		SELECT id, link, feed_item, match_reason, staged_at, status, approved_at, ai_score, ai_reason, ai_scored, match_confidence, match_confidence_reason, content_type, fail_reason
+		SELECT id, link, feed_item, match_reason, staged_at, status, approved_at, ai_score, ai_reason, ai_scored, match_confidence, match_confidence_reason, content_type, fail_reason, upgrade_of, torrent_meta, release_score, superseded_by
 		FROM staged_torrents
 		WHERE id = ?
-	`, id).Scan(&t.ID, &link, &feedItemJSON, &t.MatchReason, &t.StagedAt, &t.Status, &approvedAt, &t.AIScore, &t.AIReason, &t.AIScored, &t.MatchConfidence, &t.MatchConfidenceReason, &contentTypeDB, &t.FailReason)
+	`, id).Scan(&t.ID, &link, &feedItemJSON, &t.MatchReason, &t.StagedAt, &t.Status, &approvedAt, &t.AIScore, &t.AIReason, &t.AIScored, &t.MatchConfidence, &t.MatchConfidenceReason, &contentTypeDB, &t.FailReason, &t.UpgradeOf, &torrentMetaJSON, &t.ReleaseScore, &t.SupersededBy)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("torrent not found")
@@ -401,10 +536,24 @@ func (s *Storage) Get(id int) (*models.StagedTorrent, error) {
 	if approvedAt.Valid {
 		t.ApprovedAt = &approvedAt.Time
 	}
+	t.Torrent = decodeTorrentMeta(torrentMetaJSON)
 
 	return &t, nil
 }
 
+// decodeTorrentMeta parses a torrent_meta column value; empty or unreadable
+// values yield nil (not inspected).
+func decodeTorrentMeta(raw string) *models.TorrentMeta {
+	if raw == "" {
+		return nil
+	}
+	var meta models.TorrentMeta
+	if err := json.Unmarshal([]byte(raw), &meta); err != nil {
+		return nil
+	}
+	return &meta
+}
+
 // SetFailed marks a torrent as failed and records the error reason for display in the UI.
 func (s *Storage) SetFailed(id int, reason string) error {
 	_, err := s.db.Exec(`
@@ -415,6 +564,18 @@ func (s *Storage) SetFailed(id int, reason string) error {
 	return err
 }
 
+// SetSupersededBy links torrent id to the upgrade that replaced it.
+func (s *Storage) SetSupersededBy(id, upgradeID int) error {
+	_, err := s.db.Exec(`UPDATE staged_torrents SET superseded_by = ? WHERE id = ?`, upgradeID, id)
+	return err
+}
+
+// SetReleaseScore stores a recomputed release score for torrent id.
+func (s *Storage) SetReleaseScore(id, score int) error {
+	_, err := s.db.Exec(`UPDATE staged_torrents SET release_score = ? WHERE id = ?`, score, id)
+	return err
+}
+
 // UpdateStatus updates the status of a torrent
 func (s *Storage) UpdateStatus(id int, status string) error {
 	var approvedAt *time.Time
@@ -481,11 +642,12 @@ func (s *Storage) GetByID(id int) (*models.StagedTorrent, error) {
 	var approvedAt sql.NullTime
 
 	var contentTypeDB string
+	var torrentMetaJSON string
 	err := s.db.QueryRow(`
-		SELECT id, feed_item, match_reason, staged_at, status, approved_at, ai_score, ai_reason, ai_scored, match_confidence, match_confidence_reason, content_type, fail_reason
+		SELECT id, feed_item, match_reason, staged_at, status, approved_at, ai_score, ai_reason, ai_scored, match_confidence, match_confidence_reason, content_type, fail_reason, upgrade_of, torrent_meta, release_score, superseded_by
 		FROM staged_torrents
 		WHERE id = ?
-	`, id).Scan(&t.ID, &feedItemJSON, &t.MatchReason, &t.StagedAt, &t.Status, &approvedAt, &t.AIScore, &t.AIReason, &t.AIScored, &t.MatchConfidence, &t.MatchConfidenceReason, &contentTypeDB, &t.FailReason)
+	`, id).Scan(&t.ID, &feedItemJSON, &t.MatchReason, &t.StagedAt, &t.Status, &approvedAt, &t.AIScore, &t.AIReason, &t.AIScored, &t.MatchConfidence, &t.MatchConfidenceReason, &contentTypeDB, &t.FailReason, &t.UpgradeOf, &torrentMetaJSON, &t.ReleaseScore, &t.SupersededBy)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -504,6 +666,7 @@ func (s *Storage) GetByID(id int) (*models.StagedTorrent, error) {
 	if approvedAt.Valid {
 		t.ApprovedAt = &approvedAt.Time
 	}
+	t.Torrent = decodeTorrentMeta(torrentMetaJSON)
 
 	return &t, nil
 }
@@ -637,9 +800,10 @@ func (s *Storage) UpdateAIScore(id int, score float64, reason string, confidence
 }
 
 // UpdateAfterRematch persists the re-parsed feed item, refreshed match reason,
-// and reconciled status for an existing staged torrent. It also clears AI score
-// fields so stale prior scores are not shown when match context changed.
-func (s *Storage) UpdateAfterRematch(id int, item models.FeedItem, matchReason, status string) error {
+// release score and reconciled status for an existing staged torrent. It also
+// clears AI score fields so stale prior scores are not shown when match
+// context changed.
+func (s *Storage) UpdateAfterRematch(id int, item models.FeedItem, matchReason, status string, releaseScore int) error {
 	feedItemJSON, err := json.Marshal(item)
 	if err != nil {
 		return fmt.Errorf("failed to marshal feed item: %w", err)
@@ -649,6 +813,7 @@ func (s *Storage) UpdateAfterRematch(id int, item models.FeedItem, matchReason,
 		UPDATE staged_torrents
 		SET feed_item = ?,
 		    match_reason = ?,
+		    release_score = ?,
 		    status = ?,
 		    approved_at = CASE WHEN ? = 'accepted' THEN approved_at ELSE NULL END,
 		    ai_score = 0,
@@ -657,7 +822,7 @@ func (s *Storage) UpdateAfterRematch(id int, item models.FeedItem, matchReason,
 		    match_confidence = -1,
 		    match_confidence_reason = ''
 		WHERE id = ?
-	`, string(feedItemJSON), matchReason, status, status, id)
+	`, string(feedItemJSON), matchReason, releaseScore, status, status, id)
 	return err
 }
 
@@ -790,6 +955,242 @@ func (s *Storage) GetJob(id int) (*models.JobRecord, error) {
 	return &j, nil
 }
 
+// feedStateColumns is the column list shared by GetFeedState and
+// ListFeedStates; keep in sync with scanFeedState.
+const feedStateColumns = `url, etag, last_modified, last_success_at, last_error_at, last_error,
+	consecutive_failures, last_item_count, avg_latency_ms, next_attempt_at, updated_at`
+
+// scanFeedState scans one feed_state row selected with feedStateColumns.
+func scanFeedState(row interface{ Scan(...any) error }) (models.FeedState, error) {
+	var st models.FeedState
+	var lastSuccess, lastError, nextAttempt sql.NullTime
+	err := row.Scan(&st.URL, &st.ETag, &st.LastModified, &lastSuccess, &lastError, &st.LastError,
+		&st.ConsecutiveFailures, &st.LastItemCount, &st.AvgLatencyMs, &nextAttempt, &st.UpdatedAt)
+	if err != nil {
+		return st, err
+	}
+	if lastSuccess.Valid {
+		st.LastSuccessAt = &lastSuccess.Time
+	}
+	if lastError.Valid {
+		st.LastErrorAt = &lastError.Time
+	}
+	if nextAttempt.Valid {
+		st.NextAttemptAt = &nextAttempt.Time
+	}
+	return st, nil
+}
+
+// GetFeedState returns the persisted fetch state for url, or nil when no row
+// exists.
+func (s *Storage) GetFeedState(url string) (*models.FeedState, error) {
+	st, err := scanFeedState(s.db.QueryRow(
+		`SELECT `+feedStateColumns+` FROM feed_state WHERE url = ?`, url,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetFeedState: %w", err)
+	}
+	return &st, nil
+}
+
+// ListFeedStates returns every persisted feed state ordered by URL.
+func (s *Storage) ListFeedStates() ([]models.FeedState, error) {
+	rows, err := s.db.Query(`SELECT ` + feedStateColumns + ` FROM feed_state ORDER BY url`)
+	if err != nil {
+		return nil, fmt.Errorf("ListFeedStates: query: %w", err)
+	}
+	defer rows.Close()
+
+	out := []models.FeedState{}
+	for rows.Next() {
+		st, err := scanFeedState(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListFeedStates: scan: %w", err)
+		}
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
+
+// feedLatencyAlpha is the weight given to the newest sample in the feed
+// latency moving average.
+const feedLatencyAlpha = 0.2
+
+// RecordFeedSuccess clears the failure streak and backoff for url and folds
+// latency into the moving average. The first sample seeds the average. A
+// negative items count means the feed was unchanged and keeps the last count.
+func (s *Storage) RecordFeedSuccess(url string, items int, latency time.Duration) error {
+	now := time.Now()
+	ms := float64(latency.Milliseconds())
+	_, err := s.db.Exec(
+		`INSERT INTO feed_state(url, last_success_at, consecutive_failures, last_item_count, avg_latency_ms, next_attempt_at, updated_at)
+		 VALUES(?, ?, 0, MAX(?, 0), ?, NULL, ?)
+		 ON CONFLICT(url) DO UPDATE SET
+		     last_success_at      = excluded.last_success_at,
+		     consecutive_failures = 0,
+		     last_item_count      = CASE WHEN ? < 0 THEN feed_state.last_item_count ELSE excluded.last_item_count END,
+		     avg_latency_ms       = CASE WHEN feed_state.avg_latency_ms = 0 THEN excluded.avg_latency_ms
+		                                 ELSE feed_state.avg_latency_ms * (1 - ?) + excluded.avg_latency_ms * ? END,
+		     next_attempt_at      = NULL,
+		     updated_at           = excluded.updated_at`,
+		url, now, items, ms, now, items, feedLatencyAlpha, feedLatencyAlpha,
+	)
+	return err
+}
+
+// RecordFeedFailure increments the failure streak for url, stores errMsg and
+// sets next_attempt_at. Latency still feeds the moving average so slow
+// timeouts are visible.
+func (s *Storage) RecordFeedFailure(url, errMsg string, latency time.Duration, nextAttempt time.Time) error {
+	now := time.Now()
+	ms := float64(latency.Milliseconds())
+	_, err := s.db.Exec(
+		`INSERT INTO feed_state(url, last_error_at, last_error, consecutive_failures, avg_latency_ms, next_attempt_at, updated_at)
+		 VALUES(?, ?, ?, 1, ?, ?, ?)
+		 ON CONFLICT(url) DO UPDATE SET
+		     last_error_at        = excluded.last_error_at,
+		     last_error           = excluded.last_error,
+		     consecutive_failures = feed_state.consecutive_failures + 1,
+		     avg_latency_ms       = CASE WHEN feed_state.avg_latency_ms = 0 THEN excluded.avg_latency_ms
+		                                 ELSE feed_state.avg_latency_ms * (1 - ?) + excluded.avg_latency_ms * ? END,
+		     next_attempt_at      = excluded.next_attempt_at,
+		     updated_at           = excluded.updated_at`,
+		url, now, errMsg, ms, nextAttempt, now, feedLatencyAlpha, feedLatencyAlpha,
+	)
+	return err
+}
+
+// SetFeedValidators upserts the HTTP cache validators for url.
+func (s *Storage) SetFeedValidators(url, etag, lastModified string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO feed_state(url, etag, last_modified, updated_at) VALUES(?, ?, ?, ?)
+		 ON CONFLICT(url) DO UPDATE SET etag = excluded.etag, last_modified = excluded.last_modified, updated_at = excluded.updated_at`,
+		url, etag, lastModified, time.Now(),
+	)
+	return err
+}
+
+// feedColumns is the column list shared by ListFeeds and GetFeed; keep in
+// sync with scanFeed.
+const feedColumns = `id, name, url, content_type, enabled, poll_interval_mins, headers_json, cookies_json, passkey, created_at, updated_at`
+
+// scanFeed scans one feeds row selected with feedColumns.
+func scanFeed(row interface{ Scan(...any) error }) (models.ManagedFeed, error) {
+	var f models.ManagedFeed
+	var contentType, headersJSON, cookiesJSON string
+	err := row.Scan(&f.ID, &f.Name, &f.URL, &contentType, &f.Enabled, &f.PollIntervalMins,
+		&headersJSON, &cookiesJSON, &f.Passkey, &f.CreatedAt, &f.UpdatedAt)
+	if err != nil {
+		return f, err
+	}
+	f.ContentType = models.ContentType(contentType)
+	if err := json.Unmarshal([]byte(headersJSON), &f.Headers); err != nil {
+		return f, fmt.Errorf("headers_json: %w", err)
+	}
+	if err := json.Unmarshal([]byte(cookiesJSON), &f.Cookies); err != nil {
+		return f, fmt.Errorf("cookies_json: %w", err)
+	}
+	if f.Headers == nil {
+		f.Headers = map[string]string{}
+	}
+	if f.Cookies == nil {
+		f.Cookies = map[string]string{}
+	}
+	return f, nil
+}
+
+// ListFeeds returns every registered feed ordered by id.
+func (s *Storage) ListFeeds() ([]models.ManagedFeed, error) {
+	rows, err := s.db.Query(`SELECT ` + feedColumns + ` FROM feeds ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("ListFeeds: query: %w", err)
+	}
+	defer rows.Close()
+
+	out := []models.ManagedFeed{}
+	for rows.Next() {
+		f, err := scanFeed(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListFeeds: scan: %w", err)
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+// GetFeed returns the feed with the given id, or nil when not found.
+func (s *Storage) GetFeed(id int) (*models.ManagedFeed, error) {
+	f, err := scanFeed(s.db.QueryRow(`SELECT `+feedColumns+` FROM feeds WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetFeed: %w", err)
+	}
+	return &f, nil
+}
+
+// stringMapJSON marshals m for a *_json column, storing "{}" for nil.
+func stringMapJSON(m map[string]string) string {
+	if m == nil {
+		return "{}"
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+// CreateFeed inserts f and returns the new row id.
+func (s *Storage) CreateFeed(f models.ManagedFeed) (int, error) {
+	now := time.Now()
+	res, err := s.db.Exec(
+		`INSERT INTO feeds(name, url, content_type, enabled, poll_interval_mins, headers_json, cookies_json, passkey, created_at, updated_at)
+		 VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		f.Name, f.URL, string(f.ContentType), f.Enabled, f.PollIntervalMins,
+		stringMapJSON(f.Headers), stringMapJSON(f.Cookies), f.Passkey, now, now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("CreateFeed: %w", err)
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+// UpdateFeed overwrites the editable fields of the feed with id f.ID.
+func (s *Storage) UpdateFeed(f models.ManagedFeed) error {
+	res, err := s.db.Exec(
+		`UPDATE feeds SET name = ?, url = ?, content_type = ?, enabled = ?, poll_interval_mins = ?,
+		     headers_json = ?, cookies_json = ?, passkey = ?, updated_at = ?
+		 WHERE id = ?`,
+		f.Name, f.URL, string(f.ContentType), f.Enabled, f.PollIntervalMins,
+		stringMapJSON(f.Headers), stringMapJSON(f.Cookies), f.Passkey, time.Now(), f.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("UpdateFeed: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteFeed removes the feed with the given id.
+func (s *Storage) DeleteFeed(id int) error {
+	res, err := s.db.Exec(`DELETE FROM feeds WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("DeleteFeed: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 // GetSetting retrieves a single runtime setting by key. Returns "", nil when the key does not exist.
 func (s *Storage) GetSetting(key string) (string, error) {
 	var value string
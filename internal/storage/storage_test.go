@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -85,6 +86,142 @@ func TestGetTorrent(t *testing.T) {
 	}
 }
 
+func TestTorrentMetaRoundTrip(t *testing.T) {
+	store, tmpDir := setupTestDB(t)
+	defer cleanupTestDB(store, tmpDir)
+
+	plain := createTestTorrent()
+	inspected := createTestTorrent()
+	inspected.FeedItem.Link = "http://example.com/inspected.torrent"
+	inspected.UpgradeOf = 1
+	inspected.Torrent = &models.TorrentMeta{
+		InfoHash:  "0123456789abcdef0123456789abcdef01234567",
+		TotalSize: 42,
+		FileCount: 1,
+		Files:     []models.TorrentFile{{Path: "show.rar", Size: 42}},
+		Flags:     []string{models.TorrentFlagRAR},
+	}
+	for _, tr := range []models.StagedTorrent{plain, inspected} {
+		if err := store.Add(tr); err != nil {
+			t.Fatalf("failed to add torrent: %v", err)
+		}
+	}
+
+	got, err := store.Get(1)
+	if err != nil {
+		t.Fatalf("failed to get torrent: %v", err)
+	}
+	if got.Torrent != nil {
+		t.Errorf("uninspected torrent: Torrent = %+v, want nil", got.Torrent)
+	}
+
+	got, err = store.GetByID(2)
+	if err != nil || got == nil {
+		t.Fatalf("failed to get inspected torrent: %v", err)
+	}
+	if got.UpgradeOf != 1 {
+		t.Errorf("UpgradeOf = %d, want 1", got.UpgradeOf)
+	}
+
+	if err := store.SetSupersededBy(1, 2); err != nil {
+		t.Fatalf("SetSupersededBy: %v", err)
+	}
+	if orig, _ := store.Get(1); orig == nil || orig.SupersededBy != 2 {
+		t.Errorf("original not linked to its upgrade: %+v", orig)
+	}
+	if got.Torrent == nil || got.Torrent.InfoHash != inspected.Torrent.InfoHash || !got.Torrent.HasFlag(models.TorrentFlagRAR) {
+		t.Errorf("Torrent = %+v, want %+v", got.Torrent, inspected.Torrent)
+	}
+}
+
+func TestReleaseScoreRoundTrip(t *testing.T) {
+	store, tmpDir := setupTestDB(t)
+	defer cleanupTestDB(store, tmpDir)
+
+	torrent := createTestTorrent()
+	torrent.ReleaseScore = 57
+	if err := store.Add(torrent); err != nil {
+		t.Fatalf("failed to add torrent: %v", err)
+	}
+	got, err := store.GetByID(1)
+	if err != nil || got == nil {
+		t.Fatalf("failed to get torrent: %v", err)
+	}
+	if got.ReleaseScore != 57 {
+		t.Errorf("release score = %d, want 57", got.ReleaseScore)
+	}
+
+	if err := store.UpdateAfterRematch(got.ID, got.FeedItem, "matches show: Test", "pending", 23); err != nil {
+		t.Fatalf("failed to update after rematch: %v", err)
+	}
+	list, err := store.List("", "", "")
+	if err != nil || len(list) != 1 {
+		t.Fatalf("list: %v (%d rows)", err, len(list))
+	}
+	if list[0].ReleaseScore != 23 {
+		t.Errorf("release score after rematch = %d, want 23", list[0].ReleaseScore)
+	}
+}
+
+func TestAddDedupsByInfoHash(t *testing.T) {
+	store, tmpDir := setupTestDB(t)
+	defer cleanupTestDB(store, tmpDir)
+
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+	first := createTestTorrent()
+	first.FeedItem.InfoHash = hash
+	if err := store.Add(first); err != nil {
+		t.Fatalf("failed to add torrent: %v", err)
+	}
+
+	// Same release announced by another indexer: different link, same hash
+	// (upper-cased here to exercise normalization).
+	other := createTestTorrent()
+	other.FeedItem.Link = "magnet:?xt=urn:btih:" + strings.ToUpper(hash)
+	other.FeedItem.InfoHash = strings.ToUpper(hash)
+	if err := store.Add(other); err != nil {
+		t.Fatalf("failed to add torrent: %v", err)
+	}
+	all, _ := store.List("", "", "")
+	if len(all) != 1 {
+		t.Fatalf("expected 1 torrent after duplicate hash, got %d", len(all))
+	}
+	if staged, err := store.IsStaged(other.FeedItem.Link, other.FeedItem.InfoHash); err != nil || !staged {
+		t.Errorf("IsStaged(duplicate hash) = %v, %v; want true", staged, err)
+	}
+	if staged, _ := store.IsStaged(first.FeedItem.Link, ""); !staged {
+		t.Error("IsStaged(existing link) = false, want true")
+	}
+	if staged, _ := store.IsStaged("http://example.com/new.torrent", ""); staged {
+		t.Error("IsStaged(new link) = true, want false")
+	}
+
+	// A failed row no longer blocks the hash.
+	if err := store.UpdateStatus(all[0].ID, "failed"); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+	if err := store.Add(other); err != nil {
+		t.Fatalf("failed to add torrent: %v", err)
+	}
+	all, _ = store.List("", "", "")
+	if len(all) != 2 {
+		t.Errorf("expected 2 torrents once the first failed, got %d", len(all))
+	}
+
+	// Items without a hash are only deduplicated by link.
+	for _, link := range []string{"http://example.com/a.torrent", "http://example.com/b.torrent"} {
+		tr := createTestTorrent()
+		tr.FeedItem.Link = link
+		if err := store.Add(tr); err != nil {
+			t.Fatalf("failed to add torrent: %v", err)
+		}
+	}
+	all, _ = store.List("", "", "")
+	if len(all) != 4 {
+		t.Errorf("expected 4 torrents, got %d", len(all))
+	}
+}
+
 func TestUpdateStatus(t *testing.T) {
 	store, tmpDir := setupTestDB(t)
 	defer cleanupTestDB(store, tmpDir)
@@ -0,0 +1,117 @@
+package torrentfile
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// maxDepth bounds list/dict nesting so a hostile file cannot exhaust the stack.
+const maxDepth = 64
+
+var errTruncated = errors.New("bencode: unexpected end of data")
+
+// decoder is a minimal bencode reader. Strings decode to string, integers to
+// int64, lists to []any and dictionaries to map[string]any. When infoKey is
+// reached in the top-level dictionary the raw bytes of its value are kept in
+// rawInfo so the info-hash can be computed over the exact encoding.
+type decoder struct {
+	data    []byte
+	pos     int
+	rawInfo []byte
+}
+
+func (d *decoder) value(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("bencode: nesting deeper than %d", maxDepth)
+	}
+	if d.pos >= len(d.data) {
+		return nil, errTruncated
+	}
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		return d.integer()
+	case c == 'l':
+		d.pos++
+		var list []any
+		for {
+			if d.pos >= len(d.data) {
+				return nil, errTruncated
+			}
+			if d.data[d.pos] == 'e' {
+				d.pos++
+				return list, nil
+			}
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+	case c == 'd':
+		d.pos++
+		dict := make(map[string]any)
+		for {
+			if d.pos >= len(d.data) {
+				return nil, errTruncated
+			}
+			if d.data[d.pos] == 'e' {
+				d.pos++
+				return dict, nil
+			}
+			key, err := d.str()
+			if err != nil {
+				return nil, fmt.Errorf("bencode: dictionary key: %w", err)
+			}
+			start := d.pos
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if depth == 0 && key == "info" {
+				d.rawInfo = d.data[start:d.pos]
+			}
+			dict[key] = v
+		}
+	case c >= '0' && c <= '9':
+		return d.str()
+	default:
+		return nil, fmt.Errorf("bencode: unexpected byte %q at offset %d", c, d.pos)
+	}
+}
+
+func (d *decoder) integer() (int64, error) {
+	end := d.pos + 1
+	for end < len(d.data) && d.data[end] != 'e' {
+		end++
+	}
+	if end >= len(d.data) {
+		return 0, errTruncated
+	}
+	n, err := strconv.ParseInt(string(d.data[d.pos+1:end]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bencode: integer at offset %d: %w", d.pos, err)
+	}
+	d.pos = end + 1
+	return n, nil
+}
+
+func (d *decoder) str() (string, error) {
+	colon := d.pos
+	for colon < len(d.data) && d.data[colon] != ':' {
+		colon++
+	}
+	if colon >= len(d.data) {
+		return "", errTruncated
+	}
+	n, err := strconv.Atoi(string(d.data[d.pos:colon]))
+	if err != nil || n < 0 {
+		return "", fmt.Errorf("bencode: string length at offset %d", d.pos)
+	}
+	start := colon + 1
+	if n > len(d.data)-start {
+		return "", errTruncated
+	}
+	d.pos = start + n
+	return string(d.data[start:d.pos]), nil
+}
@@ -0,0 +1,225 @@
+// Package torrentfile downloads and inspects .torrent metainfo files: it
+// decodes the bencoded info dictionary to recover the info-hash, the real
+// payload size and the file list, and flags suspicious contents.
+package torrentfile
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/killakam3084/rss-curator/internal/feed"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// MaxBytes bounds .torrent downloads.
+const MaxBytes = 20 << 20
+
+// maxListedFiles caps how many entries are kept in TorrentMeta.Files so a
+// torrent with thousands of files does not bloat the staged row.
+const maxListedFiles = 500
+
+// Fetch downloads a .torrent with feed credentials applied (auth may be the
+// zero value) and checks that the body looks like a bencoded dictionary
+// rather than an HTML login page.
+func Fetch(ctx context.Context, url string, auth models.FeedAuth) ([]byte, error) {
+	req, err := feed.NewRequest(url, auth)
+	if err != nil {
+		return nil, fmt.Errorf("download torrent: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("download torrent: %w", RedactURLError(err, url))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download torrent: unexpected status code: %d", resp.StatusCode)
+	}
+	buf, err := io.ReadAll(io.LimitReader(resp.Body, MaxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("download torrent: %w", err)
+	}
+	if len(buf) == 0 || buf[0] != 'd' {
+		return nil, fmt.Errorf("download torrent: response is not a .torrent file (content-type %q); check the feed's cookies/passkey",
+			resp.Header.Get("Content-Type"))
+	}
+	return buf, nil
+}
+
+// FileURL returns the file:// link for a local .torrent, the Link given to
+// items picked up from a watch directory.
+func FileURL(path string) string {
+	return (&neturl.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// IsFileURL reports whether link refers to a local .torrent.
+func IsFileURL(link string) bool {
+	return strings.HasPrefix(link, "file://")
+}
+
+// ReadFileURL reads the local .torrent behind a file:// link with the same
+// size bound and sanity check as Fetch. The file must resolve, symlinks
+// included, to a path inside dir; an empty dir refuses every link.
+func ReadFileURL(link, dir string) ([]byte, error) {
+	u, err := neturl.Parse(link)
+	if err != nil || u.Scheme != "file" {
+		return nil, fmt.Errorf("read torrent: not a file:// link: %s", link)
+	}
+	if dir == "" {
+		return nil, fmt.Errorf("read torrent: file:// links are only accepted from a watch directory")
+	}
+	p, err := filepath.EvalSymlinks(filepath.FromSlash(u.Path))
+	if err != nil {
+		return nil, fmt.Errorf("read torrent: %w", err)
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read torrent: %w", err)
+	}
+	if rel, err := filepath.Rel(root, p); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("read torrent: %s is outside %s", u.Path, dir)
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("read torrent: %w", err)
+	}
+	defer f.Close()
+	buf, err := io.ReadAll(io.LimitReader(f, MaxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read torrent: %w", err)
+	}
+	if len(buf) == 0 || buf[0] != 'd' {
+		return nil, fmt.Errorf("read torrent: %s is not a .torrent file", u.Path)
+	}
+	return buf, nil
+}
+
+// RedactURLError replaces the (passkey-expanded) URL in a *url.Error with the
+// unexpanded template.
+func RedactURLError(err error, rawURL string) error {
+	var ue *neturl.Error
+	if errors.As(err, &ue) {
+		ue.URL = rawURL
+	}
+	return err
+}
+
+// Inspect decodes a .torrent file and returns its metainfo summary with
+// content flags applied.
+func Inspect(data []byte) (models.TorrentMeta, error) {
+	d := &decoder{data: data}
+	root, err := d.value(0)
+	if err != nil {
+		return models.TorrentMeta{}, err
+	}
+	top, ok := root.(map[string]any)
+	if !ok {
+		return models.TorrentMeta{}, errors.New("torrent: top level is not a dictionary")
+	}
+	info, ok := top["info"].(map[string]any)
+	if !ok || d.rawInfo == nil {
+		return models.TorrentMeta{}, errors.New("torrent: missing info dictionary")
+	}
+
+	sum := sha1.Sum(d.rawInfo)
+	meta := models.TorrentMeta{InfoHash: hex.EncodeToString(sum[:])}
+	meta.Name, _ = info["name"].(string)
+
+	var paths []string
+	if files, ok := info["files"].([]any); ok {
+		// Multi-file torrent: paths are lists of components under Name.
+		for _, f := range files {
+			entry, ok := f.(map[string]any)
+			if !ok {
+				continue
+			}
+			size, _ := entry["length"].(int64)
+			var parts []string
+			if comps, ok := entry["path"].([]any); ok {
+				for _, c := range comps {
+					if s, ok := c.(string); ok {
+						parts = append(parts, s)
+					}
+				}
+			}
+			paths = append(paths, strings.Join(parts, "/"))
+			addFile(&meta, paths[len(paths)-1], size)
+		}
+	} else {
+		size, _ := info["length"].(int64)
+		paths = append(paths, meta.Name)
+		addFile(&meta, meta.Name, size)
+	}
+
+	meta.Flags = contentFlags(paths)
+	return meta, nil
+}
+
+func addFile(m *models.TorrentMeta, p string, size int64) {
+	m.TotalSize += size
+	m.FileCount++
+	if len(m.Files) < maxListedFiles {
+		m.Files = append(m.Files, models.TorrentFile{Path: p, Size: size})
+	}
+}
+
+// Extension sets used by contentFlags.
+var (
+	executableExts = map[string]bool{".exe": true, ".msi": true, ".bat": true, ".cmd": true, ".scr": true, ".vbs": true, ".ps1": true}
+	videoExts      = map[string]bool{".mkv": true, ".mp4": true, ".avi": true, ".m2ts": true, ".ts": true, ".wmv": true, ".mov": true, ".m4v": true}
+)
+
+// contentFlags inspects file paths for signs of a packed, fake or malicious
+// release: RAR volumes (.rar, .r00…), executables, Windows shortcuts, and
+// payloads whose only video files are samples.
+func contentFlags(paths []string) []string {
+	var rar, exe, lnk bool
+	videos, samples := 0, 0
+	for _, p := range paths {
+		lower := strings.ToLower(p)
+		ext := path.Ext(lower)
+		switch {
+		case ext == ".rar" || isRarVolume(ext):
+			rar = true
+		case executableExts[ext]:
+			exe = true
+		case ext == ".lnk":
+			lnk = true
+		case videoExts[ext]:
+			videos++
+			if strings.Contains(lower, "sample") {
+				samples++
+			}
+		}
+	}
+
+	var flags []string
+	if rar {
+		flags = append(flags, models.TorrentFlagRAR)
+	}
+	if exe {
+		flags = append(flags, models.TorrentFlagExecutable)
+	}
+	if lnk {
+		flags = append(flags, models.TorrentFlagLNK)
+	}
+	if videos > 0 && samples == videos && !rar {
+		flags = append(flags, models.TorrentFlagSampleOnly)
+	}
+	return flags
+}
+
+// isRarVolume reports whether ext is an old-style RAR continuation volume
+// (.r00 – .r99).
+func isRarVolume(ext string) bool {
+	return len(ext) == 4 && ext[1] == 'r' && ext[2] >= '0' && ext[2] <= '9' && ext[3] >= '0' && ext[3] <= '9'
+}
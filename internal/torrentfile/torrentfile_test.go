@@ -0,0 +1,103 @@
+package torrentfile
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+const singleInfo = "d6:lengthi1048576e4:name27:Show.S01E01.1080p-GROUP.mkv12:piece lengthi262144e6:pieces0:e"
+
+const multiInfo = "d5:filesl" +
+	"d6:lengthi100e4:pathl6:Sample10:sample.mkveed" +
+	"6:lengthi500e4:pathl8:show.rareed" +
+	"6:lengthi500e4:pathl8:show.r00eed" +
+	"6:lengthi10e4:pathl9:Codec.exeee" +
+	"e4:name11:Show.S01E01e"
+
+func TestInspect_SingleFile(t *testing.T) {
+	data := []byte("d8:announce14:http://tracker4:info" + singleInfo + "e")
+	meta, err := Inspect(data)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+
+	sum := sha1.Sum([]byte(singleInfo))
+	if want := hex.EncodeToString(sum[:]); meta.InfoHash != want {
+		t.Errorf("InfoHash = %s, want %s", meta.InfoHash, want)
+	}
+	if meta.Name != "Show.S01E01.1080p-GROUP.mkv" || meta.TotalSize != 1048576 || meta.FileCount != 1 {
+		t.Errorf("meta = %+v", meta)
+	}
+	if len(meta.Flags) != 0 {
+		t.Errorf("Flags = %v, want none", meta.Flags)
+	}
+}
+
+func TestInspect_MultiFileFlags(t *testing.T) {
+	meta, err := Inspect([]byte("d4:info" + multiInfo + "e"))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if meta.FileCount != 4 || meta.TotalSize != 1110 {
+		t.Errorf("FileCount/TotalSize = %d/%d, want 4/1110", meta.FileCount, meta.TotalSize)
+	}
+	if meta.Files[0].Path != "Sample/sample.mkv" {
+		t.Errorf("Files[0].Path = %q, want Sample/sample.mkv", meta.Files[0].Path)
+	}
+	// The only video is a sample, but the payload is in RAR volumes, so the
+	// release is not "sample only".
+	want := []string{models.TorrentFlagRAR, models.TorrentFlagExecutable}
+	if strings.Join(meta.Flags, ",") != strings.Join(want, ",") {
+		t.Errorf("Flags = %v, want %v", meta.Flags, want)
+	}
+}
+
+func TestContentFlags(t *testing.T) {
+	cases := []struct {
+		paths []string
+		want  string
+	}{
+		{[]string{"Show/show.mkv", "Show/Sample/sample.mkv"}, ""},
+		{[]string{"Show/Sample/show.sample.mkv", "Show/show.nfo"}, models.TorrentFlagSampleOnly},
+		{[]string{"Show/Show.mkv.lnk"}, models.TorrentFlagLNK},
+		{[]string{"Show/show.part01.rar", "Show/show.r01"}, models.TorrentFlagRAR},
+	}
+	for _, tc := range cases {
+		if got := strings.Join(contentFlags(tc.paths), ","); got != tc.want {
+			t.Errorf("contentFlags(%v) = %q, want %q", tc.paths, got, tc.want)
+		}
+	}
+}
+
+func TestInspect_Invalid(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"<html>login</html>",
+		"d4:info" + singleInfo,      // truncated
+		"d8:announce3:urle",         // no info dict
+		"d4:infod4:name999:shortee", // string length past end
+	} {
+		if _, err := Inspect([]byte(data)); err == nil {
+			t.Errorf("Inspect(%q): expected error", data)
+		}
+	}
+}
+
+func TestFetch_RejectsHTML(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html>please log in</html>"))
+	}))
+	defer srv.Close()
+
+	if _, err := Fetch(context.Background(), srv.URL, models.FeedAuth{}); err == nil || !strings.Contains(err.Error(), "not a .torrent file") {
+		t.Errorf("Fetch error = %v, want not-a-torrent error", err)
+	}
+}
@@ -0,0 +1,239 @@
+// Package watchdir is a feed.FeedSource backed by a local directory: .torrent
+// files, .magnet files and exported RSS/Atom XML dropped into it become
+// FeedItems, so manually grabbed torrents are matched, scored and staged like
+// anything pulled from an indexer.
+package watchdir
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/killakam3084/rss-curator/internal/feed"
+	"github.com/killakam3084/rss-curator/internal/torrentfile"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+const (
+	processedDir = "processed"
+	failedDir    = "failed"
+)
+
+// Source watches one directory. Each pickup moves the file into processed/
+// (or failed/ when it cannot be read) so a file is turned into items exactly
+// once; .torrent items link to their processed/ copy via a file:// URL,
+// which the qBittorrent client uploads from disk.
+type Source struct {
+	dir         string
+	contentType models.ContentType // "" infers show vs movie per item
+	parser      *feed.Parser
+	// minAge skips files modified more recently than this so a file still
+	// being written is picked up on the next run instead.
+	minAge time.Duration
+}
+
+// New returns a watch-directory source. contentType applies to every item;
+// pass "" to infer it per item (a season/episode or air date means show,
+// anything else is parsed as a movie).
+func New(dir string, contentType models.ContentType) *Source {
+	return &Source{
+		dir:         dir,
+		contentType: contentType,
+		parser:      feed.NewParser(),
+		minAge:      2 * time.Second,
+	}
+}
+
+// ID identifies the source in feed health tracking.
+func (s *Source) ID() string { return "watch:" + s.dir }
+
+// ProcessedDir returns the processed/ directory of the watch directory that
+// feedURL (a Source ID, as stored in FeedItem.FeedURL) identifies, or false
+// when feedURL is not a watch directory.
+func ProcessedDir(feedURL string) (string, bool) {
+	dir, ok := strings.CutPrefix(feedURL, "watch:")
+	if !ok || dir == "" {
+		return "", false
+	}
+	return filepath.Join(dir, processedDir), true
+}
+
+// Fetch picks up every settled file in the directory and returns its items.
+// A missing directory is an error; unreadable files are moved to failed/ and
+// logged without failing the run.
+func (s *Source) Fetch(ctx context.Context, _ feed.Validators) (*feed.FetchResult, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("watch dir: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var items []models.FeedItem
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		if e.IsDir() || !supported(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) < s.minAge {
+			continue
+		}
+		got, err := s.pickup(e.Name(), info.ModTime())
+		if err != nil {
+			fmt.Printf("[WatchDir] WARNING: %s: %v\n", e.Name(), err)
+			if _, mvErr := s.moveTo(e.Name(), failedDir); mvErr != nil {
+				fmt.Printf("[WatchDir] WARNING: could not move %s to %s/: %v\n", e.Name(), failedDir, mvErr)
+			}
+			continue
+		}
+		items = append(items, got...)
+	}
+
+	for i := range items {
+		items[i].FeedURL = s.ID()
+	}
+	return &feed.FetchResult{Items: items}, nil
+}
+
+// supported reports whether name has an extension the source understands.
+func supported(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".torrent", ".magnet", ".xml", ".rss":
+		return true
+	}
+	return false
+}
+
+// pickup parses one file and moves it to processed/.
+func (s *Source) pickup(name string, modTime time.Time) ([]models.FeedItem, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.FeedItem
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".torrent":
+		meta, err := torrentfile.Inspect(data)
+		if err != nil {
+			return nil, err
+		}
+		dest, err := s.moveTo(name, processedDir)
+		if err != nil {
+			return nil, err
+		}
+		title := meta.Name
+		if title == "" {
+			title = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+		items = append(items, s.newItem(models.FeedItem{
+			Title:    title,
+			Link:     torrentfile.FileURL(dest),
+			GUID:     "watch:" + meta.InfoHash,
+			PubDate:  modTime,
+			Size:     meta.TotalSize,
+			InfoHash: meta.InfoHash,
+			Torrent:  &meta,
+		}))
+		return items, nil
+
+	case ".magnet":
+		sc := bufio.NewScanner(bytes.NewReader(data))
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			m, err := feed.ParseMagnet(line)
+			if err != nil {
+				return nil, err
+			}
+			title := m.Name
+			if title == "" {
+				title = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			items = append(items, s.newItem(models.FeedItem{
+				Title:    title,
+				Link:     line,
+				GUID:     "watch:" + m.InfoHash,
+				PubDate:  modTime,
+				InfoHash: m.InfoHash,
+			}))
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("no magnet links found")
+		}
+
+	default: // exported RSS/Atom
+		ct := s.contentType
+		if ct == "" {
+			ct = models.ContentTypeShow
+		}
+		parsed, err := s.parser.ParseDocument(data, ct)
+		if err != nil {
+			return nil, err
+		}
+		for _, it := range parsed {
+			if s.contentType == "" {
+				inferContentType(&it)
+			}
+			items = append(items, it)
+		}
+	}
+
+	if _, err := s.moveTo(name, processedDir); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// newItem applies the content type and title metadata to a file-derived item.
+func (s *Source) newItem(item models.FeedItem) models.FeedItem {
+	if s.contentType != "" {
+		item.ContentType = s.contentType
+		feed.ParseTitleMetadata(&item)
+	} else {
+		inferContentType(&item)
+	}
+	return item
+}
+
+// inferContentType parses item as a show and falls back to movie parsing when
+// the title carries no season, episode or air date.
+func inferContentType(item *models.FeedItem) {
+	item.ContentType = models.ContentTypeShow
+	feed.ParseTitleMetadata(item)
+	if item.Season > 0 || item.AirDate != "" {
+		return
+	}
+	item.ContentType = models.ContentTypeMovie
+	feed.ParseTitleMetadata(item)
+}
+
+// moveTo relocates name into sub/, prefixing a timestamp when a file of the
+// same name was picked up before, and returns the absolute destination path.
+func (s *Source) moveTo(name, sub string) (string, error) {
+	dir := filepath.Join(s.dir, sub)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	dest := filepath.Join(dir, name)
+	if _, err := os.Stat(dest); err == nil {
+		dest = filepath.Join(dir, time.Now().UTC().Format("20060102T150405")+"-"+name)
+	}
+	if err := os.Rename(filepath.Join(s.dir, name), dest); err != nil {
+		return "", err
+	}
+	return filepath.Abs(dest)
+}
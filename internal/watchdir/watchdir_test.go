@@ -0,0 +1,137 @@
+package watchdir
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/killakam3084/rss-curator/internal/feed"
+	"github.com/killakam3084/rss-curator/internal/torrentfile"
+	"github.com/killakam3084/rss-curator/pkg/models"
+)
+
+// singleFileTorrent returns a minimal bencoded single-file .torrent.
+func singleFileTorrent(name string, length int) []byte {
+	info := fmt.Sprintf("d6:lengthi%de4:name%d:%s12:piece lengthi16384e6:pieces20:%se",
+		length, len(name), name, strings.Repeat("x", 20))
+	return []byte("d8:announce23:http://tracker.test/ann4:info" + info + "e")
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "grab.torrent", singleFileTorrent("Show.Name.S01E02.1080p.WEB-DL-GRP.mkv", 4096))
+	writeFile(t, dir, "links.magnet", []byte(
+		"# manual grabs\n"+
+			"magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&dn=Some.Movie.2023.1080p.BluRay.x264-GRP\n"))
+	writeFile(t, dir, "export.xml", []byte(`<?xml version="1.0"?>
+<rss version="2.0"><channel>
+<item><title>Other.Show.S03E04.720p.HDTV-GRP</title><link>http://tracker.test/dl/9</link></item>
+</channel></rss>`))
+	writeFile(t, dir, "broken.torrent", []byte("<html>login</html>"))
+	writeFile(t, dir, "notes.txt", []byte("ignored"))
+
+	src := New(dir, "")
+	src.minAge = 0
+	var _ feed.FeedSource = src
+
+	res, err := src.Fetch(context.Background(), feed.Validators{})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	byTitle := map[string]models.FeedItem{}
+	for _, it := range res.Items {
+		byTitle[it.Title] = it
+		if it.FeedURL != src.ID() {
+			t.Errorf("%s: FeedURL = %q, want %q", it.Title, it.FeedURL, src.ID())
+		}
+	}
+	if len(res.Items) != 3 {
+		t.Fatalf("expected 3 items, got %d: %+v", len(res.Items), res.Items)
+	}
+
+	tor := byTitle["Show.Name.S01E02.1080p.WEB-DL-GRP.mkv"]
+	if tor.ContentType != models.ContentTypeShow || tor.Season != 1 || tor.Episode != 2 || tor.Size != 4096 || len(tor.InfoHash) != 40 {
+		t.Errorf("torrent item = %+v", tor)
+	}
+	if tor.Torrent == nil || tor.Torrent.InfoHash != tor.InfoHash {
+		t.Errorf("torrent item carries no decoded metainfo: %+v", tor.Torrent)
+	}
+	if !torrentfile.IsFileURL(tor.Link) {
+		t.Errorf("torrent Link = %q, want file:// URL", tor.Link)
+	} else if processed, ok := ProcessedDir(tor.FeedURL); !ok {
+		t.Errorf("ProcessedDir(%q) not recognised", tor.FeedURL)
+	} else if _, err := torrentfile.ReadFileURL(tor.Link, processed); err != nil {
+		t.Errorf("processed torrent not readable: %v", err)
+	}
+	if _, err := torrentfile.ReadFileURL(tor.Link, t.TempDir()); err == nil {
+		t.Error("file:// link outside the allowed directory was read")
+	}
+	if _, err := torrentfile.ReadFileURL(tor.Link, ""); err == nil {
+		t.Error("file:// link read without an allowed directory")
+	}
+
+	mag := byTitle["Some.Movie.2023.1080p.BluRay.x264-GRP"]
+	if mag.ContentType != models.ContentTypeMovie || mag.ReleaseYear != 2023 || !feed.IsMagnet(mag.Link) {
+		t.Errorf("magnet item = %+v", mag)
+	}
+
+	xmlItem := byTitle["Other.Show.S03E04.720p.HDTV-GRP"]
+	if xmlItem.ContentType != models.ContentTypeShow || xmlItem.Season != 3 || xmlItem.Link != "http://tracker.test/dl/9" {
+		t.Errorf("xml item = %+v", xmlItem)
+	}
+
+	for _, name := range []string{"grab.torrent", "links.magnet", "export.xml"} {
+		if _, err := os.Stat(filepath.Join(dir, processedDir, name)); err != nil {
+			t.Errorf("%s not moved to processed/: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, failedDir, "broken.torrent")); err != nil {
+		t.Errorf("broken.torrent not moved to failed/: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Errorf("unsupported file should be left alone: %v", err)
+	}
+
+	// Files are consumed: a second run yields nothing.
+	res, err = src.Fetch(context.Background(), feed.Validators{})
+	if err != nil || len(res.Items) != 0 {
+		t.Errorf("second Fetch = %d items, err %v; want 0", len(res.Items), err)
+	}
+}
+
+func TestFetch_PinnedContentTypeAndSettling(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.magnet", []byte("magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&dn=Daily.Show.2026.10.15.720p.WEB-GRP\n"))
+
+	// Freshly written files are left for the next run.
+	src := New(dir, models.ContentTypeMovie)
+	res, err := src.Fetch(context.Background(), feed.Validators{})
+	if err != nil || len(res.Items) != 0 {
+		t.Fatalf("unsettled Fetch = %+v, err %v; want no items", res, err)
+	}
+
+	src.minAge = 0
+	res, err = src.Fetch(context.Background(), feed.Validators{})
+	if err != nil || len(res.Items) != 1 {
+		t.Fatalf("Fetch = %+v, err %v; want 1 item", res, err)
+	}
+	if res.Items[0].ContentType != models.ContentTypeMovie {
+		t.Errorf("ContentType = %q, want pinned movie", res.Items[0].ContentType)
+	}
+}
+
+func TestFetch_MissingDir(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "nope"), "").Fetch(context.Background(), feed.Validators{}); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -17,6 +18,93 @@ const (
 type FeedConfig struct {
 	URL         string      `yaml:"url"`
 	ContentType ContentType `yaml:"content_type"`
+	// Name is a display label; empty for env-configured feeds.
+	Name string `yaml:"name"`
+	// PollIntervalMins, when > 0, is the minimum number of minutes between
+	// fetches of this feed. Zero fetches it on every feed-check run.
+	PollIntervalMins int `yaml:"poll_interval_mins"`
+	// Headers and Cookies are sent on the feed fetch and on .torrent
+	// downloads from this feed; see FeedAuth.
+	Headers map[string]string `yaml:"headers"`
+	Cookies map[string]string `yaml:"cookies"`
+	Passkey string            `yaml:"passkey"`
+}
+
+// Auth returns the request credentials configured for this feed.
+func (f FeedConfig) Auth() FeedAuth {
+	return FeedAuth{Headers: f.Headers, Cookies: f.Cookies, Passkey: f.Passkey}
+}
+
+// FeedAuth carries the credentials a private-tracker feed needs on both the
+// RSS fetch and the .torrent download. "{passkey}" in the URL, header values
+// and cookie values is replaced with Passkey at request time.
+type FeedAuth struct {
+	Headers map[string]string
+	Cookies map[string]string
+	Passkey string
+	// LocalDir is the only directory file:// links may be read from. It is
+	// set for watch-directory items; file:// links are refused without it.
+	LocalDir string
+}
+
+// IsZero reports whether no credentials are configured.
+func (a FeedAuth) IsZero() bool {
+	return len(a.Headers) == 0 && len(a.Cookies) == 0 && a.Passkey == ""
+}
+
+// Expand substitutes the {passkey} placeholder in s.
+func (a FeedAuth) Expand(s string) string {
+	if a.Passkey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, "{passkey}", a.Passkey)
+}
+
+// ManagedFeed is a feed stored in the runtime-editable feed registry.
+type ManagedFeed struct {
+	ID               int               `json:"id"`
+	Name             string            `json:"name"`
+	URL              string            `json:"url"`
+	ContentType      ContentType       `json:"content_type"`
+	Enabled          bool              `json:"enabled"`
+	PollIntervalMins int               `json:"poll_interval_mins"`
+	Headers          map[string]string `json:"headers"`
+	Cookies          map[string]string `json:"cookies"`
+	Passkey          string            `json:"passkey"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// FeedConfig converts a registry entry into the FeedConfig consumed by a
+// feed-check run.
+func (f ManagedFeed) FeedConfig() FeedConfig {
+	return FeedConfig{
+		URL:              f.URL,
+		ContentType:      f.ContentType,
+		Name:             f.Name,
+		PollIntervalMins: f.PollIntervalMins,
+		Headers:          f.Headers,
+		Cookies:          f.Cookies,
+		Passkey:          f.Passkey,
+	}
+}
+
+// FeedState is the persisted per-feed fetch state and health record, keyed by
+// feed URL. ETag and LastModified are the cache validators from the last 200
+// response and are replayed as If-None-Match / If-Modified-Since on the next
+// fetch. NextAttemptAt is set while a repeatedly failing feed is backing off.
+type FeedState struct {
+	URL                 string     `json:"url"`
+	ETag                string     `json:"etag,omitempty"`
+	LastModified        string     `json:"last_modified,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastErrorAt         *time.Time `json:"last_error_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastItemCount       int        `json:"last_item_count"`
+	AvgLatencyMs        float64    `json:"avg_latency_ms"` // exponential moving average
+	NextAttemptAt       *time.Time `json:"next_attempt_at,omitempty"`
+	UpdatedAt           time.Time  `json:"updated_at"`
 }
 
 // FeedItem represents a parsed RSS feed item
@@ -33,12 +121,51 @@ type FeedItem struct {
 	ShowName     string      `json:"show_name"`
 	Season       int         `json:"season"`
 	Episode      int         `json:"episode"`
+	EpisodeEnd   int         `json:"episode_end,omitempty"`    // last episode of a multi-episode release (S01E01-E03 → 3)
+	IsSeasonPack bool        `json:"is_season_pack,omitempty"` // season given without an episode (Show.S02.1080p)
+	AirDate      string      `json:"air_date,omitempty"`       // YYYY-MM-DD for date-based shows (Show.2026.10.15)
+	Revision     int         `json:"revision,omitempty"`       // 0 = original; PROPER/REPACK/RERIP = 1, REPACK2 = 2, each REAL adds 1
 	ReleaseYear  int         `json:"release_year,omitempty"`
 	Quality      string      `json:"quality"`
 	Codec        string      `json:"codec"`
 	Source       string      `json:"source"`
 	ReleaseGroup string      `json:"release_group"`
 	HDR          []string    `json:"hdr,omitempty"`
+
+	// Release attributes parsed from the title after the show/movie name.
+	Audio         []string `json:"audio,omitempty"`          // canonical formats, sorted: "atmos", "ddp", "truehd", "dts-hd", "aac", ...
+	AudioChannels string   `json:"audio_channels,omitempty"` // "5.1", "7.1", "2.0"
+	BitDepth      int      `json:"bit_depth,omitempty"`      // 10 for 10bit/Hi10P; 0 when not stated
+	Edition       string   `json:"edition,omitempty"`        // "extended", "directors_cut", "imax", "unrated", ...
+	Languages     []string `json:"languages,omitempty"`      // "multi", "dual", "french", "german", ...
+	Subbed        bool     `json:"subbed,omitempty"`
+	Dubbed        bool     `json:"dubbed,omitempty"`
+
+	// FeedURL is the (unexpanded) URL of the feed the item came from. Used to
+	// look up the feed's credentials when the .torrent link is downloaded.
+	FeedURL string `json:"feed_url,omitempty"`
+
+	// Indexer attributes (Torznab/Newznab <torznab:attr> elements). Pointer
+	// fields are nil when the feed did not report them, so a reported zero
+	// (a dead swarm, a freeleech factor of 0) stays distinguishable from absent.
+	Seeders              *int     `json:"seeders,omitempty"`
+	Peers                *int     `json:"peers,omitempty"`
+	InfoHash             string   `json:"info_hash,omitempty"`
+	Categories           []int    `json:"categories,omitempty"`
+	DownloadVolumeFactor *float64 `json:"download_volume_factor,omitempty"` // 0 = freeleech, 0.5 = half-leech
+	IMDBID               string   `json:"imdb_id,omitempty"`                // normalised "tt0903747"
+	TVDBID               int      `json:"tvdb_id,omitempty"`
+
+	// Torrent is the decoded metainfo when the source already read the
+	// .torrent itself (the watch directory). MatchAll carries it onto the
+	// StagedTorrent so inspection does not decode the file again.
+	Torrent *TorrentMeta `json:"-"`
+}
+
+// IsFreeleech reports whether the indexer marked the item as not counting
+// against download ratio (downloadvolumefactor == 0).
+func (f FeedItem) IsFreeleech() bool {
+	return f.DownloadVolumeFactor != nil && *f.DownloadVolumeFactor == 0
 }
 
 // StagedTorrent represents a torrent waiting for approval
@@ -60,6 +187,58 @@ type StagedTorrent struct {
 	// FailReason holds the error message from the last failed qBittorrent add attempt.
 	// Non-empty only when Status == "failed".
 	FailReason string `json:"fail_reason,omitempty"`
+	// UpgradeOf is the ID of an already-queued torrent for the same episode
+	// that this strictly better release (higher tier, preferred HDR or higher
+	// revision) would replace. Zero for ordinary staged items.
+	UpgradeOf int `json:"upgrade_of,omitempty"`
+	// SupersededBy is the ID of the upgrade that was queued to replace this
+	// torrent. Zero while this is still the current release of its episode.
+	SupersededBy int `json:"superseded_by,omitempty"`
+	// Torrent holds the decoded .torrent metainfo when the feed check's
+	// inspection step is enabled; nil when the item was not inspected.
+	Torrent *TorrentMeta `json:"torrent,omitempty"`
+	// ReleaseScore is the matcher's deterministic release score: quality
+	// tier, the rule's codec/HDR/group/attribute preferences and the weights
+	// of matching custom formats. Dedup and auto-queue rank by it.
+	ReleaseScore int `json:"release_score"`
+}
+
+// TorrentMeta is what the .torrent inspection step learned from an item's
+// bencoded metainfo.
+type TorrentMeta struct {
+	InfoHash  string        `json:"info_hash"` // lowercase hex SHA-1 of the info dictionary
+	Name      string        `json:"name"`
+	TotalSize int64         `json:"total_size"`
+	FileCount int           `json:"file_count"`
+	Files     []TorrentFile `json:"files,omitempty"` // may be truncated for very large torrents; FileCount is exact
+	Flags     []string      `json:"flags,omitempty"` // TorrentFlag* values
+}
+
+// TorrentFile is one entry in a torrent's file list.
+type TorrentFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// Content flags raised by .torrent inspection.
+const (
+	TorrentFlagRAR        = "rar"         // payload is packed in RAR volumes
+	TorrentFlagExecutable = "executable"  // contains .exe/.msi/.bat/... files
+	TorrentFlagLNK        = "lnk"         // contains Windows shortcut files
+	TorrentFlagSampleOnly = "sample_only" // the only video files are samples
+)
+
+// HasFlag reports whether inspection raised flag.
+func (m *TorrentMeta) HasFlag(flag string) bool {
+	if m == nil {
+		return false
+	}
+	for _, f := range m.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
 }
 
 // RawFeedItem represents a raw item pulled from RSS feed (before filtering/matching)
@@ -83,30 +262,115 @@ type Activity struct {
 
 // ShowRule represents rules for a specific show
 type ShowRule struct {
-	Name            string   `json:"name"`
+	Name string `json:"name"`
+	// Aliases are alternate titles the show is released under; they
+	// match like Name. The watchlist enrich job merges provider alternate
+	// titles in.
+	Aliases []string `json:"aliases,omitempty"`
+	// TitleRegex and TitleGlob match the raw release title (case-insensitive)
+	// as an alternative to Name/Aliases; Name still labels the rule.
+	TitleRegex string `json:"title_regex,omitempty"`
+	TitleGlob  string `json:"title_glob,omitempty"`
+	// MustContain requires at least one of its keywords in the raw title and
+	// MustNotContain rejects on any of them. Keywords match whole words,
+	// case-insensitively; empty lists fall back to the defaults.
+	MustContain     []string `json:"must_contain,omitempty"`
+	MustNotContain  []string `json:"must_not_contain,omitempty"`
 	MinQuality      string   `json:"min_quality,omitempty"`
 	PreferredCodec  string   `json:"preferred_codec,omitempty"`
 	PreferredGroups []string `json:"preferred_groups,omitempty"`
 	PreferredHDR    []string `json:"preferred_hdr,omitempty"`
 	ExcludeGroups   []string `json:"exclude_groups,omitempty"`
+	// Release constraints. MaxQuality caps resolution; MinSize/MaxSize bound
+	// the size in GB per episode (season packs are exempt); sources compare against the title's source tags (WEB-DL,
+	// BluRay, HDTV, ...). Unset fields fall back to the defaults.
+	MaxQuality      string   `json:"max_quality,omitempty"`
+	MinSize         float64  `json:"min_size,omitempty"`
+	MaxSize         float64  `json:"max_size,omitempty"`
+	AllowedSources  []string `json:"allowed_sources,omitempty"`
+	ExcludedSources []string `json:"excluded_sources,omitempty"`
+	AttributeRules
+	// Season and episode bounds. FromSeason/FromEpisode skip everything
+	// before SxxEyy (FromEpisode applies within FromSeason), OnlySeasons
+	// limits matches to the listed seasons and SkipSpecials drops S00. Bounds
+	// other than SkipSpecials ignore specials and date-based episodes.
+	FromSeason   int   `json:"from_season,omitempty"`
+	FromEpisode  int   `json:"from_episode,omitempty"`
+	OnlySeasons  []int `json:"only_seasons,omitempty"`
+	SkipSpecials bool  `json:"skip_specials,omitempty"`
+	// Profile names a QualityProfile this rule inherits unset fields from.
+	Profile string `json:"profile,omitempty"`
+	// MinScore rejects releases whose release score (see CustomFormat) is
+	// below it; nil falls back to the profile/defaults, then no minimum.
+	MinScore *int `json:"min_score,omitempty"`
+	// Download routing applied when the torrent is queued; unset fields fall
+	// back to the profile/defaults, then to the qBittorrent client settings.
+	// SavePath is a template such as "/media/tv/{show}/Season {season:02}".
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	SavePath string   `json:"save_path,omitempty"`
+	// Upgrade policy for episodes that were already queued. UpgradesAllowed
+	// nil falls back to the profile/defaults, then allowed. UpgradeCutoff
+	// stops quality upgrades once the queued release reaches it, and
+	// UpgradeWindowDays only considers upgrades that many days after the
+	// original was staged (0 = no limit).
+	UpgradesAllowed   *bool  `json:"upgrades_allowed,omitempty"`
+	UpgradeCutoff     string `json:"upgrade_cutoff,omitempty"`
+	UpgradeWindowDays int    `json:"upgrade_window_days,omitempty"`
 	// AutoQueue controls whether the auto-queue job may select and queue torrents
 	// for this show without human review. nil means "use the global default".
 	AutoQueue *bool `json:"auto_queue,omitempty"`
 }
 
-// MovieRule represents rules for a specific movie (mirrors ShowRule).
+// MovieRule represents rules for a specific movie. Fields shared with
+// ShowRule behave and fall back exactly as documented there; the season and
+// episode bounds have no movie counterpart.
 type MovieRule struct {
-	Name            string   `json:"name"`
+	Name       string   `json:"name"`
+	Aliases    []string `json:"aliases,omitempty"` // see ShowRule.Aliases
+	TitleRegex string   `json:"title_regex,omitempty"`
+	TitleGlob  string   `json:"title_glob,omitempty"`
+	// Keyword filters, quality preferences and release constraints; see
+	// ShowRule. MinSize/MaxSize bound the size per movie.
+	MustContain     []string `json:"must_contain,omitempty"`
+	MustNotContain  []string `json:"must_not_contain,omitempty"`
 	MinQuality      string   `json:"min_quality,omitempty"`
 	PreferredCodec  string   `json:"preferred_codec,omitempty"`
 	PreferredGroups []string `json:"preferred_groups,omitempty"`
 	PreferredHDR    []string `json:"preferred_hdr,omitempty"`
 	ExcludeGroups   []string `json:"exclude_groups,omitempty"`
+	MaxQuality      string   `json:"max_quality,omitempty"`
+	MinSize         float64  `json:"min_size,omitempty"`
+	MaxSize         float64  `json:"max_size,omitempty"`
+	AllowedSources  []string `json:"allowed_sources,omitempty"`
+	ExcludedSources []string `json:"excluded_sources,omitempty"`
+	AttributeRules
+	// Profile, score minimum, download routing and upgrade policy; see
+	// ShowRule.
+	Profile           string   `json:"profile,omitempty"`
+	MinScore          *int     `json:"min_score,omitempty"`
+	Category          string   `json:"category,omitempty"`
+	Tags              []string `json:"tags,omitempty"`
+	SavePath          string   `json:"save_path,omitempty"`
+	UpgradesAllowed   *bool    `json:"upgrades_allowed,omitempty"`
+	UpgradeCutoff     string   `json:"upgrade_cutoff,omitempty"`
+	UpgradeWindowDays int      `json:"upgrade_window_days,omitempty"`
+	// RetireAfter moves the rule to the watchlist archive once the movie has
+	// been acquired: "queued" on the first queued release, "cutoff" once a
+	// release at or above the effective upgrade_cutoff is queued (any queued
+	// release when no cutoff is set). Empty keeps the rule forever.
+	RetireAfter string `json:"retire_after,omitempty"`
 	// AutoQueue controls whether the auto-queue job may select and queue torrents
 	// for this movie without human review. nil means "use the global default".
 	AutoQueue *bool `json:"auto_queue,omitempty"`
 }
 
+// MovieRule.RetireAfter values.
+const (
+	RetireAfterQueued = "queued"
+	RetireAfterCutoff = "cutoff"
+)
+
 // DefaultRules represents default matching rules
 type DefaultRules struct {
 	MinQuality      string   `json:"min_quality"`
@@ -114,13 +378,302 @@ type DefaultRules struct {
 	PreferredGroups []string `json:"preferred_groups"`
 	PreferredHDR    []string `json:"preferred_hdr,omitempty"`
 	ExcludeGroups   []string `json:"exclude_groups"`
+	// Keyword filters applied to rules that set none of their own.
+	MustContain    []string `json:"must_contain,omitempty"`
+	MustNotContain []string `json:"must_not_contain,omitempty"`
+	// Release constraints applied to rules that leave them unset.
+	MaxQuality      string   `json:"max_quality,omitempty"`
+	MinSize         float64  `json:"min_size,omitempty"`
+	MaxSize         float64  `json:"max_size,omitempty"`
+	AllowedSources  []string `json:"allowed_sources,omitempty"`
+	ExcludedSources []string `json:"excluded_sources,omitempty"`
+	// MinScore is the release-score floor for rules that set none.
+	MinScore *int `json:"min_score,omitempty"`
+	// Download routing (qBittorrent category, tags, save-path template) for
+	// rules that set none.
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	SavePath string   `json:"save_path,omitempty"`
+	// Upgrade policy for rules that set none.
+	UpgradesAllowed   *bool  `json:"upgrades_allowed,omitempty"`
+	UpgradeCutoff     string `json:"upgrade_cutoff,omitempty"`
+	UpgradeWindowDays int    `json:"upgrade_window_days,omitempty"`
+	AttributeRules
+}
+
+// ReleaseConstraints is the quality-ceiling, size and source slice of a
+// ShowRule, MovieRule or DefaultRules. Sizes are in GB.
+type ReleaseConstraints struct {
+	MaxQuality      string
+	MinSize         float64
+	MaxSize         float64
+	AllowedSources  []string
+	ExcludedSources []string
+}
+
+// Constraints returns the rule's release constraints.
+func (r ShowRule) Constraints() ReleaseConstraints {
+	return ReleaseConstraints{
+		MaxQuality: r.MaxQuality, MinSize: r.MinSize, MaxSize: r.MaxSize,
+		AllowedSources: r.AllowedSources, ExcludedSources: r.ExcludedSources,
+	}
+}
+
+// Constraints returns the rule's release constraints.
+func (r MovieRule) Constraints() ReleaseConstraints {
+	return ReleaseConstraints{
+		MaxQuality: r.MaxQuality, MinSize: r.MinSize, MaxSize: r.MaxSize,
+		AllowedSources: r.AllowedSources, ExcludedSources: r.ExcludedSources,
+	}
+}
+
+// Constraints returns the default release constraints.
+func (d DefaultRules) Constraints() ReleaseConstraints {
+	return ReleaseConstraints{
+		MaxQuality: d.MaxQuality, MinSize: d.MinSize, MaxSize: d.MaxSize,
+		AllowedSources: d.AllowedSources, ExcludedSources: d.ExcludedSources,
+	}
+}
+
+// WithDefaults fills every unset field of c from defaults.
+func (c ReleaseConstraints) WithDefaults(defaults ReleaseConstraints) ReleaseConstraints {
+	if c.MaxQuality == "" {
+		c.MaxQuality = defaults.MaxQuality
+	}
+	if c.MinSize == 0 {
+		c.MinSize = defaults.MinSize
+	}
+	if c.MaxSize == 0 {
+		c.MaxSize = defaults.MaxSize
+	}
+	if len(c.AllowedSources) == 0 {
+		c.AllowedSources = defaults.AllowedSources
+	}
+	if len(c.ExcludedSources) == 0 {
+		c.ExcludedSources = defaults.ExcludedSources
+	}
+	return c
+}
+
+// AttributeRules holds the release-attribute preferences embedded in
+// ShowRule, MovieRule and DefaultRules. Preferred values add a match reason
+// and an auto-queue bonus; required values reject items that lack them.
+// Languages accept "original" (not dubbed, and untagged or multi/dual) and
+// "subbed" alongside language names.
+type AttributeRules struct {
+	PreferredAudio     []string `json:"preferred_audio,omitempty"`
+	RequiredAudio      []string `json:"required_audio,omitempty"`
+	PreferredBitDepth  int      `json:"preferred_bit_depth,omitempty"`
+	RequiredBitDepth   int      `json:"required_bit_depth,omitempty"`
+	PreferredEditions  []string `json:"preferred_editions,omitempty"`
+	RequiredEditions   []string `json:"required_editions,omitempty"`
+	PreferredLanguages []string `json:"preferred_languages,omitempty"`
+	RequiredLanguages  []string `json:"required_languages,omitempty"`
+}
+
+// WithDefaults fills every unset field of a from defaults, mirroring how the
+// other per-rule fields fall back to DefaultRules.
+func (a AttributeRules) WithDefaults(defaults AttributeRules) AttributeRules {
+	if len(a.PreferredAudio) == 0 {
+		a.PreferredAudio = defaults.PreferredAudio
+	}
+	if len(a.RequiredAudio) == 0 {
+		a.RequiredAudio = defaults.RequiredAudio
+	}
+	if a.PreferredBitDepth == 0 {
+		a.PreferredBitDepth = defaults.PreferredBitDepth
+	}
+	if a.RequiredBitDepth == 0 {
+		a.RequiredBitDepth = defaults.RequiredBitDepth
+	}
+	if len(a.PreferredEditions) == 0 {
+		a.PreferredEditions = defaults.PreferredEditions
+	}
+	if len(a.RequiredEditions) == 0 {
+		a.RequiredEditions = defaults.RequiredEditions
+	}
+	if len(a.PreferredLanguages) == 0 {
+		a.PreferredLanguages = defaults.PreferredLanguages
+	}
+	if len(a.RequiredLanguages) == 0 {
+		a.RequiredLanguages = defaults.RequiredLanguages
+	}
+	return a
 }
 
 // ShowsConfig represents the watchlist.json structure
 type ShowsConfig struct {
-	Shows    []ShowRule   `json:"shows"`
-	Movies   []MovieRule  `json:"movies"`
-	Defaults DefaultRules `json:"defaults"`
+	Shows         []ShowRule       `json:"shows"`
+	Movies        []MovieRule      `json:"movies"`
+	Profiles      []QualityProfile `json:"profiles,omitempty"`
+	CustomFormats []CustomFormat   `json:"custom_formats,omitempty"`
+	Defaults      DefaultRules     `json:"defaults"`
+	// ArchivedShows and ArchivedMovies hold retired rules. The matcher
+	// ignores them; moving an entry back into Shows/Movies restores it.
+	ArchivedShows  []ArchivedShow  `json:"archived_shows,omitempty"`
+	ArchivedMovies []ArchivedMovie `json:"archived_movies,omitempty"`
+}
+
+// ArchivedShow is a show rule retired from the watchlist, with when and why.
+type ArchivedShow struct {
+	ShowRule
+	ArchivedAt    time.Time `json:"archived_at"`
+	ArchiveReason string    `json:"archive_reason"`
+}
+
+// ArchivedMovie is a movie rule retired from the watchlist, with when and why.
+type ArchivedMovie struct {
+	MovieRule
+	ArchivedAt    time.Time `json:"archived_at"`
+	ArchiveReason string    `json:"archive_reason"`
+}
+
+// CustomFormat is a named condition set over a FeedItem's parsed fields with
+// a signed weight, in the style of the *arr tools: a release matching every
+// condition has Weight added to its release score.
+type CustomFormat struct {
+	Name       string            `json:"name"`
+	Weight     int               `json:"weight"`
+	Conditions []FormatCondition `json:"conditions"`
+}
+
+// FormatCondition tests one FeedItem field. Text fields (quality, codec,
+// source, group, hdr, audio, edition, language) match when the item carries
+// any of Values, case-insensitively; "title" treats Values as regular
+// expressions. Numeric fields (size in GB, revision, bit_depth, seeders)
+// match when the value lies within Min/Max (0 = unbounded); "freeleech"
+// matches freeleech items. Negate inverts the result.
+type FormatCondition struct {
+	Field  string   `json:"field"`
+	Values []string `json:"values,omitempty"`
+	Min    float64  `json:"min,omitempty"`
+	Max    float64  `json:"max,omitempty"`
+	Negate bool     `json:"negate,omitempty"`
+}
+
+// QualityProfile is a named set of rule settings ("4K-HDR", "Sitcom-720p")
+// defined once in the watchlist. A rule that names it in Profile inherits
+// every field it leaves unset from the profile, and anything the profile
+// leaves unset from Defaults.
+type QualityProfile struct {
+	Name string `json:"name"`
+	DefaultRules
+}
+
+// Profile returns the profile with the given name (case-insensitive), or nil.
+func (c *ShowsConfig) Profile(name string) *QualityProfile {
+	if c == nil || name == "" {
+		return nil
+	}
+	for i := range c.Profiles {
+		if strings.EqualFold(c.Profiles[i].Name, name) {
+			return &c.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// RuleDefaults returns the fallback rules for a rule referencing profile:
+// the profile's fields over Defaults. An empty or unknown profile yields
+// Defaults unchanged.
+func (c *ShowsConfig) RuleDefaults(profile string) DefaultRules {
+	if c == nil {
+		return DefaultRules{}
+	}
+	if p := c.Profile(profile); p != nil {
+		return p.DefaultRules.WithFallback(c.Defaults)
+	}
+	return c.Defaults
+}
+
+// WithFallback fills every unset field of d from fallback.
+func (d DefaultRules) WithFallback(fallback DefaultRules) DefaultRules {
+	if d.MinQuality == "" {
+		d.MinQuality = fallback.MinQuality
+	}
+	if d.PreferredCodec == "" {
+		d.PreferredCodec = fallback.PreferredCodec
+	}
+	if len(d.PreferredGroups) == 0 {
+		d.PreferredGroups = fallback.PreferredGroups
+	}
+	if len(d.PreferredHDR) == 0 {
+		d.PreferredHDR = fallback.PreferredHDR
+	}
+	if len(d.ExcludeGroups) == 0 {
+		d.ExcludeGroups = fallback.ExcludeGroups
+	}
+	if len(d.MustContain) == 0 {
+		d.MustContain = fallback.MustContain
+	}
+	if len(d.MustNotContain) == 0 {
+		d.MustNotContain = fallback.MustNotContain
+	}
+	if d.MinScore == nil {
+		d.MinScore = fallback.MinScore
+	}
+	if d.Category == "" {
+		d.Category = fallback.Category
+	}
+	if len(d.Tags) == 0 {
+		d.Tags = fallback.Tags
+	}
+	if d.SavePath == "" {
+		d.SavePath = fallback.SavePath
+	}
+	if d.UpgradesAllowed == nil {
+		d.UpgradesAllowed = fallback.UpgradesAllowed
+	}
+	if d.UpgradeCutoff == "" {
+		d.UpgradeCutoff = fallback.UpgradeCutoff
+	}
+	if d.UpgradeWindowDays == 0 {
+		d.UpgradeWindowDays = fallback.UpgradeWindowDays
+	}
+
+	d.setConstraints(d.Constraints().WithDefaults(fallback.Constraints()))
+	d.AttributeRules = d.AttributeRules.WithDefaults(fallback.AttributeRules)
+	return d
+}
+
+func (d *DefaultRules) setConstraints(c ReleaseConstraints) {
+	d.MaxQuality, d.MinSize, d.MaxSize = c.MaxQuality, c.MinSize, c.MaxSize
+	d.AllowedSources, d.ExcludedSources = c.AllowedSources, c.ExcludedSources
+}
+
+// AllowsUpgrades reports whether the upgrade policy permits upgrades; an
+// unset UpgradesAllowed means yes.
+func (d DefaultRules) AllowsUpgrades() bool {
+	return d.UpgradesAllowed == nil || *d.UpgradesAllowed
+}
+
+// Settings returns the rule's own matching fields as DefaultRules, so the
+// effective rule is r.Settings().WithFallback(cfg.RuleDefaults(r.Profile)).
+func (r ShowRule) Settings() DefaultRules {
+	d := DefaultRules{
+		MinQuality: r.MinQuality, PreferredCodec: r.PreferredCodec,
+		PreferredGroups: r.PreferredGroups, PreferredHDR: r.PreferredHDR, ExcludeGroups: r.ExcludeGroups,
+		MustContain: r.MustContain, MustNotContain: r.MustNotContain, MinScore: r.MinScore,
+		Category: r.Category, Tags: r.Tags, SavePath: r.SavePath,
+		UpgradesAllowed: r.UpgradesAllowed, UpgradeCutoff: r.UpgradeCutoff, UpgradeWindowDays: r.UpgradeWindowDays,
+		AttributeRules: r.AttributeRules,
+	}
+	d.setConstraints(r.Constraints())
+	return d
+}
+
+// Settings returns the rule's own matching fields as DefaultRules.
+func (r MovieRule) Settings() DefaultRules {
+	d := DefaultRules{
+		MinQuality: r.MinQuality, PreferredCodec: r.PreferredCodec,
+		PreferredGroups: r.PreferredGroups, PreferredHDR: r.PreferredHDR, ExcludeGroups: r.ExcludeGroups,
+		MustContain: r.MustContain, MustNotContain: r.MustNotContain, MinScore: r.MinScore,
+		Category: r.Category, Tags: r.Tags, SavePath: r.SavePath,
+		UpgradesAllowed: r.UpgradesAllowed, UpgradeCutoff: r.UpgradeCutoff, UpgradeWindowDays: r.UpgradeWindowDays,
+		AttributeRules: r.AttributeRules,
+	}
+	d.setConstraints(r.Constraints())
+	return d
 }
 
 // MatchRule represents legacy rule structure
@@ -162,10 +715,37 @@ type JobSummary struct {
 
 // FeedCheckSummary is the summary stored for "feed_check" jobs.
 type FeedCheckSummary struct {
+	ItemsFound   int `json:"items_found"`
+	ItemsMatched int `json:"items_matched"`
+	ItemsScored  int `json:"items_scored"`
+	// FeedsNotModified counts feeds that answered 304 Not Modified to a
+	// conditional GET and were therefore skipped this run.
+	FeedsNotModified int `json:"feeds_not_modified,omitempty"`
+	// FeedsFailed counts feeds whose fetch or parse failed this run.
+	FeedsFailed int `json:"feeds_failed,omitempty"`
+	// FeedsBackedOff counts feeds skipped because they are in failure backoff.
+	FeedsBackedOff int `json:"feeds_backed_off,omitempty"`
+	// ItemsInspected counts matches whose .torrent was downloaded and decoded;
+	// ItemsFlagged counts those whose contents raised a TorrentFlag.
+	ItemsInspected int `json:"items_inspected,omitempty"`
+	ItemsFlagged   int `json:"items_flagged,omitempty"`
+	// ItemsUpgrades counts matches staged as upgrades of a queued episode;
+	// ItemsAlreadyQueued counts matches dropped because their episode was
+	// already queued and the upgrade policy did not take them.
+	ItemsUpgrades      int               `json:"items_upgrades,omitempty"`
+	ItemsAlreadyQueued int               `json:"items_already_queued,omitempty"`
+	Feeds              []FeedCheckResult `json:"feeds,omitempty"`
+	ErrorMessage       string            `json:"error_message,omitempty"`
+}
+
+// FeedCheckResult is the per-feed outcome recorded in a FeedCheckSummary.
+type FeedCheckResult struct {
+	URL          string `json:"url"`
+	Status       string `json:"status"` // "ok", "not_modified", "failed", "backoff", "not_due"
 	ItemsFound   int    `json:"items_found"`
-	ItemsMatched int    `json:"items_matched"`
-	ItemsScored  int    `json:"items_scored"`
-	ErrorMessage string `json:"error_message,omitempty"`
+	ItemsMatched int    `json:"items_matched"` // before cross-feed dedup
+	LatencyMs    int64  `json:"latency_ms"`
+	Error        string `json:"error,omitempty"`
 }
 
 // RescoreSummary is the summary stored for "rescore" jobs.
@@ -200,6 +780,14 @@ type SuggestRefreshSummary struct {
 type WatchlistEnrichSummary struct {
 	ShowsUpdated  int    `json:"shows_updated"`
 	MoviesUpdated int    `json:"movies_updated"`
+	AliasesAdded  int    `json:"aliases_added"`
+	ErrorMessage  string `json:"error_message,omitempty"`
+}
+
+// WatchlistRetireSummary is the summary stored for "watchlist_retire" jobs.
+type WatchlistRetireSummary struct {
+	ShowsRetired  int    `json:"shows_retired"`
+	MoviesRetired int    `json:"movies_retired"`
 	ErrorMessage  string `json:"error_message,omitempty"`
 }
 
@@ -953,6 +953,11 @@ const app = createApp({
                 if ((s.items_found || 0) > 0) parts.push(`${s.items_found} found`);
                 if ((s.items_matched || 0) > 0) parts.push(`${s.items_matched} matched`);
                 if ((s.items_scored || 0) > 0) parts.push(`${s.items_scored} scored`);
+                if ((s.items_flagged || 0) > 0) parts.push(`${s.items_flagged} flagged`);
+                if ((s.items_upgrades || 0) > 0) parts.push(`${s.items_upgrades} upgrade${s.items_upgrades === 1 ? '' : 's'}`);
+                if ((s.feeds_not_modified || 0) > 0) parts.push(`${s.feeds_not_modified} unchanged`);
+                if ((s.feeds_failed || 0) > 0) parts.push(`${s.feeds_failed} feed${s.feeds_failed === 1 ? '' : 's'} failed`);
+                if ((s.feeds_backed_off || 0) > 0) parts.push(`${s.feeds_backed_off} backing off`);
                 return parts.length ? parts.join(' · ') : 'no new items';
             }
             if (job.type === 'rescore') {
@@ -965,6 +970,10 @@ const app = createApp({
                 const n = s.suggestions_generated;
                 return n != null ? `${n} suggestion${n === 1 ? '' : 's'} generated` : 'cache refreshed';
             }
+            if (job.type === 'watchlist_retire') {
+                const n = (s.shows_retired || 0) + (s.movies_retired || 0);
+                return n > 0 ? `${n} rule${n === 1 ? '' : 's'} archived` : 'nothing to retire';
+            }
             if (job.type === 'rematch') {
                 const parts = [];
                 const kept = s.items_matched || 0;
@@ -146,6 +146,18 @@
                                 <span class="fg-dim font-mono">match:</span>
                                 <span class="font-mono font-bold px-2 py-1 rounded text-xs badge-amber border" :title="torrent.match_confidence_reason">&#9888; low confidence</span>
                             </div>
+                            <div v-if="torrent.torrent && torrent.torrent.flags && torrent.torrent.flags.length" class="flex items-center justify-between">
+                                <span class="fg-dim font-mono">contents:</span>
+                                <span class="font-mono font-bold px-2 py-1 rounded text-xs bg-red-950/40 border border-red-800/50 text-red-400">&#9888; {{ torrent.torrent.flags.join(', ').replace('_', ' ') }}</span>
+                            </div>
+                            <div v-if="torrent.upgrade_of" class="flex items-center justify-between">
+                                <span class="fg-dim font-mono">upgrade:</span>
+                                <span class="font-mono font-bold px-2 py-1 rounded text-xs badge-amber border" :title="torrent.match_reason">&#8593; replaces #{{ torrent.upgrade_of }}<template v-if="torrent.revision"> (rev {{ torrent.revision }})</template></span>
+                            </div>
+                            <div v-if="torrent.superseded_by" class="flex items-center justify-between">
+                                <span class="fg-dim font-mono">upgrade:</span>
+                                <span class="font-mono font-bold px-2 py-1 rounded text-xs badge-amber border">&#8595; superseded by #{{ torrent.superseded_by }}</span>
+                            </div>
                             <!-- Failure reason banner -->
                             <div v-if="torrent.status === 'failed' && torrent.fail_reason" class="mt-3 p-2 rounded bg-red-950/40 border border-red-800/50">
                                 <span class="text-xs font-mono text-red-400 break-words">&#9888; {{ torrent.fail_reason }}</span>
@@ -197,6 +209,55 @@
                                     <span v-if="torrent.release_year" class="fg-dim">year</span>
                                     <span v-if="torrent.release_year" class="fg-soft">{{ torrent.release_year }}</span>
 
+                                    <template v-if="torrent.audio && torrent.audio.length">
+                                        <span class="fg-dim">audio</span>
+                                        <span class="fg-soft">{{ torrent.audio.join(' + ') }}<span v-if="torrent.audio_channels"> {{ torrent.audio_channels }}</span></span>
+                                    </template>
+
+                                    <span v-if="torrent.bit_depth" class="fg-dim">bit depth</span>
+                                    <span v-if="torrent.bit_depth" class="fg-soft">{{ torrent.bit_depth }}-bit</span>
+
+                                    <span v-if="torrent.edition" class="fg-dim">edition</span>
+                                    <span v-if="torrent.edition" class="fg-soft">{{ torrent.edition.replace('_', ' ') }}</span>
+
+                                    <template v-if="(torrent.languages && torrent.languages.length) || torrent.subbed || torrent.dubbed">
+                                        <span class="fg-dim">language</span>
+                                        <span class="fg-soft">{{ [...(torrent.languages || []), torrent.subbed ? 'subbed' : '', torrent.dubbed ? 'dubbed' : ''].filter(Boolean).join(' · ') }}</span>
+                                    </template>
+
+                                    <template v-if="torrent.seeders != null">
+                                        <span class="fg-dim">swarm</span>
+                                        <span :class="torrent.seeders > 0 ? 'fg-soft' : 'text-red-400'">{{ torrent.seeders }} seeders<span v-if="torrent.peers != null"> / {{ torrent.peers }} peers</span></span>
+                                    </template>
+
+                                    <template v-if="torrent.download_volume_factor === 0">
+                                        <span class="fg-dim">freeleech</span>
+                                        <span class="badge-emerald border px-1.5 py-0.5 rounded w-fit font-bold">yes</span>
+                                    </template>
+
+                                    <span v-if="torrent.info_hash" class="fg-dim">info hash</span>
+                                    <span v-if="torrent.info_hash" class="fg-soft break-all">{{ torrent.info_hash }}</span>
+
+                                    <template v-if="torrent.torrent">
+                                        <span class="fg-dim">contents</span>
+                                        <span class="fg-soft">
+                                            {{ torrent.torrent.file_count }} file{{ torrent.torrent.file_count === 1 ? '' : 's' }} · {{ formatSize(torrent.torrent.total_size) }}
+                                            <span v-for="flag in (torrent.torrent.flags || [])" :key="flag" class="ml-1 px-1.5 py-0.5 rounded border border-red-800/50 text-red-400">{{ flag.replace('_', ' ') }}</span>
+                                        </span>
+                                        <span class="fg-dim">files</span>
+                                        <span class="fg-soft break-all">
+                                            <span v-for="f in (torrent.torrent.files || []).slice(0, 20)" :key="f.path" class="block">{{ f.path }} <span class="fg-dim">({{ formatSize(f.size) }})</span></span>
+                                            <span v-if="torrent.torrent.file_count > 20" class="block fg-dim">… {{ torrent.torrent.file_count - 20 }} more</span>
+                                        </span>
+                                    </template>
+
+                                    <span v-if="torrent.imdb_id || torrent.tvdb_id" class="fg-dim">ids</span>
+                                    <span v-if="torrent.imdb_id || torrent.tvdb_id" class="fg-soft">
+                                        <span v-if="torrent.imdb_id">imdb {{ torrent.imdb_id }}</span>
+                                        <span v-if="torrent.imdb_id && torrent.tvdb_id"> · </span>
+                                        <span v-if="torrent.tvdb_id">tvdb {{ torrent.tvdb_id }}</span>
+                                    </span>
+
                                     <span class="fg-dim">published</span>
                                     <span class="fg-soft">{{ fmtDate(torrent.pub_date) }}</span>
 
@@ -47,6 +47,7 @@ const settingsApp = createApp({
                 feed_check_interval_secs: 300,
                 feed_check_enabled: true,
                 rescore_backfill_enabled: false,
+                inspect_torrents: false,
             },
             auto_queue: {
                 enabled: false,
@@ -55,6 +56,7 @@ const settingsApp = createApp({
                 interval_secs: 600,
                 hold_mins: 30,
                 max_hold_mins: 480,
+                upgrades: false,
                 dry_run: false,
             },
             alerts: {
@@ -66,6 +68,11 @@ const settingsApp = createApp({
                 preferred_codec: '',
                 exclude_groups: [],
                 preferred_groups: [],
+                max_quality: '',
+                min_size: 0,
+                max_size: 0,
+                allowed_sources: [],
+                excluded_sources: [],
             },
             auth: {
                 username: '',
@@ -76,6 +83,8 @@ const settingsApp = createApp({
         // Comma-separated text inputs for array fields
         const preferredGroupsInput = ref('');
         const excludeGroupsInput   = ref('');
+        const allowedSourcesInput  = ref('');
+        const excludedSourcesInput = ref('');
         // Separate password input so we can send sentinel when blank
         const passwordInput = ref('');
 
@@ -102,6 +111,7 @@ const settingsApp = createApp({
                 form.scheduler.feed_check_interval_secs  = data.scheduler.feed_check_interval_secs  ?? 300;
                 form.scheduler.feed_check_enabled        = data.scheduler.feed_check_enabled        ?? true;
                 form.scheduler.rescore_backfill_enabled  = data.scheduler.rescore_backfill_enabled  ?? false;
+                form.scheduler.inspect_torrents          = data.scheduler.inspect_torrents          ?? false;
             }
             // auto_queue
             if (data.auto_queue) {
@@ -111,6 +121,7 @@ const settingsApp = createApp({
                 form.auto_queue.interval_secs  = data.auto_queue.interval_secs  ?? 600;
                 form.auto_queue.hold_mins      = data.auto_queue.hold_mins      ?? 30;
                 form.auto_queue.max_hold_mins  = data.auto_queue.max_hold_mins  ?? 480;
+                form.auto_queue.upgrades       = data.auto_queue.upgrades       ?? false;
                 form.auto_queue.dry_run        = data.auto_queue.dry_run        ?? false;
             }
             // alerts
@@ -126,6 +137,13 @@ const settingsApp = createApp({
                 form.match.preferred_groups  = data.match.preferred_groups  ?? [];
                 preferredGroupsInput.value   = (form.match.preferred_groups).join(', ');
                 excludeGroupsInput.value     = (form.match.exclude_groups).join(', ');
+                form.match.max_quality       = data.match.max_quality       ?? '';
+                form.match.min_size          = data.match.min_size          ?? 0;
+                form.match.max_size          = data.match.max_size          ?? 0;
+                form.match.allowed_sources   = data.match.allowed_sources   ?? [];
+                form.match.excluded_sources  = data.match.excluded_sources  ?? [];
+                allowedSourcesInput.value    = (form.match.allowed_sources).join(', ');
+                excludedSourcesInput.value   = (form.match.excluded_sources).join(', ');
             }
             // auth — password always masked server-side
             if (data.auth) {
@@ -158,6 +176,8 @@ const settingsApp = createApp({
             if (section === 'match') {
                 form.match.preferred_groups = parseCSV(preferredGroupsInput.value);
                 form.match.exclude_groups   = parseCSV(excludeGroupsInput.value);
+                form.match.allowed_sources  = parseCSV(allowedSourcesInput.value);
+                form.match.excluded_sources = parseCSV(excludedSourcesInput.value);
             }
 
             // Build patch payload — send only the section being saved so we
@@ -657,6 +677,8 @@ const settingsApp = createApp({
             form,
             preferredGroupsInput,
             excludeGroupsInput,
+            allowedSourcesInput,
+            excludedSourcesInput,
             passwordInput,
             toast,
             save,